@@ -0,0 +1,200 @@
+package geodesic
+
+import (
+	"math"
+
+	"github.com/twpayne/go-geom"
+)
+
+// The Densify functions in this file add vertices to each segment of a
+// LineString so that the segment follows a curve on the surface of the earth
+// rather than a straight line in lon/lat coordinates, which is useful before
+// drawing or reprojecting long segments. Each segment is divided into the
+// smallest number of equal parts that are no longer than the maximum. The
+// ordinates other than the longitude and latitude, such as Z and M, are
+// interpolated linearly along each segment. The result has the layout and
+// SRID of the LineString and contains all of its vertices. If the maximum is
+// not positive then a clone of the LineString is returned.
+//
+// The longitudes of the added vertices are continuous with the vertex before
+// them, so they may be outside the range [-180, 180]. A LineString whose
+// longitudes are continuous, such as one from longitude 170 to 190, gives a
+// continuous result, from 170 through 180 to 190. If the longitudes of the
+// LineString jump by 360 degrees, such as from 170 to -170, then the result
+// jumps at the same vertex, from 180 or beyond to -170.
+
+// Densify returns ls with vertices added along the geodesics between its
+// vertices so that no segment is longer than maxSegmentLength.
+func (e *Ellipsoid) Densify(ls *geom.LineString, maxSegmentLength float64) *geom.LineString {
+	if !(maxSegmentLength > 0) {
+		return ls.Clone()
+	}
+	return densify(ls, func(lat1, lon1, lat2, lon2 float64) (int, func(float64) (float64, float64)) {
+		r := e.inverse(lat1, lon1, lat2, lon2, false)
+		l := e.newLine(lat1, lon1, r.salp1, r.calp1)
+		return int(math.Ceil(r.s12 / maxSegmentLength)), func(f float64) (float64, float64) {
+			lat, lon, _ := l.position(f * r.s12)
+			return lat, lon
+		}
+	})
+}
+
+// DensifyGreatCircle returns ls with vertices added along the great circles
+// between its vertices on a sphere so that no segment subtends an angle
+// greater than maxAngle degrees at the center of the sphere. A maximum
+// length s on a sphere of radius r is a maximum angle of s/r radians.
+func DensifyGreatCircle(ls *geom.LineString, maxAngle float64) *geom.LineString {
+	if !(maxAngle > 0) {
+		return ls.Clone()
+	}
+	return densify(ls, func(lat1, lon1, lat2, lon2 float64) (int, func(float64) (float64, float64)) {
+		angle, interpolate := greatCircle(lat1, lon1, lat2, lon2)
+		return int(math.Ceil(angle / maxAngle)), interpolate
+	})
+}
+
+// DensifyRhumbLine returns ls with vertices added along the rhumb lines
+// between its vertices on a sphere so that no segment subtends an angle
+// greater than maxAngle degrees at the center of the sphere. Rhumb lines
+// are straight lines in the Mercator projection.
+func DensifyRhumbLine(ls *geom.LineString, maxAngle float64) *geom.LineString {
+	if !(maxAngle > 0) {
+		return ls.Clone()
+	}
+	return densify(ls, func(lat1, lon1, lat2, lon2 float64) (int, func(float64) (float64, float64)) {
+		angle, interpolate := rhumbLine(lat1, lon1, lat2, lon2)
+		return int(math.Ceil(angle / maxAngle)), interpolate
+	})
+}
+
+// Interpolate returns the point at fraction f of the way along the shortest
+// geodesic from (lat1, lon1) to (lat2, lon2). lon is in the range [-180,
+// 180].
+func (e *Ellipsoid) Interpolate(lat1, lon1, lat2, lon2, f float64) (lat, lon float64) {
+	r := e.inverse(lat1, lon1, lat2, lon2, false)
+	lat, lon, _ = e.newLine(lat1, lon1, r.salp1, r.calp1).position(f * r.s12)
+	return lat, lon
+}
+
+// InterpolateGreatCircle returns the point at fraction f of the way along
+// the shorter great circle arc from (lat1, lon1) to (lat2, lon2) on a
+// sphere. lon is in the range [-180, 180].
+func InterpolateGreatCircle(lat1, lon1, lat2, lon2, f float64) (lat, lon float64) {
+	_, interpolate := greatCircle(lat1, lon1, lat2, lon2)
+	return interpolate(f)
+}
+
+// InterpolateRhumbLine returns the point at fraction f of the way along the
+// shorter rhumb line from (lat1, lon1) to (lat2, lon2) on a sphere. lon is in
+// the range [-180, 180].
+func InterpolateRhumbLine(lat1, lon1, lat2, lon2, f float64) (lat, lon float64) {
+	_, interpolate := rhumbLine(lat1, lon1, lat2, lon2)
+	return interpolate(f)
+}
+
+// A segmentFunc returns the number of parts to divide the segment from
+// (lat1, lon1) to (lat2, lon2) into and a function that returns the point at
+// a fraction of the way along it.
+type segmentFunc func(lat1, lon1, lat2, lon2 float64) (int, func(float64) (float64, float64))
+
+// densify returns ls with each segment divided into parts by segment.
+func densify(ls *geom.LineString, segment segmentFunc) *geom.LineString {
+	flatCoords, stride := ls.FlatCoords(), ls.Stride()
+	var result []float64
+	if len(flatCoords) != 0 {
+		result = append(result, flatCoords[:stride]...)
+	}
+	for i := stride; i < len(flatCoords); i += stride {
+		c0, c1 := flatCoords[i-stride:i], flatCoords[i:i+stride]
+		n, interpolate := segment(c0[1], c0[0], c1[1], c1[0])
+		prevLon := c0[0]
+		for k := 1; k < n; k++ {
+			f := float64(k) / float64(n)
+			lat, lon := interpolate(f)
+			// Keep the longitude continuous with the previous vertex rather
+			// than in the range [-180, 180].
+			lon = prevLon + angNormalize(lon-prevLon)
+			prevLon = lon
+			result = append(result, lon, lat)
+			for j := 2; j < stride; j++ {
+				result = append(result, c0[j]+f*(c1[j]-c0[j]))
+			}
+		}
+		result = append(result, c1...)
+	}
+	return geom.NewLineStringFlat(ls.Layout(), result).SetSRID(ls.SRID())
+}
+
+// greatCircle returns the angle in degrees subtended by the shorter great
+// circle arc from (lat1, lon1) to (lat2, lon2) and a function that returns
+// the point at a fraction of the way along it. The arc between antipodal
+// points is not unique, and the one that starts northwards is used.
+func greatCircle(lat1, lon1, lat2, lon2 float64) (float64, func(float64) (float64, float64)) {
+	sphi1, cphi1 := sincosd(lat1)
+	sphi2, cphi2 := sincosd(lat2)
+	lon12, _ := angDiff(lon1, lon2)
+	slam12, clam12 := sincosd(lon12)
+	// (y, x) are proportional to the sine and cosine of the initial azimuth.
+	y := cphi2 * slam12
+	x := cphi1*sphi2 - sphi1*cphi2*clam12
+	angle := atan2d(math.Hypot(x, y), sphi1*sphi2+cphi1*cphi2*clam12)
+	salp1, calp1 := sincosd(atan2d(y, x))
+	return angle, func(f float64) (float64, float64) {
+		// (x, y, z) is the point in a frame with (lat1, lon1) on the x axis
+		// and the z axis through the north pole.
+		ssig, csig := sincosd(f * angle)
+		x := cphi1*csig - sphi1*ssig*calp1
+		y := salp1 * ssig
+		z := sphi1*csig + cphi1*ssig*calp1
+		return atan2d(z, math.Hypot(x, y)), angNormalize(lon1 + atan2d(y, x))
+	}
+}
+
+// rhumbLine returns the angle in degrees subtended by the shorter rhumb line
+// from (lat1, lon1) to (lat2, lon2) and a function that returns the point at
+// a fraction of the way along it. The latitude changes linearly along a rhumb
+// line and the longitude changes linearly with the isometric latitude. A
+// rhumb line to or from a pole is a meridian.
+func rhumbLine(lat1, lon1, lat2, lon2 float64) (float64, func(float64) (float64, float64)) {
+	lat12 := lat2 - lat1
+	lon12, _ := angDiff(lon1, lon2)
+	psi12 := isometricLatitudeDifference(lat1, lat2)
+	var angle float64
+	switch {
+	case math.IsInf(psi12, 0):
+		angle = math.Abs(lat12)
+	case lat12 == 0:
+		_, clat := sincosd(lat1)
+		angle = math.Abs(clat * lon12)
+	default:
+		// lat12 / psi12 is the mean cosine of the latitude.
+		angle = math.Hypot(lat12, lat12*degree/psi12*lon12)
+	}
+	return angle, func(f float64) (float64, float64) {
+		lat := lat1 + f*lat12
+		switch {
+		case math.Abs(lat1) == 90:
+			return lat, lon2
+		case math.IsInf(psi12, 0):
+			return lat, lon1
+		case lat12 == 0:
+			return lat, angNormalize(lon1 + f*lon12)
+		default:
+			return lat, angNormalize(lon1 + lon12*isometricLatitudeDifference(lat1, lat)/psi12)
+		}
+	}
+}
+
+// isometricLatitudeDifference returns the difference between the isometric
+// latitudes in radians of the latitudes lat1 and lat2 on a sphere, which is
+// infinite if either is a pole. It is computed without cancellation when lat1
+// and lat2 are close.
+func isometricLatitudeDifference(lat1, lat2 float64) float64 {
+	// The isometric latitude is atanh(sin(lat)), and atanh(a) - atanh(b) =
+	// atanh((a - b) / (1 - a*b)), where a - b and 1 - a*b can be written
+	// in terms of the half difference and the mean of the latitudes.
+	sd, _ := sincosd((lat2 - lat1) / 2)
+	_, cm := sincosd((lat1 + lat2) / 2)
+	// Clamp the argument, which may round to outside [-1, 1] at the poles.
+	return math.Atanh(max(-1, min(2*cm*sd/(sd*sd+cm*cm), 1)))
+}
@@ -0,0 +1,251 @@
+package geodesic_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+func TestInterpolateGreatCircle(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		f                      float64
+		lat, lon               float64
+	}{
+		{
+			name: "equator",
+			lon2: 90,
+			f:    0.5,
+			lon:  45,
+		},
+		{
+			name: "meridian",
+			lat2: 90,
+			f:    0.25,
+			lat:  22.5,
+		},
+		{
+			name: "oblique",
+			lat2: 45,
+			lon2: 90,
+			f:    0.5,
+			lat:  30,
+			lon:  35.264389682754654,
+		},
+		{
+			name: "jfk_lhr",
+			lat1: 40.6,
+			lon1: -73.8,
+			lat2: 51.6,
+			lon2: -0.5,
+			f:    0.25,
+			lat:  47.569801572257816,
+			lon:  -59.38811584430602,
+		},
+		{
+			name: "antimeridian",
+			lat1: -33.9,
+			lon1: 151.2,
+			lat2: 37.6,
+			lon2: -122.4,
+			f:    0.5,
+			lat:  2.536451165567127,
+			lon:  -166.8508883250715,
+		},
+		{
+			name: "start",
+			lat1: 10,
+			lon1: 20,
+			lat2: 30,
+			lon2: 40,
+			lat:  10,
+			lon:  20,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lat, lon := geodesic.InterpolateGreatCircle(tc.lat1, tc.lon1, tc.lat2, tc.lon2, tc.f)
+			assertInDelta(t, tc.lat, lat, 1e-12)
+			assertInDelta(t, tc.lon, lon, 1e-12)
+		})
+	}
+}
+
+func TestInterpolateRhumbLine(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		f                      float64
+		lat, lon               float64
+	}{
+		{
+			name: "parallel",
+			lat1: 60,
+			lat2: 60,
+			lon2: 90,
+			f:    0.5,
+			lat:  60,
+			lon:  45,
+		},
+		{
+			name: "oblique",
+			lat1: 10,
+			lat2: 60,
+			lon2: 100,
+			f:    0.5,
+			lat:  35,
+			lon:  41.82193069779148,
+		},
+		{
+			name: "antimeridian",
+			lat1: 10,
+			lon1: 170,
+			lat2: 40,
+			lon2: -170,
+			f:    0.5,
+			lat:  25,
+			lon:  179.37726248078422,
+		},
+		{
+			name: "to_pole",
+			lon1: 10,
+			lat2: 90,
+			lon2: 50,
+			f:    0.5,
+			lat:  45,
+			lon:  10,
+		},
+		{
+			name: "from_pole",
+			lat1: -90,
+			lon1: 10,
+			lon2: 50,
+			f:    0.5,
+			lat:  -45,
+			lon:  50,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lat, lon := geodesic.InterpolateRhumbLine(tc.lat1, tc.lon1, tc.lat2, tc.lon2, tc.f)
+			assertInDelta(t, tc.lat, lat, 1e-12)
+			assertInDelta(t, tc.lon, lon, 1e-12)
+		})
+	}
+}
+
+func TestEllipsoidInterpolate(t *testing.T) {
+	lat1, lon1, lat2, lon2 := 40.6, -73.8, 51.6, -0.5
+	s12, _, _ := geodesic.WGS84.Inverse(lat1, lon1, lat2, lon2)
+	for _, f := range []float64{0, 0.25, 0.5, 1} {
+		lat, lon := geodesic.WGS84.Interpolate(lat1, lon1, lat2, lon2, f)
+		s1, _, _ := geodesic.WGS84.Inverse(lat1, lon1, lat, lon)
+		s2, _, _ := geodesic.WGS84.Inverse(lat, lon, lat2, lon2)
+		assertInDelta(t, f*s12, s1, 1e-6)
+		assertInDelta(t, (1-f)*s12, s2, 1e-6)
+	}
+}
+
+func TestEllipsoidDensify(t *testing.T) {
+	ls := geom.NewLineStringFlat(geom.XYZ, []float64{
+		-73.8, 40.6, 0,
+		-0.5, 51.6, 1000,
+		-0.5, 51.7, 2000,
+	}).SetSRID(4326)
+	actual := geodesic.WGS84.Densify(ls, 1000e3)
+	assert.Equal(t, geom.XYZ, actual.Layout())
+	assert.Equal(t, 4326, actual.SRID())
+	// The first segment is 5551.759km long, so it is divided into six parts.
+	assert.Equal(t, 8, actual.NumCoords())
+	assert.Equal(t, ls.Coord(0), actual.Coord(0))
+	assert.Equal(t, ls.Coord(1), actual.Coord(6))
+	assert.Equal(t, ls.Coord(2), actual.Coord(7))
+	s12, _, _ := geodesic.WGS84.Inverse(40.6, -73.8, 51.6, -0.5)
+	for i := 1; i <= 6; i++ {
+		c0, c1 := actual.Coord(i-1), actual.Coord(i)
+		s, _, _ := geodesic.WGS84.Inverse(c0.Y(), c0.X(), c1.Y(), c1.X())
+		assertInDelta(t, s12/6, s, 1e-6)
+		assertInDelta(t, 1000*float64(i)/6, c1[2], 1e-9)
+	}
+}
+
+func TestEllipsoidDensifyAntimeridian(t *testing.T) {
+	ls := geom.NewLineStringFlat(geom.XY, []float64{170, -10, 190, 10})
+	actual := geodesic.WGS84.Densify(ls, 500e3)
+	assert.True(t, actual.NumCoords() > 2)
+	for i := 1; i < actual.NumCoords(); i++ {
+		lon0, lon1 := actual.Coord(i-1).X(), actual.Coord(i).X()
+		if !(lon0 < lon1 && lon1 <= 190) {
+			t.Errorf("expected longitudes to increase continuously to 190, got %v then %v", lon0, lon1)
+		}
+	}
+}
+
+func TestDensifyGreatCircle(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		ls       *geom.LineString
+		maxAngle float64
+		expected *geom.LineString
+	}{
+		{
+			name:     "empty",
+			ls:       geom.NewLineString(geom.XY),
+			maxAngle: 1,
+			expected: geom.NewLineString(geom.XY),
+		},
+		{
+			name:     "equator",
+			ls:       geom.NewLineStringFlat(geom.XYM, []float64{0, 0, 0, 90, 0, 30}).SetSRID(4326),
+			maxAngle: 30,
+			expected: geom.NewLineStringFlat(geom.XYM, []float64{0, 0, 0, 30, 0, 10, 60, 0, 20, 90, 0, 30}).SetSRID(4326),
+		},
+		{
+			name:     "antimeridian",
+			ls:       geom.NewLineStringFlat(geom.XY, []float64{170, 0, -170, 0}),
+			maxAngle: 10,
+			expected: geom.NewLineStringFlat(geom.XY, []float64{170, 0, 180, 0, -170, 0}),
+		},
+		{
+			name:     "antimeridian_continuous",
+			ls:       geom.NewLineStringFlat(geom.XY, []float64{170, 0, 190, 0}),
+			maxAngle: 5,
+			expected: geom.NewLineStringFlat(geom.XY, []float64{170, 0, 175, 0, 180, 0, 185, 0, 190, 0}),
+		},
+		{
+			name:     "antimeridian_westwards",
+			ls:       geom.NewLineStringFlat(geom.XY, []float64{-170, 0, -190, 0}),
+			maxAngle: 5,
+			expected: geom.NewLineStringFlat(geom.XY, []float64{-170, 0, -175, 0, -180, 0, -185, 0, -190, 0}),
+		},
+		{
+			name:     "zero_max_angle",
+			ls:       geom.NewLineStringFlat(geom.XY, []float64{0, 0, 90, 0}),
+			expected: geom.NewLineStringFlat(geom.XY, []float64{0, 0, 90, 0}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := geodesic.DensifyGreatCircle(tc.ls, tc.maxAngle)
+			assert.Equal(t, tc.expected.Layout(), actual.Layout())
+			assert.Equal(t, tc.expected.SRID(), actual.SRID())
+			assert.Equal(t, len(tc.expected.FlatCoords()), len(actual.FlatCoords()))
+			for i, expected := range tc.expected.FlatCoords() {
+				assertInDelta(t, expected, actual.FlatCoords()[i], 1e-12)
+			}
+		})
+	}
+}
+
+func TestDensifyRhumbLine(t *testing.T) {
+	ls := geom.NewLineStringFlat(geom.XY, []float64{0, 10, 100, 60})
+	// The rhumb line subtends 91.346°, so it is divided into ten parts.
+	actual := geodesic.DensifyRhumbLine(ls, 10)
+	assert.Equal(t, 11, actual.NumCoords())
+	for i := range 11 {
+		c := actual.Coord(i)
+		assertInDelta(t, 10+5*float64(i), c.Y(), 1e-12)
+	}
+	assertInDelta(t, 41.82193069779148, actual.Coord(5).X(), 1e-12)
+}
@@ -0,0 +1,652 @@
+// Package geodesic implements geodesic calculations on an ellipsoid of
+// revolution, such as the WGS84 ellipsoid, using the algorithms of C. F. F.
+// Karney, Algorithms for geodesics, J. Geodesy 87, 43-55 (2013),
+// https://doi.org/10.1007/s00190-012-0578-z. These are accurate to round-off
+// and always converge.
+//
+// Latitudes, longitudes, and azimuths are in degrees and distances are in
+// the units of the equatorial radius of the ellipsoid, which is meters for
+// WGS84. Azimuths are measured clockwise from north. Geometries have the
+// longitude as their x ordinate and the latitude as their y ordinate, as
+// with SRID 4326.
+package geodesic
+
+// Ported from GeographicLib, https://geographiclib.sourceforge.io/.
+// Original license:
+//
+// The MIT License (MIT)
+//
+// Copyright (c) 2008-2024, Charles Karney
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+import "math"
+
+// WGS84 is the WGS84 ellipsoid.
+var WGS84 = NewEllipsoid(6378137, 1/298.257223563)
+
+// An Ellipsoid is an ellipsoid of revolution.
+type Ellipsoid struct {
+	a, f, f1, e2, ep2, n, b, c2, etol2 float64
+	a3x, c3x, c4x                      []float64
+}
+
+// NewEllipsoid returns a new Ellipsoid with equatorial radius a and
+// flattening f. f is zero for a sphere and negative for a prolate
+// ellipsoid.
+func NewEllipsoid(a, f float64) *Ellipsoid {
+	e := &Ellipsoid{
+		a:  a,
+		f:  f,
+		f1: 1 - f,
+		e2: f * (2 - f),
+		n:  f / (2 - f),
+		b:  a * (1 - f),
+	}
+	e.ep2 = e.e2 / (e.f1 * e.f1)
+	// c2 is the authalic radius squared.
+	switch {
+	case e.e2 == 0:
+		e.c2 = (a*a + e.b*e.b) / 2
+	case e.e2 > 0:
+		e.c2 = (a*a + e.b*e.b*math.Atanh(math.Sqrt(e.e2))/math.Sqrt(e.e2)) / 2
+	default:
+		e.c2 = (a*a + e.b*e.b*math.Atan(math.Sqrt(-e.e2))/math.Sqrt(-e.e2)) / 2
+	}
+	e.etol2 = 0.1 * tol2 / math.Sqrt(max(0.001, math.Abs(f))*min(1, 1-f/2)/2)
+	e.a3x = a3coeff(e.n)
+	e.c3x = c3coeff(e.n)
+	e.c4x = c4coeff(e.n)
+	return e
+}
+
+// EquatorialRadius returns the equatorial radius of e.
+func (e *Ellipsoid) EquatorialRadius() float64 {
+	return e.a
+}
+
+// Flattening returns the flattening of e.
+func (e *Ellipsoid) Flattening() float64 {
+	return e.f
+}
+
+// SurfaceArea returns the total surface area of e.
+func (e *Ellipsoid) SurfaceArea() float64 {
+	return 4 * math.Pi * e.c2
+}
+
+// Inverse solves the inverse geodesic problem. It returns the length s12 of
+// the shortest geodesic from (lat1, lon1) to (lat2, lon2), and the azimuths
+// azi1 and azi2 of the geodesic at each point.
+func (e *Ellipsoid) Inverse(lat1, lon1, lat2, lon2 float64) (s12, azi1, azi2 float64) {
+	r := e.inverse(lat1, lon1, lat2, lon2, false)
+	return r.s12, atan2d(r.salp1, r.calp1), atan2d(r.salp2, r.calp2)
+}
+
+// Direct solves the direct geodesic problem. It returns the point (lat2,
+// lon2) at distance s12 from (lat1, lon1) along the geodesic with azimuth
+// azi1, and the azimuth azi2 of the geodesic at that point. lon2 is in the
+// range [-180, 180].
+func (e *Ellipsoid) Direct(lat1, lon1, azi1, s12 float64) (lat2, lon2, azi2 float64) {
+	azi1 = angNormalize(azi1)
+	salp1, calp1 := sincosd(angRound(azi1))
+	l := e.newLine(lat1, lon1, salp1, calp1)
+	return l.position(s12)
+}
+
+// An inverseResult is the result of the inverse problem: the distance, the
+// sines and cosines of the azimuths at each point, and the area between the
+// geodesic and the equator.
+type inverseResult struct {
+	s12, salp1, calp1, salp2, calp2, area float64
+}
+
+// inverse solves the inverse geodesic problem, and computes the area between
+// the geodesic and the equator if area is true.
+func (e *Ellipsoid) inverse(lat1, lon1, lat2, lon2 float64, area bool) inverseResult {
+	var ca [nC]float64
+
+	// Compute the longitude difference carefully and make it positive.
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := 1.0
+	if lon12 < 0 {
+		lonsign = -1
+	}
+	// If very close to being on the same half-meridian, then make it so.
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := lon12 * degree
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	// If really close to the equator, treat as on equator.
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+	// Swap points so that the point with the higher absolute latitude is
+	// point 1. If one latitude is NaN then it becomes lat1.
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) || math.IsNaN(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+	// Make lat1 <= -0.
+	latsign := -1.0
+	if math.Signbit(lat1) {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+	// Now 0 <= lon12 <= 180, -90 <= lat1 <= -0, and lat1 <= lat2 <= -lat1.
+	// lonsign, swapp, and latsign record the transformation to this
+	// canonical form.
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1, cbet1 = norm2(e.f1*sbet1, cbet1)
+	cbet1 = max(tiny, cbet1)
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2, cbet2 = norm2(e.f1*sbet2, cbet2)
+	cbet2 = max(tiny, cbet2)
+
+	// If cbet1 < -sbet1 then cbet2 - cbet1 is a sensitive measure of |bet1|
+	// - |bet2|, otherwise |sbet2| + sbet1 is. When these vanish, force bet2
+	// = +/- bet1 exactly.
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + e.ep2*sbet1*sbet1)
+	dn2 := math.Sqrt(1 + e.ep2*sbet2*sbet2)
+
+	var s12x, m12x, sig12, salp1, calp1, salp2, calp2 float64
+	// somg12 > 1 marks that it needs to be calculated.
+	omg12, somg12, comg12 := 0.0, 2.0, 0.0
+
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		// The endpoints are on a single full meridian, so the geodesic
+		// might lie on a meridian. Head to the target longitude, and at the
+		// target head north.
+		calp1, salp1 = clam12, slam12
+		calp2, salp2 = 1, 0
+		// tan(bet) = tan(sig) * cos(alp)
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+		sig12 = math.Atan2(max(0, csig1*ssig2-ssig1*csig2)+0, csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _ = e.lengths(e.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2)
+		// A meridional geodesic with sig12 > pi/2 and m12 < 0 is not a
+		// shortest path, which happens for prolate ellipsoids near
+		// antipodal points.
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*tiny || (sig12 < tol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= e.b
+			s12x *= e.b
+		} else {
+			meridian = false
+		}
+	}
+
+	switch {
+	case meridian:
+	case sbet1 == 0 && (e.f <= 0 || lon12s >= e.f*180):
+		// The geodesic runs along the equator.
+		calp1, calp2, salp1, salp2 = 0, 0, 1, 1
+		s12x = e.a * lam12
+		sig12 = lam12 / e.f1
+		omg12 = sig12
+	default:
+		// The points are within a hemisphere bounded by a meridian and the
+		// geodesic is neither meridional nor equatorial.
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = e.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12)
+		if sig12 >= 0 {
+			// Short lines.
+			s12x = sig12 * e.b * dnm
+			omg12 = lam12 / (e.f1 * dnm)
+			break
+		}
+
+		// Newton's method to solve lambda12(alp1) - lam12 = 0. The root is
+		// bracketed by (alp1a, alp1b), which is shrunk with each evaluation.
+		// If the derivative is not positive or the Newton step leaves the
+		// bracket then the midpoint of the bracket is used instead.
+		var ssig1, csig1, ssig2, csig2, eps, domg12 float64
+		salp1a, calp1a, salp1b, calp1b := tiny, 1.0, tiny, -1.0
+		tripn, tripb := false, false
+		const maxit1 = 20
+		const maxit2 = maxit1 + 53 + 10
+		for numit := 0; ; numit++ {
+			var v, dv float64
+			v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dv = e.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12, numit < maxit1)
+			tol := tol0
+			if tripn {
+				tol *= 8
+			}
+			// The reversed test allows escape with NaNs.
+			if tripb || !(math.Abs(v) >= tol) || numit == maxit2 {
+				break
+			}
+			if v > 0 && (numit > maxit1 || calp1/salp1 > calp1b/salp1b) {
+				salp1b, calp1b = salp1, calp1
+			} else if v < 0 && (numit > maxit1 || calp1/salp1 < calp1a/salp1a) {
+				salp1a, calp1a = salp1, calp1
+			}
+			if numit < maxit1 && dv > 0 {
+				if dalp1 := -v / dv; math.Abs(dalp1) < math.Pi {
+					sdalp1, cdalp1 := math.Sincos(dalp1)
+					if nsalp1 := salp1*cdalp1 + calp1*sdalp1; nsalp1 > 0 {
+						calp1 = calp1*cdalp1 - salp1*sdalp1
+						salp1, calp1 = norm2(nsalp1, calp1)
+						// Convergence may not be quadratic if the slope
+						// tends to zero, so use conditions based on epsilon
+						// rather than sqrt(epsilon).
+						tripn = math.Abs(v) <= 16*tol0
+						continue
+					}
+				}
+			}
+			salp1, calp1 = norm2((salp1a+salp1b)/2, (calp1a+calp1b)/2)
+			tripn = false
+			tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < tolb || math.Abs(salp1-salp1b)+(calp1-calp1b) < tolb
+		}
+		s12x, _, _ = e.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2)
+		s12x *= e.b
+		// omg12 = lam12 - domg12
+		sdomg12, cdomg12 := math.Sincos(domg12)
+		somg12 = slam12*cdomg12 - clam12*sdomg12
+		comg12 = clam12*cdomg12 + slam12*sdomg12
+	}
+
+	r := inverseResult{s12: 0 + s12x}
+
+	if area {
+		// From lambda12: sin(alp1) * cos(bet1) = sin(alp0).
+		salp0 := salp1 * cbet1
+		calp0 := math.Hypot(calp1, salp1*sbet1)
+		if calp0 != 0 && salp0 != 0 {
+			// From lambda12: tan(bet) = tan(sig) * cos(alp).
+			ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+			ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+			k2 := calp0 * calp0 * e.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			a4 := e.a * e.a * calp0 * salp0 * e.e2
+			e.c4f(eps, ca[:])
+			b41 := sinCosSeries(false, ssig1, csig1, ca[:], nC4)
+			b42 := sinCosSeries(false, ssig2, csig2, ca[:], nC4)
+			r.area = a4 * (b42 - b41)
+		}
+		if !meridian && somg12 > 1 {
+			somg12, comg12 = math.Sincos(omg12)
+		}
+		var alp12 float64
+		if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+			// Use tan(Gamma/2) = tan(omg12/2) * (tan(bet1/2) + tan(bet2/2))
+			// / (1 + tan(bet1/2) * tan(bet2/2)) with tan(x/2) = sin(x) / (1
+			// + cos(x)).
+			domg12, dbet1, dbet2 := 1+comg12, 1+cbet1, 1+cbet2
+			alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+		} else {
+			// alp12 = alp2 - alp1, used in atan2 so no need to normalize.
+			salp12 := salp2*calp1 - calp2*salp1
+			calp12 := calp2*calp1 + salp2*salp1
+			if salp12 == 0 && calp12 < 0 {
+				salp12 = tiny * calp1
+				calp12 = -1
+			}
+			alp12 = math.Atan2(salp12, calp12)
+		}
+		r.area += e.c2 * alp12
+		r.area *= swapp * lonsign * latsign
+		r.area += 0
+	}
+
+	// Undo the transformation to the canonical form.
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+	}
+	r.salp1, r.calp1 = salp1*swapp*lonsign, calp1*swapp*latsign
+	r.salp2, r.calp2 = salp2*swapp*lonsign, calp2*swapp*latsign
+	return r
+}
+
+// lengths returns the distance s12b and reduced length m12b divided by b,
+// and the coefficient m0 of the secular term of the reduced length.
+func (e *Ellipsoid) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2 float64) (s12b, m12b, m0 float64) {
+	var ca, cb [nC]float64
+	a1 := a1m1f(eps)
+	c1f(eps, ca[:])
+	a2 := a2m1f(eps)
+	c2f(eps, cb[:])
+	m0 = a1 - a2
+	a1++
+	a2++
+	b1 := sinCosSeries(true, ssig2, csig2, ca[:], nC1) - sinCosSeries(true, ssig1, csig1, ca[:], nC1)
+	s12b = a1 * (sig12 + b1)
+	b2 := sinCosSeries(true, ssig2, csig2, cb[:], nC2) - sinCosSeries(true, ssig1, csig1, cb[:], nC2)
+	j12 := m0*sig12 + (a1*b1 - a2*b2)
+	// The parentheses ensure accurate cancellation for coincident points.
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+	return s12b, m12b, m0
+}
+
+// inverseStart returns a starting point for Newton's method in salp1 and
+// calp1, and a negative sig12. If Newton's method is not needed then it also
+// returns sig12, salp2, and calp2. dnm is only set for short lines.
+func (e *Ellipsoid) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12 float64) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+	sig12 = -1
+	// bet12 = bet2 - bet1 in [0, pi), bet12a = bet2 + bet1 in (-pi, 0].
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+		// sin((bet1+bet2)/2)^2 = (sbet1 + sbet2)^2 / ((sbet1 + sbet2)^2 +
+		// (cbet1 + cbet2)^2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + e.ep2*sbetm2)
+		omg12 := lam12 / (e.f1 * dnm)
+		somg12, comg12 = math.Sincos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	switch {
+	case shortline && ssig12 < e.etol2:
+		// Really short lines.
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*somg12*somg12/(1+comg12)
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	case math.Abs(e.n) > 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(e.n)*math.Pi*cbet1*cbet1:
+		// The zeroth order spherical approximation is good enough, or the
+		// ellipsoid is too eccentric for the astroid calculation.
+	default:
+		// Scale lam12 and bet2 to x, y coordinates where the antipodal
+		// point is at the origin and the singular point is at y = 0, x =
+		// -1.
+		var x, y, lamscale, betscale float64
+		lam12x := math.Atan2(-slam12, -clam12) // lam12 - pi
+		if e.f >= 0 {
+			// x = dlong, y = dlat
+			k2 := sbet1 * sbet1 * e.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			lamscale = e.f * cbet1 * e.a3f(eps) * math.Pi
+			betscale = lamscale * cbet1
+			x = lam12x / lamscale
+			y = sbet12a / betscale
+		} else {
+			// x = dlat, y = dlong
+			cbet12a := cbet2*cbet1 - sbet2*sbet1
+			bet12a := math.Atan2(sbet12a, cbet12a)
+			_, m12b, m0 := e.lengths(e.n, math.Pi+bet12a, sbet1, -cbet1, dn1, sbet2, cbet2, dn2)
+			x = -1 + m12b/(cbet1*cbet2*m0*math.Pi)
+			if x < -0.01 {
+				betscale = sbet12a / x
+			} else {
+				betscale = -e.f * cbet1 * cbet1 * math.Pi
+			}
+			lamscale = betscale / cbet1
+			y = lam12x / lamscale
+		}
+
+		if y > -tol1 && x > -1-xthresh {
+			// Strip near the cut.
+			if e.f >= 0 {
+				salp1 = min(1, -x)
+				calp1 = -math.Sqrt(1 - salp1*salp1)
+			} else {
+				if x > -tol1 {
+					calp1 = max(0, x)
+				} else {
+					calp1 = max(-1, x)
+				}
+				salp1 = math.Sqrt(1 - calp1*calp1)
+			}
+		} else {
+			// Estimate omg12 by solving the astroid problem, and use the
+			// spherical formula to compute alp1. omg12 is near pi, so
+			// estimate omg12a = pi - omg12.
+			k := astroid(x, y)
+			var omg12a float64
+			if e.f >= 0 {
+				omg12a = lamscale * (-x * k / (1 + k))
+			} else {
+				omg12a = lamscale * (-y * (1 + k) / k)
+			}
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+	// Sanity check the starting guess. The reversed test allows NaNs
+	// through.
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+// lambda12 returns the difference between the longitude difference of the
+// geodesic with azimuth alp1 at point 1 and the target longitude
+// difference, and, if diffp is true, its derivative with respect to alp1.
+func (e *Ellipsoid) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64, diffp bool) (
+	lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64,
+) {
+	var ca [nC]float64
+	if sbet1 == 0 && calp1 == 0 {
+		// Break the degeneracy of the equatorial line, which has already
+		// been handled.
+		calp1 = -tiny
+	}
+
+	// sin(alp1) * cos(bet1) = sin(alp0)
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	// tan(bet1) = tan(sig1) * cos(alp1)
+	// tan(omg1) = sin(alp0) * tan(sig1) = tan(omg1) = tan(alp1) * sin(bet1)
+	somg1 := salp0 * sbet1
+	comg1 := calp1 * cbet1
+	ssig1, csig1 = norm2(sbet1, comg1)
+
+	// Enforce symmetries in the case |bet2| = -bet1, which can otherwise
+	// give singularities in the Newton iteration.
+	// sin(alp2) * cos(bet2) = sin(alp0)
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+	// calp2 = sqrt(1 - sq(salp2)) = sqrt(sq(calp0) - sq(sbet2)) / cbet2,
+	// choosing the positive root to give alp2 in [0, pi/2].
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var d float64
+		if cbet1 < -sbet1 {
+			d = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			d = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt((calp1*cbet1)*(calp1*cbet1)+d) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+	// tan(bet2) = tan(sig2) * cos(alp2)
+	// tan(omg2) = sin(alp0) * tan(sig2)
+	somg2 := salp0 * sbet2
+	comg2 := calp2 * cbet2
+	ssig2, csig2 = norm2(sbet2, comg2)
+
+	// sig12 = sig2 - sig1, limited to [0, pi].
+	sig12 = math.Atan2(max(0, csig1*ssig2-ssig1*csig2)+0, csig1*csig2+ssig1*ssig2)
+	// omg12 = omg2 - omg1, limited to [0, pi].
+	somg12 := max(0, comg1*somg2-somg1*comg2) + 0
+	comg12 := comg1*comg2 + somg1*somg2
+	// eta = omg12 - lam120
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+	k2 := calp0 * calp0 * e.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	e.c3f(eps, ca[:])
+	b312 := sinCosSeries(true, ssig2, csig2, ca[:], nC3-1) - sinCosSeries(true, ssig1, csig1, ca[:], nC3-1)
+	domg12 = -e.f * e.a3f(eps) * salp0 * (sig12 + b312)
+	lam12 = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * e.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _ = e.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2)
+			dlam12 *= e.f1 / (calp2 * cbet2)
+		}
+	}
+	return lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12
+}
+
+// A line is a geodesic starting at a point with a given azimuth, for
+// solving direct problems.
+type line struct {
+	e                          *Ellipsoid
+	lon1                       float64
+	salp0, calp0, k2           float64
+	ssig1, csig1, somg1, comg1 float64
+	stau1, ctau1               float64
+	a1m1, b11, a3c, b31        float64
+	c1a, c1pa, c3a             [nC]float64
+}
+
+// newLine returns the geodesic line starting at (lat1, lon1) with azimuth
+// alp1.
+func (e *Ellipsoid) newLine(lat1, lon1, salp1, calp1 float64) *line {
+	l := &line{
+		e:    e,
+		lon1: lon1,
+	}
+	sbet1, cbet1 := sincosd(angRound(latFix(lat1)))
+	sbet1, cbet1 = norm2(e.f1*sbet1, cbet1)
+	cbet1 = max(tiny, cbet1)
+
+	// Evaluate alp0 from sin(alp1) * cos(bet1) = sin(alp0).
+	l.salp0 = salp1 * cbet1
+	l.calp0 = math.Hypot(calp1, salp1*sbet1)
+	// Evaluate sig with tan(bet1) = tan(sig1) * cos(alp1), where sig = 0 is
+	// the nearest northward crossing of the equator, and omg1 with
+	// tan(omg1) = sin(alp0) * tan(sig1).
+	l.ssig1 = sbet1
+	l.somg1 = l.salp0 * sbet1
+	if sbet1 != 0 || calp1 != 0 {
+		l.csig1 = cbet1 * calp1
+	} else {
+		l.csig1 = 1
+	}
+	l.comg1 = l.csig1
+	l.ssig1, l.csig1 = norm2(l.ssig1, l.csig1)
+
+	l.k2 = l.calp0 * l.calp0 * e.ep2
+	eps := l.k2 / (2*(1+math.Sqrt(1+l.k2)) + l.k2)
+
+	l.a1m1 = a1m1f(eps)
+	c1f(eps, l.c1a[:])
+	l.b11 = sinCosSeries(true, l.ssig1, l.csig1, l.c1a[:], nC1)
+	s, c := math.Sincos(l.b11)
+	// tau1 = sig1 + B11
+	l.stau1 = l.ssig1*c + l.csig1*s
+	l.ctau1 = l.csig1*c - l.ssig1*s
+
+	c1pf(eps, l.c1pa[:])
+
+	l.a3c = -e.f * l.salp0 * e.a3f(eps)
+	e.c3f(eps, l.c3a[:])
+	l.b31 = sinCosSeries(true, l.ssig1, l.csig1, l.c3a[:], nC3-1)
+	return l
+}
+
+// position returns the position and azimuth at distance s12 along l.
+func (l *line) position(s12 float64) (lat2, lon2, azi2 float64) {
+	e := l.e
+	tau12 := s12 / (e.b * (1 + l.a1m1))
+	s, c := math.Sincos(tau12)
+	// tau2 = tau1 + tau12
+	b12 := -sinCosSeries(true, l.stau1*c+l.ctau1*s, l.ctau1*c-l.stau1*s, l.c1pa[:], nC1p)
+	sig12 := tau12 - (b12 - l.b11)
+	ssig12, csig12 := math.Sincos(sig12)
+	if math.Abs(e.f) > 0.01 {
+		// The reverted distance series is inaccurate for |f| > 1/100, so
+		// correct sig12 with one Newton iteration.
+		ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+		csig2 := l.csig1*csig12 - l.ssig1*ssig12
+		b12 = sinCosSeries(true, ssig2, csig2, l.c1a[:], nC1)
+		serr := (1+l.a1m1)*(sig12+(b12-l.b11)) - s12/e.b
+		sig12 -= serr / math.Sqrt(1+l.k2*ssig2*ssig2)
+		ssig12, csig12 = math.Sincos(sig12)
+	}
+
+	// sig2 = sig1 + sig12
+	ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+	csig2 := l.csig1*csig12 - l.ssig1*ssig12
+	// sin(bet2) = cos(alp0) * sin(sig2)
+	sbet2 := l.calp0 * ssig2
+	cbet2 := math.Hypot(l.salp0, l.calp0*csig2)
+	if cbet2 == 0 {
+		// salp0 = 0 and csig2 = 0, so break the degeneracy.
+		cbet2 = tiny
+		csig2 = tiny
+	}
+	// tan(alp0) = cos(sig2) * tan(alp2)
+	salp2, calp2 := l.salp0, l.calp0*csig2
+
+	// tan(omg2) = sin(alp0) * tan(sig2)
+	somg2, comg2 := l.salp0*ssig2, csig2
+	// omg12 = omg2 - omg1
+	omg12 := math.Atan2(somg2*l.comg1-comg2*l.somg1, comg2*l.comg1+somg2*l.somg1)
+	lam12 := omg12 + l.a3c*(sig12+(sinCosSeries(true, ssig2, csig2, l.c3a[:], nC3-1)-l.b31))
+	lon12 := lam12 / degree
+	lon2 = angNormalize(angNormalize(l.lon1) + angNormalize(lon12))
+	lat2 = atan2d(sbet2, e.f1*cbet2)
+	azi2 = atan2d(salp2, calp2)
+	return lat2, lon2, azi2
+}
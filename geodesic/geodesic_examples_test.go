@@ -0,0 +1,45 @@
+package geodesic_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+func ExampleEllipsoid_Inverse() {
+	// From JFK airport to London Heathrow.
+	s12, azi1, azi2 := geodesic.WGS84.Inverse(40.6, -73.8, 51.6, -0.5)
+	fmt.Printf("%.3fkm %.6f° %.6f°\n", s12/1000, azi1, azi2)
+	// Output: 5551.759km 51.198883° 107.821777°
+}
+
+func ExampleEllipsoid_Length() {
+	track := geom.NewLineStringFlat(geom.XYZM, []float64{
+		6.0, 46.0, 1200, 0,
+		6.1, 46.1, 1500, 600,
+		6.3, 46.0, 1400, 1200,
+	}).SetSRID(4326)
+	length, err := geodesic.WGS84.Length(track)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%.1fkm\n", length/1000)
+	// Output: 32.6km
+}
+
+func ExampleDensifyGreatCircle() {
+	// A flight from Sydney to San Francisco, with a vertex at least every 30°.
+	// San Francisco's longitude of -122.4 is given as 237.6 so that the route
+	// crosses the antimeridian without jumping across the map.
+	route := geom.NewLineStringFlat(geom.XY, []float64{151.2, -33.9, 237.6, 37.6}).SetSRID(4326)
+	for _, c := range geodesic.DensifyGreatCircle(route, 30).Coords() {
+		fmt.Printf("%.1f %.1f\n", c.X(), c.Y())
+	}
+	// Output:
+	// 151.2 -33.9
+	// 174.2 -16.7
+	// 193.1 2.5
+	// 212.7 21.5
+	// 237.6 37.6
+}
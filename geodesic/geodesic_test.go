@@ -0,0 +1,162 @@
+package geodesic_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+func assertInDelta(t *testing.T, expected, actual, delta float64) {
+	t.Helper()
+	if math.Abs(expected-actual) > delta {
+		t.Errorf("expected %v ± %v but got %v", expected, delta, actual)
+	}
+}
+
+func TestInverse(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		ellipsoid              *geodesic.Ellipsoid
+		lat1, lon1, lat2, lon2 float64
+		s12, azi1, azi2        float64
+		s12Delta, aziDelta     float64
+	}{
+		{
+			name:      "jfk_lhr",
+			ellipsoid: geodesic.WGS84,
+			lat1:      40.6,
+			lon1:      -73.8,
+			lat2:      51.6,
+			lon2:      -0.5,
+			s12:       5551759.400318679,
+			azi1:      51.198882845579824,
+			azi2:      107.821776735514248,
+			s12Delta:  1e-8,
+			aziDelta:  1e-12,
+		},
+		{
+			name:      "jfk_cdg",
+			ellipsoid: geodesic.WGS84,
+			lat1:      40.6,
+			lon1:      -73.8,
+			lat2:      49.01666667,
+			lon2:      2.55,
+			s12:       5853226,
+			azi1:      53.47022,
+			azi2:      111.59367,
+			s12Delta:  0.5,
+			aziDelta:  0.5e-5,
+		},
+		{
+			name:      "nearly_antipodal_prolate",
+			ellipsoid: geodesic.NewEllipsoid(6.4e6, -1.0/150),
+			lat1:      0.07476,
+			lon1:      0,
+			lat2:      -0.07476,
+			lon2:      180,
+			s12:       20106193,
+			azi1:      90.00078,
+			azi2:      90.00078,
+			s12Delta:  0.5,
+			aziDelta:  0.5e-5,
+		},
+		{
+			name:      "nearly_antipodal_1",
+			ellipsoid: geodesic.WGS84,
+			lat1:      56.320923501171,
+			lon1:      0,
+			lat2:      -56.320923501171,
+			lon2:      179.664747671772880215,
+			s12:       19993558.287,
+			azi1:      math.NaN(),
+			s12Delta:  0.5e-3,
+		},
+		{
+			name:      "nearly_antipodal_2",
+			ellipsoid: geodesic.WGS84,
+			lat1:      48.522876735459,
+			lon1:      0,
+			lat2:      -48.52287673545898293,
+			lon2:      179.599720456223079643,
+			s12:       19989144.774,
+			azi1:      math.NaN(),
+			s12Delta:  0.5e-3,
+		},
+		{
+			name:      "quarter_meridian",
+			ellipsoid: geodesic.WGS84,
+			lat1:      0,
+			lon1:      0,
+			lat2:      90,
+			lon2:      0,
+			s12:       10001965.729312724,
+			azi1:      0,
+			azi2:      0,
+			s12Delta:  1e-8,
+			aziDelta:  0,
+		},
+		{
+			name:      "prolate",
+			ellipsoid: geodesic.NewEllipsoid(89.8, -1.83),
+			lat1:      0,
+			lon1:      0,
+			lat2:      -10,
+			lon2:      160,
+			s12:       266.7,
+			azi1:      120.27,
+			azi2:      105.15,
+			s12Delta:  1e-1,
+			aziDelta:  1e-2,
+		},
+		{
+			name:      "sphere",
+			ellipsoid: geodesic.NewEllipsoid(1, 0),
+			lat1:      0,
+			lon1:      0,
+			lat2:      0,
+			lon2:      90,
+			s12:       math.Pi / 2,
+			azi1:      90,
+			azi2:      90,
+			s12Delta:  1e-15,
+			aziDelta:  0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s12, azi1, azi2 := tc.ellipsoid.Inverse(tc.lat1, tc.lon1, tc.lat2, tc.lon2)
+			assertInDelta(t, tc.s12, s12, tc.s12Delta)
+			if !math.IsNaN(tc.azi1) {
+				assertInDelta(t, tc.azi1, azi1, tc.aziDelta)
+				assertInDelta(t, tc.azi2, azi2, tc.aziDelta)
+			}
+
+			// The direct problem should give the second point.
+			lat2, lon2, directAzi2 := tc.ellipsoid.Direct(tc.lat1, tc.lon1, azi1, s12)
+			assertInDelta(t, tc.lat2, lat2, 1e-9)
+			if math.Abs(tc.lat2) != 90 {
+				assertInDelta(t, 0, math.Remainder(tc.lon2-lon2, 360), 1e-9)
+				assertInDelta(t, azi2, directAzi2, 1e-9)
+			}
+		})
+	}
+}
+
+func TestDirect(t *testing.T) {
+	lat2, lon2, azi2 := geodesic.WGS84.Direct(40.63972222, -73.77888889, 53.5, 5850e3)
+	assertInDelta(t, 49.01467, lat2, 0.5e-5)
+	assertInDelta(t, 2.56106, lon2, 0.5e-5)
+	assertInDelta(t, 111.62947, azi2, 0.5e-5)
+
+	lat2, lon2, azi2 = geodesic.WGS84.Direct(0, 170, 90, 2*math.Pi*6378137/36)
+	assertInDelta(t, 0, lat2, 1e-12)
+	assertInDelta(t, 180, math.Abs(lon2), 1e-9)
+	assertInDelta(t, 90, azi2, 1e-12)
+}
+
+func TestSurfaceArea(t *testing.T) {
+	assertInDelta(t, 510065621724088.5, geodesic.WGS84.SurfaceArea(), 1)
+	assert.Equal(t, 4*math.Pi, geodesic.NewEllipsoid(1, 0).SurfaceArea())
+}
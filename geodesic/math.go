@@ -0,0 +1,225 @@
+package geodesic
+
+// Ported from GeographicLib, https://geographiclib.sourceforge.io/.
+// Original license:
+//
+// The MIT License (MIT)
+//
+// Copyright (c) 2008-2024, Charles Karney
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+import "math"
+
+const (
+	degree = math.Pi / 180
+	// tiny is the square root of the smallest normal float64.
+	tiny = 1.4916681462400413e-154
+	tol0 = 2.220446049250313e-16
+	tol1 = 200 * tol0
+)
+
+var (
+	tol2    = math.Sqrt(tol0)
+	tolb    = tol0 * tol2
+	xthresh = 1000 * tol2
+)
+
+// polyval evaluates the polynomial of degree n with coefficients p, highest
+// degree first, at x.
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+	y := p[0]
+	for _, c := range p[1 : n+1] {
+		y = y*x + c
+	}
+	return y
+}
+
+// sumx returns the sum of u and v and the round-off error of the sum.
+func sumx(u, v float64) (float64, float64) {
+	s := u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	return s, -(up + vpp)
+}
+
+// angNormalize reduces x to the range [-180, 180].
+func angNormalize(x float64) float64 {
+	y := math.Remainder(x, 360)
+	if math.Abs(y) == 180 {
+		return math.Copysign(180, x)
+	}
+	return y
+}
+
+// latFix returns NaN if x is not a valid latitude.
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+// angDiff returns the exact difference y - x reduced to the range [-180,
+// 180] and the error in the difference.
+func angDiff(x, y float64) (float64, float64) {
+	d, t := sumx(angNormalize(-x), angNormalize(y))
+	d = angNormalize(d)
+	if d == 180 && t > 0 {
+		d = -180
+	}
+	return sumx(d, t)
+}
+
+// angRound rounds tiny values of x so that small differences are handled
+// exactly.
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	y := math.Abs(x)
+	if w := z - y; w > 0 {
+		y = z - w
+	}
+	return math.Copysign(y, x)
+}
+
+// sincosd returns the sine and cosine of x degrees, exactly for multiples of
+// 90 degrees.
+func sincosd(x float64) (float64, float64) {
+	r := math.Remainder(x, 90)
+	q := int(math.Round((x - r) / 90))
+	s, c := math.Sincos(r * degree)
+	var sinx, cosx float64
+	switch q & 3 {
+	case 0:
+		sinx, cosx = s, c
+	case 1:
+		sinx, cosx = c, -s
+	case 2:
+		sinx, cosx = -s, -c
+	default:
+		sinx, cosx = -c, s
+	}
+	if x != 0 {
+		sinx += 0
+		cosx += 0
+	}
+	return sinx, cosx
+}
+
+// atan2d returns atan2(y, x) in degrees, exactly for multiples of 45
+// degrees.
+func atan2d(y, x float64) float64 {
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		x, y = y, x
+		q = 2
+	}
+	if math.Signbit(x) {
+		x = -x
+		q++
+	}
+	angle := math.Atan2(y, x) / degree
+	switch q {
+	case 1:
+		angle = math.Copysign(180, y) - angle
+	case 2:
+		angle = 90 - angle
+	case 3:
+		angle = -90 + angle
+	}
+	return angle
+}
+
+// norm2 returns x and y scaled so that x^2 + y^2 = 1.
+func norm2(x, y float64) (float64, float64) {
+	r := math.Hypot(x, y)
+	return x / r, y / r
+}
+
+// sinCosSeries evaluates the sine series sum(c[l] * sin(2*l*sigma), l = 1,
+// n) if sinp is true, or the cosine series sum(c[l] * cos((2*l+1)*sigma), l
+// = 0, n-1) otherwise, using Clenshaw summation.
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64, n int) float64 {
+	i := n
+	if sinp {
+		i++
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	var y0, y1 float64
+	if n&1 != 0 {
+		i--
+		y0 = c[i]
+	}
+	for range n / 2 {
+		i--
+		y1 = ar*y0 - y1 + c[i]
+		i--
+		y0 = ar*y1 - y0 + c[i]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0
+	}
+	return cosx * (y0 - y1)
+}
+
+// astroid solves k^4+2*k^3-(x^2+y^2-1)*k^2-2*y^2*k-y^2 = 0 for its positive
+// root k.
+func astroid(x, y float64) float64 {
+	p := x * x
+	q := y * y
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		return 0
+	}
+	s := p * q / 4
+	r2 := r * r
+	r3 := r * r2
+	disc := s * (s + 2*r3)
+	u := r
+	if disc >= 0 {
+		t3 := s + r3
+		if t3 < 0 {
+			t3 -= math.Sqrt(disc)
+		} else {
+			t3 += math.Sqrt(disc)
+		}
+		t := math.Cbrt(t3)
+		u += t
+		if t != 0 {
+			u += r2 / t
+		}
+	} else {
+		angle := math.Atan2(math.Sqrt(-disc), -(s + r3))
+		u += 2 * r * math.Cos(angle/3)
+	}
+	v := math.Sqrt(u*u + q)
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+w*w) + w)
+}
@@ -0,0 +1,202 @@
+package geodesic
+
+// Ported from GeographicLib, https://geographiclib.sourceforge.io/.
+// Original license:
+//
+// The MIT License (MIT)
+//
+// Copyright (c) 2008-2024, Charles Karney
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+import (
+	"math"
+
+	"github.com/twpayne/go-geom"
+)
+
+// Length returns the geodesic length of g. As with the Length methods of the
+// geom types, the length of a polygon is the length of its rings, and points
+// have zero length.
+func (e *Ellipsoid) Length(g geom.T) (float64, error) {
+	switch g := g.(type) {
+	case *geom.Point, *geom.MultiPoint:
+		return 0, nil
+	case *geom.LineString, *geom.LinearRing:
+		return e.lineLength(g.FlatCoords(), g.Stride()), nil
+	case *geom.MultiLineString:
+		return e.ringsLength(g.FlatCoords(), 0, g.Ends(), g.Stride()), nil
+	case *geom.Polygon:
+		return e.ringsLength(g.FlatCoords(), 0, g.Ends(), g.Stride()), nil
+	case *geom.MultiPolygon:
+		var length float64
+		offset := 0
+		for _, ends := range g.Endss() {
+			length += e.ringsLength(g.FlatCoords(), offset, ends, g.Stride())
+			if len(ends) != 0 {
+				offset = ends[len(ends)-1]
+			}
+		}
+		return length, nil
+	case *geom.GeometryCollection:
+		var length float64
+		for _, child := range g.Geoms() {
+			childLength, err := e.Length(child)
+			if err != nil {
+				return 0, err
+			}
+			length += childLength
+		}
+		return length, nil
+	default:
+		return 0, geom.ErrUnsupportedType{Value: g}
+	}
+}
+
+// Area returns the geodesic area of g. The area of a polygon is the area of
+// its exterior ring minus the areas of its interior rings, regardless of
+// their orientations, so each ring must enclose less than half of the
+// ellipsoid. Points and lines have zero area.
+func (e *Ellipsoid) Area(g geom.T) (float64, error) {
+	switch g := g.(type) {
+	case *geom.Point, *geom.MultiPoint, *geom.LineString, *geom.LinearRing, *geom.MultiLineString:
+		return 0, nil
+	case *geom.Polygon:
+		return e.polygonArea(g.FlatCoords(), 0, g.Ends(), g.Stride()), nil
+	case *geom.MultiPolygon:
+		var area float64
+		offset := 0
+		for _, ends := range g.Endss() {
+			area += e.polygonArea(g.FlatCoords(), offset, ends, g.Stride())
+			if len(ends) != 0 {
+				offset = ends[len(ends)-1]
+			}
+		}
+		return area, nil
+	case *geom.GeometryCollection:
+		var area float64
+		for _, child := range g.Geoms() {
+			childArea, err := e.Area(child)
+			if err != nil {
+				return 0, err
+			}
+			area += childArea
+		}
+		return area, nil
+	default:
+		return 0, geom.ErrUnsupportedType{Value: g}
+	}
+}
+
+// RingArea returns the signed geodesic area of the ring with coordinates
+// flatCoords, which is positive if the ring is counterclockwise, and its
+// perimeter. The ring is closed automatically if its first and last points
+// differ.
+func (e *Ellipsoid) RingArea(flatCoords []float64, stride int) (area, perimeter float64) {
+	n := len(flatCoords) / stride
+	if n < 2 {
+		return 0, 0
+	}
+	var sum float64
+	crossings := 0
+	for i := range n {
+		j := (i + 1) % n
+		lon1, lat1 := flatCoords[i*stride], flatCoords[i*stride+1]
+		lon2, lat2 := flatCoords[j*stride], flatCoords[j*stride+1]
+		r := e.inverse(lat1, lon1, lat2, lon2, true)
+		sum += r.area
+		perimeter += r.s12
+		crossings += transit(lon1, lon2)
+	}
+
+	// sum is the area in the clockwise sense, modulo the area of the
+	// ellipsoid. An odd number of crossings of the prime meridian means
+	// that the ring encircles a pole.
+	area0 := e.SurfaceArea()
+	sum = math.Remainder(sum, area0)
+	if crossings&1 != 0 {
+		if sum < 0 {
+			sum += area0 / 2
+		} else {
+			sum -= area0 / 2
+		}
+	}
+	area = -sum
+	switch {
+	case area > area0/2:
+		area -= area0
+	case area <= -area0/2:
+		area += area0
+	}
+	return area + 0, perimeter
+}
+
+// lineLength returns the geodesic length of the line with coordinates
+// flatCoords.
+func (e *Ellipsoid) lineLength(flatCoords []float64, stride int) float64 {
+	var length float64
+	for i := stride; i < len(flatCoords); i += stride {
+		s12, _, _ := e.Inverse(flatCoords[i-stride+1], flatCoords[i-stride], flatCoords[i+1], flatCoords[i])
+		length += s12
+	}
+	return length
+}
+
+// ringsLength returns the total geodesic length of the lines with
+// coordinates flatCoords[offset:ends[len(ends)-1]].
+func (e *Ellipsoid) ringsLength(flatCoords []float64, offset int, ends []int, stride int) float64 {
+	var length float64
+	for _, end := range ends {
+		length += e.lineLength(flatCoords[offset:end], stride)
+		offset = end
+	}
+	return length
+}
+
+// polygonArea returns the geodesic area of the polygon with rings
+// flatCoords[offset:ends[len(ends)-1]].
+func (e *Ellipsoid) polygonArea(flatCoords []float64, offset int, ends []int, stride int) float64 {
+	var area float64
+	for i, end := range ends {
+		ringArea, _ := e.RingArea(flatCoords[offset:end], stride)
+		if i == 0 {
+			area += math.Abs(ringArea)
+		} else {
+			area -= math.Abs(ringArea)
+		}
+		offset = end
+	}
+	return area
+}
+
+// transit returns 1 if the segment from lon1 to lon2 crosses the prime
+// meridian eastwards, -1 if it crosses it westwards, and 0 otherwise.
+func transit(lon1, lon2 float64) int {
+	lon12, _ := angDiff(lon1, lon2)
+	lon1 = angNormalize(lon1)
+	lon2 = angNormalize(lon2)
+	switch {
+	case lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)):
+		return 1
+	case lon12 < 0 && lon1 >= 0 && lon2 < 0:
+		return -1
+	default:
+		return 0
+	}
+}
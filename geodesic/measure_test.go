@@ -0,0 +1,127 @@
+package geodesic_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+func TestLengthAndArea(t *testing.T) {
+	diamond := []float64{-1, 0, 0, -1, 1, 0, 0, 1, -1, 0}
+	smallDiamond := []float64{-0.5, 0, 0, 0.5, 0.5, 0, 0, -0.5, -0.5, 0}
+	smallDiamondArea, smallDiamondPerimeter := geodesic.WGS84.RingArea(smallDiamond, 2)
+	for _, tc := range []struct {
+		name          string
+		g             geom.T
+		length        float64
+		area          float64
+		lengthDelta   float64
+		areaDelta     float64
+		expectedError bool
+	}{
+		{
+			name: "point",
+			g:    geom.NewPointFlat(geom.XY, []float64{1, 2}),
+		},
+		{
+			name:        "linestring",
+			g:           geom.NewLineStringFlat(geom.XYZ, []float64{-73.8, 40.6, 1000, -0.5, 51.6, 2000}),
+			length:      5551759.400318679,
+			lengthDelta: 1e-8,
+		},
+		{
+			name:        "multilinestring",
+			g:           geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 0, 90, 0, 0, 90, 0}, []int{4, 8}),
+			length:      10001965.729312724 + 10018754.171394622,
+			lengthDelta: 1e-6,
+		},
+		{
+			name:        "polygon",
+			g:           geom.NewPolygonFlat(geom.XY, diamond, []int{10}),
+			length:      627598.2731,
+			area:        24619419146,
+			lengthDelta: 1e-4,
+			areaDelta:   1,
+		},
+		{
+			name:        "clockwise_polygon",
+			g:           geom.NewPolygonFlat(geom.XY, []float64{-1, 0, 0, 1, 1, 0, 0, -1, -1, 0}, []int{10}),
+			length:      627598.2731,
+			area:        24619419146,
+			lengthDelta: 1e-4,
+			areaDelta:   1,
+		},
+		{
+			name:        "polar_polygon",
+			g:           geom.NewPolygonFlat(geom.XY, []float64{0, 89, 90, 89, 180, 89, 270, 89, 0, 89}, []int{10}),
+			length:      631819.8745,
+			area:        24952305678,
+			lengthDelta: 1e-4,
+			areaDelta:   1,
+		},
+		{
+			name:        "antimeridian_polygon",
+			g:           geom.NewPolygonFlat(geom.XY, []float64{179, 0, 180, -1, -179, 0, 180, 1, 179, 0}, []int{10}),
+			length:      627598.2731,
+			area:        24619419146,
+			lengthDelta: 1e-4,
+			areaDelta:   1,
+		},
+		{
+			name:        "polygon_with_hole",
+			g:           geom.NewPolygonFlat(geom.XY, append(append([]float64{}, diamond...), smallDiamond...), []int{10, 20}),
+			length:      627598.2731 + smallDiamondPerimeter,
+			area:        24619419146 + smallDiamondArea,
+			lengthDelta: 1e-4,
+			areaDelta:   1,
+		},
+		{
+			name:        "multipolygon",
+			g:           geom.NewMultiPolygonFlat(geom.XY, append(append([]float64{}, diamond...), 0, 89, 90, 89, 180, 89, 270, 89, 0, 89), [][]int{{10}, {20}}),
+			length:      627598.2731 + 631819.8745,
+			area:        24619419146 + 24952305678,
+			lengthDelta: 1e-3,
+			areaDelta:   2,
+		},
+		{
+			name: "geometrycollection",
+			g: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{0, 0}),
+				geom.NewPolygonFlat(geom.XY, diamond, []int{10}),
+			),
+			length:      627598.2731,
+			area:        24619419146,
+			lengthDelta: 1e-4,
+			areaDelta:   1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			length, err := geodesic.WGS84.Length(tc.g)
+			assert.NoError(t, err)
+			assertInDelta(t, tc.length, length, tc.lengthDelta)
+			area, err := geodesic.WGS84.Area(tc.g)
+			assert.NoError(t, err)
+			assertInDelta(t, tc.area, area, tc.areaDelta)
+		})
+	}
+}
+
+func TestRingArea(t *testing.T) {
+	counterclockwise := []float64{-1, 0, 0, -1, 1, 0, 0, 1}
+	area, perimeter := geodesic.WGS84.RingArea(counterclockwise, 2)
+	assertInDelta(t, 24619419146, area, 1)
+	assertInDelta(t, 627598.2731, perimeter, 1e-4)
+
+	clockwise := []float64{0, 1, 1, 0, 0, -1, -1, 0}
+	area, perimeter = geodesic.WGS84.RingArea(clockwise, 2)
+	assertInDelta(t, -24619419146, area, 1)
+	assertInDelta(t, 627598.2731, perimeter, 1e-4)
+
+	octant := []float64{0, 90, 0, 0, 90, 0}
+	area, perimeter = geodesic.WGS84.RingArea(octant, 2)
+	assertInDelta(t, geodesic.WGS84.SurfaceArea()/8, area, 1)
+	assertInDelta(t, 30022685, perimeter, 1)
+}
@@ -0,0 +1,231 @@
+package geodesic
+
+// Ported from GeographicLib, https://geographiclib.sourceforge.io/.
+// Original license:
+//
+// The MIT License (MIT)
+//
+// Copyright (c) 2008-2024, Charles Karney
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+// The series expansions in this file are of order 6 in the third flattening
+// n and in eps, as described in C. F. F. Karney, Algorithms for geodesics,
+// J. Geodesy 87, 43-55 (2013), https://doi.org/10.1007/s00190-012-0578-z.
+
+const (
+	nA1  = 6
+	nC1  = 6
+	nC1p = 6
+	nA2  = 6
+	nC2  = 6
+	nA3  = 6
+	nC3  = 6
+	nC4  = 6
+	nC   = 7
+)
+
+// a1m1f returns A1 - 1.
+func a1m1f(eps float64) float64 {
+	coeff := [...]float64{1, 4, 64, 0, 256}
+	m := nA1 / 2
+	t := polyval(m, coeff[:], eps*eps) / coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+// c1f sets c[1:nC1+1] to the coefficients C1[l].
+func c1f(eps float64, c []float64) {
+	coeff := [...]float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}
+	seriesCoeffs(eps, coeff[:], nC1, c)
+}
+
+// c1pf sets c[1:nC1p+1] to the coefficients C1'[l].
+func c1pf(eps float64, c []float64) {
+	coeff := [...]float64{
+		205, -432, 768, 1536,
+		4005, -4736, 3840, 12288,
+		-225, 116, 384,
+		-7173, 2695, 7680,
+		3467, 7680,
+		38081, 61440,
+	}
+	seriesCoeffs(eps, coeff[:], nC1p, c)
+}
+
+// a2m1f returns A2 - 1.
+func a2m1f(eps float64) float64 {
+	coeff := [...]float64{-11, -28, -192, 0, 256}
+	m := nA2 / 2
+	t := polyval(m, coeff[:], eps*eps) / coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+// c2f sets c[1:nC2+1] to the coefficients C2[l].
+func c2f(eps float64, c []float64) {
+	coeff := [...]float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}
+	seriesCoeffs(eps, coeff[:], nC2, c)
+}
+
+// seriesCoeffs sets c[1:n+1] to the coefficients of a series in eps whose
+// lth coefficient is eps^l times a polynomial in eps^2 given by coeff.
+func seriesCoeffs(eps float64, coeff []float64, n int, c []float64) {
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= n; l++ {
+		m := (n - l) / 2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// a3coeff returns the coefficients of A3 as a polynomial in eps, which
+// depend on the third flattening n.
+func a3coeff(n float64) []float64 {
+	coeff := [...]float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+	a3x := make([]float64, 0, nA3)
+	o := 0
+	for j := nA3 - 1; j >= 0; j-- {
+		m := min(nA3-j-1, j)
+		a3x = append(a3x, polyval(m, coeff[o:], n)/coeff[o+m+1])
+		o += m + 2
+	}
+	return a3x
+}
+
+// c3coeff returns the coefficients of C3 as polynomials in eps, which
+// depend on the third flattening n.
+func c3coeff(n float64) []float64 {
+	coeff := [...]float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+	c3x := make([]float64, 0, nC3*(nC3-1)/2)
+	o := 0
+	for l := 1; l < nC3; l++ {
+		for j := nC3 - 1; j >= l; j-- {
+			m := min(nC3-j-1, j)
+			c3x = append(c3x, polyval(m, coeff[o:], n)/coeff[o+m+1])
+			o += m + 2
+		}
+	}
+	return c3x
+}
+
+// c4coeff returns the coefficients of C4 as polynomials in eps, which
+// depend on the third flattening n.
+func c4coeff(n float64) []float64 {
+	coeff := [...]float64{
+		97, 15015,
+		1088, 156, 45045,
+		-224, -4784, 1573, 45045,
+		-10656, 14144, -4576, -858, 45045,
+		64, 624, -4576, 6864, -3003, 15015,
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		1, 9009,
+		-2944, 468, 135135,
+		5792, 1040, -1287, 135135,
+		5952, -11648, 9152, -2574, 135135,
+		-64, -624, 4576, -6864, 3003, 135135,
+		8, 10725,
+		1856, -936, 225225,
+		-8448, 4992, -1144, 225225,
+		-1440, 4160, -4576, 1716, 225225,
+		-136, 63063,
+		1024, -208, 105105,
+		3584, -3328, 1144, 315315,
+		-128, 135135,
+		-2560, 832, 405405,
+		128, 99099,
+	}
+	c4x := make([]float64, 0, nC4*(nC4+1)/2)
+	o := 0
+	for l := range nC4 {
+		for j := nC4 - 1; j >= l; j-- {
+			m := nC4 - j - 1
+			c4x = append(c4x, polyval(m, coeff[o:], n)/coeff[o+m+1])
+			o += m + 2
+		}
+	}
+	return c4x
+}
+
+// a3f returns A3.
+func (e *Ellipsoid) a3f(eps float64) float64 {
+	return polyval(nA3-1, e.a3x, eps)
+}
+
+// c3f sets c[1:nC3] to the coefficients C3[l].
+func (e *Ellipsoid) c3f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := 1; l < nC3; l++ {
+		m := nC3 - l - 1
+		mult *= eps
+		c[l] = mult * polyval(m, e.c3x[o:], eps)
+		o += m + 1
+	}
+}
+
+// c4f sets c[0:nC4] to the coefficients C4[l].
+func (e *Ellipsoid) c4f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := range nC4 {
+		m := nC4 - l - 1
+		c[l] = mult * polyval(m, e.c4x[o:], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
@@ -0,0 +1,228 @@
+package proj
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+// An ErrUnknownSRID is returned when an SRID is not registered.
+type ErrUnknownSRID int
+
+func (e ErrUnknownSRID) Error() string {
+	return fmt.Sprintf("proj: unknown SRID %d", int(e))
+}
+
+var (
+	errNilEllipsoid   = errors.New("proj: nil ellipsoid")
+	errInvalidToWGS84 = errors.New("proj: ToWGS84 must have 0, 3, or 7 parameters")
+)
+
+// Ellipsoids used by the built-in CRSs.
+var (
+	grs80      = geodesic.NewEllipsoid(6378137, 1/298.257222101)
+	airy1830   = geodesic.NewEllipsoid(6377563.396, 1/299.3249646)
+	bessel1841 = geodesic.NewEllipsoid(6377397.155, 1/299.1528128)
+	clarke1866 = geodesic.NewEllipsoid(6378206.4, 1/294.9786982)
+	intl1924   = geodesic.NewEllipsoid(6378388, 1.0/297)
+)
+
+// osgb36ToWGS84 are the Helmert parameters from OSGB36 to WGS84.
+var osgb36ToWGS84 = []float64{446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489}
+
+// A CRS is a coordinate reference system. Geographic CRSs have longitudes
+// and latitudes in degrees as their x and y ordinates, and projected CRSs
+// have projected coordinates.
+type CRS struct {
+	// Ellipsoid is the ellipsoid of the datum.
+	Ellipsoid *geodesic.Ellipsoid
+	// ToWGS84 are the parameters of the Helmert transformation from the
+	// datum to WGS84, as in the +towgs84 parameter of PROJ: the translations
+	// in meters, optionally followed by the rotations in arc-seconds in the
+	// position vector convention and the scale difference in parts per
+	// million. It is empty if the datum is WGS84 or is treated as equivalent
+	// to WGS84.
+	ToWGS84 []float64
+	// Projection is the projection of the CRS, or nil if the CRS is
+	// geographic. Its geographic coordinates are in the datum of the CRS.
+	Projection *Projection
+}
+
+// A Registry maps SRIDs to CRSs. It is safe for concurrent use.
+type Registry struct {
+	mu   sync.RWMutex
+	crss map[int]*CRS
+}
+
+// DefaultRegistry is the Registry used by the package-level functions.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns a new Registry containing the built-in CRSs:
+//
+//   - 4326 WGS84, 4258 ETRS89, 4269 NAD83, 4171 RGF93, and 4277 OSGB36
+//     geographic coordinates.
+//   - 3857 and 900913 Web Mercator and 3395 World Mercator.
+//   - 32601 to 32660 and 32701 to 32760 WGS84 UTM zones.
+//   - 25828 to 25838 ETRS89 UTM zones and 26901 to 26923 NAD83 UTM zones.
+//   - 3034 ETRS89 Lambert conformal conic for Europe, 2154 RGF93 Lambert-93,
+//     and 27700 British National Grid.
+func NewRegistry() *Registry {
+	wgs84 := &CRS{Ellipsoid: geodesic.WGS84}
+	etrs89 := &CRS{Ellipsoid: grs80}
+	nad83 := &CRS{Ellipsoid: grs80}
+	rgf93 := &CRS{Ellipsoid: grs80}
+	osgb36 := &CRS{Ellipsoid: airy1830, ToWGS84: slices.Clone(osgb36ToWGS84)}
+	projected := func(base *CRS, p *Projection) *CRS {
+		return &CRS{Ellipsoid: base.Ellipsoid, ToWGS84: base.ToWGS84, Projection: p}
+	}
+	crss := map[int]*CRS{
+		4326:   wgs84,
+		4258:   etrs89,
+		4269:   nad83,
+		4171:   rgf93,
+		4277:   osgb36,
+		3857:   projected(wgs84, WebMercator),
+		900913: projected(wgs84, WebMercator.WithSRID(900913)),
+		3395:   projected(wgs84, NewMercator(geodesic.WGS84, 0, 1, 0, 0).WithSRID(3395)),
+		3034:   projected(etrs89, NewLambertConformalConic(grs80, 35, 65, 52, 10, 4000000, 2800000).WithSRID(3034)),
+		2154:   projected(rgf93, NewLambertConformalConic(grs80, 49, 44, 46.5, 3, 700000, 6600000).WithSRID(2154)),
+		27700:  projected(osgb36, NewTransverseMercator(airy1830, -2, 49, 0.9996012717, 400000, -100000).WithSRID(27700)),
+	}
+	utm := func(e *geodesic.Ellipsoid, zone int, y0 float64) *Projection {
+		return NewTransverseMercator(e, float64(6*zone-183), 0, 0.9996, 500000, y0)
+	}
+	for zone := 1; zone <= 60; zone++ {
+		crss[32600+zone] = projected(wgs84, utm(geodesic.WGS84, zone, 0).WithSRID(32600+zone))
+		crss[32700+zone] = projected(wgs84, utm(geodesic.WGS84, zone, 10000000).WithSRID(32700+zone))
+	}
+	for zone := 28; zone <= 38; zone++ {
+		crss[25800+zone] = projected(etrs89, utm(grs80, zone, 0).WithSRID(25800+zone))
+	}
+	for zone := 1; zone <= 23; zone++ {
+		crss[26900+zone] = projected(nad83, utm(grs80, zone, 0).WithSRID(26900+zone))
+	}
+	return &Registry{crss: crss}
+}
+
+// Lookup returns the CRS with SRID srid.
+func (r *Registry) Lookup(srid int) (*CRS, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	crs, ok := r.crss[srid]
+	if !ok {
+		return nil, ErrUnknownSRID(srid)
+	}
+	return crs, nil
+}
+
+// Register registers crs with SRID srid, replacing any existing CRS with the
+// same SRID.
+func (r *Registry) Register(srid int, crs *CRS) error {
+	if crs.Ellipsoid == nil {
+		return errNilEllipsoid
+	}
+	if n := len(crs.ToWGS84); n != 0 && n != 3 && n != 7 {
+		return errInvalidToWGS84
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.crss[srid] = crs
+	return nil
+}
+
+// RegisterPROJ registers the CRS defined by the PROJ string s with SRID
+// srid. See ParsePROJ for the supported subset of PROJ strings.
+func (r *Registry) RegisterPROJ(srid int, s string) error {
+	crs, err := ParsePROJ(s)
+	if err != nil {
+		return err
+	}
+	return r.Register(srid, crs)
+}
+
+// RegisterWKT registers the CRS defined by the WKT string s with SRID srid.
+// See ParseWKT for the supported subset of WKT.
+func (r *Registry) RegisterWKT(srid int, s string) error {
+	crs, err := ParseWKT(s)
+	if err != nil {
+		return err
+	}
+	return r.Register(srid, crs)
+}
+
+// Reproject returns a copy of g transformed from the CRS of its SRID to the
+// CRS with SRID toSRID, with SRID toSRID. If the CRSs have different datums
+// then the coordinates are transformed through WGS84 with their Helmert
+// transformations, assuming that the points are on the surface of the
+// ellipsoid. The ordinates other than x and y, such as Z and M, are
+// unchanged. It returns an ErrUnknownSRID if either SRID is not registered,
+// including if g has SRID zero.
+func (r *Registry) Reproject(g geom.T, toSRID int) (geom.T, error) {
+	fromSRID := g.SRID()
+	from, err := r.Lookup(fromSRID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := r.Lookup(toSRID)
+	if err != nil {
+		return nil, err
+	}
+	return transform(g, fromSRID, toSRID, newTransformation(from, to))
+}
+
+// Lookup returns the CRS with SRID srid in DefaultRegistry.
+func Lookup(srid int) (*CRS, error) {
+	return DefaultRegistry.Lookup(srid)
+}
+
+// Register registers crs with SRID srid in DefaultRegistry.
+func Register(srid int, crs *CRS) error {
+	return DefaultRegistry.Register(srid, crs)
+}
+
+// RegisterPROJ registers the CRS defined by the PROJ string s with SRID srid
+// in DefaultRegistry.
+func RegisterPROJ(srid int, s string) error {
+	return DefaultRegistry.RegisterPROJ(srid, s)
+}
+
+// RegisterWKT registers the CRS defined by the WKT string s with SRID srid in
+// DefaultRegistry.
+func RegisterWKT(srid int, s string) error {
+	return DefaultRegistry.RegisterWKT(srid, s)
+}
+
+// Reproject returns a copy of g transformed to the CRS with SRID toSRID
+// using DefaultRegistry.
+func Reproject(g geom.T, toSRID int) (geom.T, error) {
+	return DefaultRegistry.Reproject(g, toSRID)
+}
+
+// newTransformation returns a function that transforms coordinates in place
+// from from to to.
+func newTransformation(from, to *CRS) func(geom.Coord) {
+	var fromEllipsoid, toEllipsoid ellipsoid
+	fromHelmert, toHelmert := newHelmert(from.ToWGS84), newHelmert(to.ToWGS84)
+	shift := fromHelmert != toHelmert
+	if shift {
+		fromEllipsoid = newEllipsoid(from.Ellipsoid.EquatorialRadius(), from.Ellipsoid.Flattening())
+		toEllipsoid = newEllipsoid(to.Ellipsoid.EquatorialRadius(), to.Ellipsoid.Flattening())
+	}
+	return func(c geom.Coord) {
+		x, y := c[0], c[1]
+		if from.Projection != nil {
+			x, y = from.Projection.inverse(x, y)
+		}
+		if shift {
+			x, y = toEllipsoid.geodetic(toHelmert.inverse(fromHelmert.forward(fromEllipsoid.geocentric(x, y))))
+		}
+		if to.Projection != nil {
+			x, y = to.Projection.forward(x, y)
+		}
+		c[0], c[1] = x, y
+	}
+}
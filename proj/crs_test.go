@@ -0,0 +1,142 @@
+package proj_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/ewkb"
+	"github.com/twpayne/go-geom/geodesic"
+	"github.com/twpayne/go-geom/proj"
+)
+
+func TestReproject(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		g        geom.T
+		toSRID   int
+		expected []float64
+		delta    float64
+	}{
+		{
+			name:     "4326_to_3857",
+			g:        geom.NewPointFlat(geom.XYZ, []float64{-0.1275, 51.507222, 11}).SetSRID(4326),
+			toSRID:   3857,
+			expected: []float64{-14193.23507614238, 6711510.640113421, 11},
+			delta:    1e-6,
+		},
+		{
+			name:     "3857_to_32631",
+			g:        geom.NewPointFlat(geom.XY, []float64{333958.4723798207, 0}).SetSRID(3857),
+			toSRID:   32631,
+			expected: []float64{500000, 0},
+			delta:    1e-6,
+		},
+		{
+			name:     "32731_to_4326",
+			g:        geom.NewPointFlat(geom.XY, []float64{500000, 10000000}).SetSRID(32731),
+			toSRID:   4326,
+			expected: []float64{3, 0},
+			delta:    1e-12,
+		},
+		{
+			name:     "etrs89_is_wgs84",
+			g:        geom.NewPointFlat(geom.XY, []float64{2.35, 48.85}).SetSRID(4258),
+			toSRID:   4326,
+			expected: []float64{2.35, 48.85},
+			delta:    1e-12,
+		},
+		{
+			// The Airy transit circle at Greenwich defines the prime meridian
+			// of OSGB36, which is about 5.3 arc-seconds west of the WGS84
+			// prime meridian.
+			name:     "wgs84_to_osgb36",
+			g:        geom.NewPointFlat(geom.XY, []float64{-0.0015, 51.4778}).SetSRID(4326),
+			toSRID:   4277,
+			expected: []float64{0, 51.4773},
+			delta:    2e-4,
+		},
+		{
+			name:     "same_srid",
+			g:        geom.NewPointFlat(geom.XY, []float64{1, 2}).SetSRID(27700),
+			toSRID:   27700,
+			expected: []float64{1, 2},
+			delta:    1e-9,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := proj.Reproject(tc.g, tc.toSRID)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.toSRID, actual.SRID())
+			assertFlatCoordsInDelta(t, tc.expected, actual.FlatCoords(), tc.delta)
+		})
+	}
+}
+
+func TestReprojectRoundTrip(t *testing.T) {
+	ls := geom.NewLineStringFlat(geom.XY, []float64{-3.2, 55.9, -0.1, 51.5, -1.9, 52.5}).SetSRID(4326)
+	for _, srid := range []int{3857, 3395, 27700, 4277, 32630, 25830, 3034, 2154} {
+		projected, err := proj.Reproject(ls, srid)
+		assert.NoError(t, err)
+		actual, err := proj.Reproject(projected, 4326)
+		assert.NoError(t, err)
+		assertFlatCoordsInDelta(t, ls.FlatCoords(), actual.FlatCoords(), 1e-7)
+	}
+}
+
+func TestReprojectBritishNationalGrid(t *testing.T) {
+	// The transformation from WGS84 to OSGB36 with a Helmert transformation
+	// is accurate to a few meters.
+	p := geom.NewPointFlat(geom.XY, []float64{-0.0014, 51.4778}).SetSRID(4326)
+	actual, err := proj.Reproject(p, 27700)
+	assert.NoError(t, err)
+	assertFlatCoordsInDelta(t, []float64{538890, 177320}, actual.FlatCoords(), 5)
+}
+
+func TestReprojectEWKB(t *testing.T) {
+	var p ewkb.Polygon
+	assert.NoError(t, p.Scan([]byte{
+		0x01, 0x03, 0x00, 0x00, 0x20, 0xe6, 0x10, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+		0x04, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}))
+	assert.Equal(t, 4326, p.SRID())
+	actual, err := proj.Reproject(p, 3857)
+	assert.NoError(t, err)
+	polygon, ok := actual.(*geom.Polygon)
+	assert.True(t, ok)
+	assert.Equal(t, 3857, polygon.SRID())
+	assertFlatCoordsInDelta(t, []float64{0, 0, 111319.49079327357, 0, 0, 111325.14286638486, 0, 0}, polygon.FlatCoords(), 1e-6)
+}
+
+func TestReprojectErrors(t *testing.T) {
+	_, err := proj.Reproject(geom.NewPointFlat(geom.XY, []float64{0, 0}), 4326)
+	assert.Equal[error](t, proj.ErrUnknownSRID(0), err)
+	_, err = proj.Reproject(geom.NewPointFlat(geom.XY, []float64{0, 0}).SetSRID(4326), 1234567)
+	assert.Equal[error](t, proj.ErrUnknownSRID(1234567), err)
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := proj.NewRegistry()
+	_, err := r.Lookup(100000)
+	assert.Equal[error](t, proj.ErrUnknownSRID(100000), err)
+	assert.NoError(t, r.Register(100000, &proj.CRS{
+		Ellipsoid:  geodesic.WGS84,
+		Projection: proj.NewTransverseMercator(geodesic.WGS84, 10, 0, 1, 0, 0),
+	}))
+	actual, err := r.Reproject(geom.NewPointFlat(geom.XY, []float64{10, 45}).SetSRID(4326), 100000)
+	assert.NoError(t, err)
+	assert.Equal(t, 100000, actual.SRID())
+	assertInDelta(t, 0, actual.FlatCoords()[0], 1e-9)
+
+	// The default registry is not changed.
+	_, err = proj.Lookup(100000)
+	assert.Equal[error](t, proj.ErrUnknownSRID(100000), err)
+
+	assert.Error(t, r.Register(100001, &proj.CRS{}))
+	assert.Error(t, r.Register(100001, &proj.CRS{Ellipsoid: geodesic.WGS84, ToWGS84: []float64{1, 2}}))
+}
@@ -0,0 +1,72 @@
+package proj
+
+import "math"
+
+// arcSecond is one arc-second in radians.
+const arcSecond = degree / 3600
+
+// A helmert is a seven parameter Helmert transformation to WGS84 in the
+// position vector convention: the translations in meters, the rotations in
+// radians, and the scale difference.
+type helmert struct {
+	dx, dy, dz, rx, ry, rz, ds float64
+}
+
+// newHelmert returns the Helmert transformation with the parameters of the
+// ToWGS84 field of a CRS.
+func newHelmert(toWGS84 []float64) helmert {
+	var h helmert
+	if len(toWGS84) >= 3 {
+		h.dx, h.dy, h.dz = toWGS84[0], toWGS84[1], toWGS84[2]
+	}
+	if len(toWGS84) == 7 {
+		h.rx, h.ry, h.rz = toWGS84[3]*arcSecond, toWGS84[4]*arcSecond, toWGS84[5]*arcSecond
+		h.ds = toWGS84[6] * 1e-6
+	}
+	return h
+}
+
+// forward transforms the geocentric coordinates (x, y, z) to WGS84.
+func (h helmert) forward(x, y, z float64) (float64, float64, float64) {
+	s := 1 + h.ds
+	return h.dx + s*(x-h.rz*y+h.ry*z),
+		h.dy + s*(h.rz*x+y-h.rx*z),
+		h.dz + s*(-h.ry*x+h.rx*y+z)
+}
+
+// inverse transforms the geocentric coordinates (x, y, z) from WGS84 with
+// the transpose of the rotation matrix, as PROJ does.
+func (h helmert) inverse(x, y, z float64) (float64, float64, float64) {
+	s := 1 + h.ds
+	x, y, z = (x-h.dx)/s, (y-h.dy)/s, (z-h.dz)/s
+	return x + h.rz*y - h.ry*z,
+		-h.rz*x + y + h.rx*z,
+		h.ry*x - h.rx*y + z
+}
+
+// geocentric returns the geocentric coordinates of the point (lon, lat) on
+// the surface of e.
+func (e ellipsoid) geocentric(lon, lat float64) (x, y, z float64) {
+	slam, clam := math.Sincos(lon * degree)
+	sphi, cphi := math.Sincos(lat * degree)
+	n := e.a / math.Sqrt(1-e.e2*sphi*sphi)
+	return n * cphi * clam, n * cphi * slam, n * (1 - e.e2) * sphi
+}
+
+// geodetic returns the longitude and latitude of the geocentric coordinates
+// (x, y, z) on e, ignoring the height.
+func (e ellipsoid) geodetic(x, y, z float64) (lon, lat float64) {
+	p := math.Hypot(x, y)
+	phi := math.Atan2(z, p*(1-e.e2))
+	for range 10 {
+		sphi := math.Sin(phi)
+		n := e.a / math.Sqrt(1-e.e2*sphi*sphi)
+		next := math.Atan2(z+e.e2*n*sphi, p)
+		if math.Abs(next-phi) < 1e-15 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	return math.Atan2(y, x) / degree, phi / degree
+}
@@ -0,0 +1,77 @@
+package proj
+
+import (
+	"math"
+
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+// A lambertConformalConic is a Lambert conformal conic projection.
+type lambertConformalConic struct {
+	ellipsoid
+	lon0, k0, x0, y0 float64
+	// n is the cone constant, af is the equatorial radius multiplied by k0
+	// and Snyder's F, and rho0 is the radius of the latitude of origin.
+	n, af, rho0 float64
+}
+
+// NewLambertConformalConic returns the Lambert conformal conic projection of
+// coordinates on e with standard parallels lat1 and lat2, latitude of origin
+// lat0, central meridian lon0, false easting x0, and false northing y0, as
+// in EPSG method 9802. If lat1 and lat2 are equal then the projection has a
+// single standard parallel. The projected coordinates are in the units of
+// the equatorial radius of e and have SRID zero. The geographic coordinates
+// have SRID 4326 if e is geodesic.WGS84 and zero otherwise. e must not be
+// prolate.
+func NewLambertConformalConic(e *geodesic.Ellipsoid, lat1, lat2, lat0, lon0, x0, y0 float64) *Projection {
+	return newLambertConformalConic(e, lat1, lat2, lat0, lon0, 1, x0, y0)
+}
+
+// newLambertConformalConic returns a Lambert conformal conic projection with
+// scale factor k0 on the standard parallels, which is used by EPSG method
+// 9801 with a single standard parallel.
+func newLambertConformalConic(e *geodesic.Ellipsoid, lat1, lat2, lat0, lon0, k0, x0, y0 float64) *Projection {
+	lcc := &lambertConformalConic{
+		ellipsoid: newEllipsoid(e.EquatorialRadius(), e.Flattening()),
+		lon0:      lon0,
+		k0:        k0,
+		x0:        x0,
+		y0:        y0,
+	}
+	psi1, psi2 := lcc.isometricLatitude(lat1), lcc.isometricLatitude(lat2)
+	m1 := lcc.parallelRadius(lat1)
+	if lat1 == lat2 {
+		lcc.n = math.Sin(lat1 * degree)
+	} else {
+		lcc.n = (math.Log(m1) - math.Log(lcc.parallelRadius(lat2))) / (psi2 - psi1)
+	}
+	lcc.af = lcc.a * k0 * m1 * math.Exp(lcc.n*psi1) / lcc.n
+	lcc.rho0 = lcc.af * math.Exp(-lcc.n*lcc.isometricLatitude(lat0))
+	return &Projection{
+		forward:        lcc.forward,
+		inverse:        lcc.inverse,
+		geographicSRID: geographicSRID(e),
+	}
+}
+
+func (lcc *lambertConformalConic) forward(lon, lat float64) (x, y float64) {
+	rho := lcc.af * math.Exp(-lcc.n*lcc.isometricLatitude(lat))
+	s, c := math.Sincos(lcc.n * math.Remainder(lon-lcc.lon0, 360) * degree)
+	return lcc.x0 + rho*s, lcc.y0 + lcc.rho0 - rho*c
+}
+
+func (lcc *lambertConformalConic) inverse(x, y float64) (lon, lat float64) {
+	dx, dy := x-lcc.x0, lcc.rho0-(y-lcc.y0)
+	if lcc.n < 0 {
+		dx, dy = -dx, -dy
+	}
+	rho := math.Copysign(math.Hypot(dx, dy), lcc.n)
+	psi := -math.Log(rho/lcc.af) / lcc.n
+	tau := lcc.latitudeTan(math.Sinh(psi))
+	return math.Remainder(lcc.lon0+math.Atan2(dx, dy)/lcc.n/degree, 360), math.Atan(tau) / degree
+}
+
+// isometricLatitude returns the isometric latitude of lat.
+func (lcc *lambertConformalConic) isometricLatitude(lat float64) float64 {
+	return math.Asinh(lcc.conformalTan(math.Tan(lat * degree)))
+}
@@ -0,0 +1,48 @@
+package proj_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom/geodesic"
+	"github.com/twpayne/go-geom/proj"
+)
+
+func TestLambertConformalConic(t *testing.T) {
+	// The NAD27 Texas South Central example from EPSG Guidance Note 7-2, in
+	// US survey feet.
+	const usSurveyFoot = 1200.0 / 3937
+	clarke1866 := geodesic.NewEllipsoid(6378206.4, 1/294.9786982)
+	lcc := proj.NewLambertConformalConic(clarke1866, 28+23.0/60, 30+17.0/60, 27+50.0/60, -99, 2000000*usSurveyFoot, 0)
+	x, y := lcc.Forward(-96, 28.5)
+	assertInDelta(t, 2963503.91, x/usSurveyFoot, 0.01)
+	assertInDelta(t, 254759.80, y/usSurveyFoot, 0.01)
+	lon, lat := lcc.Inverse(x, y)
+	assertInDelta(t, -96, lon, 1e-12)
+	assertInDelta(t, 28.5, lat, 1e-12)
+}
+
+func TestLambertConformalConicRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		lat1, lat2, lat0 float64
+	}{
+		{name: "north", lat1: 35, lat2: 65, lat0: 52},
+		{name: "south", lat1: -18, lat2: -36, lat0: -32},
+		{name: "one_standard_parallel", lat1: 46.5, lat2: 46.5, lat0: 46.5},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lcc := proj.NewLambertConformalConic(geodesic.WGS84, tc.lat1, tc.lat2, tc.lat0, 10, 4000000, 2800000)
+			assert.Equal(t, 4326, lcc.GeographicSRID())
+			for _, lat := range []float64{-70, -45, -20, 0, 20, 45, 70} {
+				for _, lon := range []float64{-30, 0, 10, 25, 60} {
+					x, y := lcc.Forward(lon, lat)
+					actualLon, actualLat := lcc.Inverse(x, y)
+					assertInDelta(t, lon, actualLon, 1e-9)
+					assertInDelta(t, lat, actualLat, 1e-9)
+				}
+			}
+		})
+	}
+}
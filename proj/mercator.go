@@ -0,0 +1,78 @@
+package proj
+
+import (
+	"math"
+
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+// earthRadius is the radius of the sphere used by Web Mercator, which is the
+// equatorial radius of WGS84.
+const earthRadius = 6378137
+
+// maxWebMercatorLatitude is the latitude in degrees at which Web Mercator
+// is square.
+var maxWebMercatorLatitude = math.Atan(math.Sinh(math.Pi)) / degree
+
+// WebMercator is the Web Mercator projection, EPSG:3857, of WGS84
+// coordinates, EPSG:4326, as used by most web maps. Latitudes are clamped to
+// the range [-85.0511287798, 85.0511287798], so the projected coordinates are
+// always finite.
+var WebMercator = &Projection{
+	forward:        webMercatorForward,
+	inverse:        webMercatorInverse,
+	srid:           3857,
+	geographicSRID: 4326,
+}
+
+func webMercatorForward(lon, lat float64) (x, y float64) {
+	lat = max(-maxWebMercatorLatitude, min(lat, maxWebMercatorLatitude))
+	return earthRadius * lon * degree, earthRadius * math.Asinh(math.Tan(lat*degree))
+}
+
+func webMercatorInverse(x, y float64) (lon, lat float64) {
+	return x / earthRadius / degree, math.Atan(math.Sinh(y/earthRadius)) / degree
+}
+
+// A mercator is a Mercator projection of an ellipsoid.
+type mercator struct {
+	ellipsoid
+	lon0, x0, y0 float64
+	// ak0 is the equatorial radius multiplied by the scale factor on the
+	// equator.
+	ak0 float64
+}
+
+// NewMercator returns the Mercator projection of coordinates on e with
+// central meridian lon0, scale factor k0 on the equator, false easting x0,
+// and false northing y0, as in EPSG method 9804. The projected coordinates
+// are in the units of the equatorial radius of e and have SRID zero. The
+// geographic coordinates have SRID 4326 if e is geodesic.WGS84 and zero
+// otherwise. e must not be prolate. Unlike WebMercator, the latitudes are
+// not clamped, so the poles are projected to infinity.
+func NewMercator(e *geodesic.Ellipsoid, lon0, k0, x0, y0 float64) *Projection {
+	m := &mercator{
+		ellipsoid: newEllipsoid(e.EquatorialRadius(), e.Flattening()),
+		lon0:      lon0,
+		x0:        x0,
+		y0:        y0,
+	}
+	m.ak0 = m.a * k0
+	return &Projection{
+		forward:        m.forward,
+		inverse:        m.inverse,
+		geographicSRID: geographicSRID(e),
+	}
+}
+
+func (m *mercator) forward(lon, lat float64) (x, y float64) {
+	x = m.x0 + m.ak0*math.Remainder(lon-m.lon0, 360)*degree
+	y = m.y0 + m.ak0*math.Asinh(m.conformalTan(math.Tan(lat*degree)))
+	return x, y
+}
+
+func (m *mercator) inverse(x, y float64) (lon, lat float64) {
+	lon = math.Remainder(m.lon0+(x-m.x0)/m.ak0/degree, 360)
+	lat = math.Atan(m.latitudeTan(math.Sinh((y-m.y0)/m.ak0))) / degree
+	return lon, lat
+}
@@ -0,0 +1,49 @@
+package proj_test
+
+import (
+	"testing"
+
+	"github.com/twpayne/go-geom/proj"
+)
+
+func TestWebMercator(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		lon, lat float64
+		x, y     float64
+	}{
+		{
+			name: "origin",
+		},
+		{
+			name: "north_east_corner",
+			lon:  180,
+			lat:  85.0511287798066,
+			x:    20037508.342789244,
+			y:    20037508.342789244,
+		},
+		{
+			name: "london",
+			lon:  -0.1275,
+			lat:  51.507222,
+			x:    -14193.23507614238,
+			y:    6711510.640113421,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			x, y := proj.WebMercator.Forward(tc.lon, tc.lat)
+			assertInDelta(t, tc.x, x, 1e-6)
+			assertInDelta(t, tc.y, y, 1e-6)
+			lon, lat := proj.WebMercator.Inverse(x, y)
+			assertInDelta(t, tc.lon, lon, 1e-12)
+			assertInDelta(t, tc.lat, lat, 1e-12)
+		})
+	}
+}
+
+func TestWebMercatorClampsLatitude(t *testing.T) {
+	_, y := proj.WebMercator.Forward(0, 90)
+	assertInDelta(t, 20037508.342789244, y, 1e-6)
+	_, y = proj.WebMercator.Forward(0, -90)
+	assertInDelta(t, -20037508.342789244, y, 1e-6)
+}
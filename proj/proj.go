@@ -0,0 +1,260 @@
+// Package proj implements map projections in pure Go.
+//
+// A Projection converts between geographic coordinates, with the longitude
+// as the x ordinate and the latitude as the y ordinate in degrees, and
+// projected coordinates, usually in meters. Projections are available for Web
+// Mercator (EPSG:3857), UTM, and generic transverse Mercator and Lambert
+// conformal conic projections on any ellipsoid.
+//
+// Projections do not change the datum, so the geographic coordinates are on
+// the ellipsoid of the projection, which is WGS84 for the built-in
+// projections.
+//
+// A Registry maps SRIDs to coordinate reference systems, so that geometries
+// can be reprojected between SRIDs, including between datums, with
+// Reproject. Common CRSs are built in and others can be registered from PROJ
+// strings or WKT.
+package proj
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/ewkb"
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+const degree = math.Pi / 180
+
+var errEmptyGeometry = errors.New("proj: empty geometry")
+
+// An ErrUnexpectedSRID is returned when a geometry has a different SRID to
+// the coordinates that a projection expects.
+type ErrUnexpectedSRID struct {
+	Expected int
+	Actual   int
+}
+
+func (e ErrUnexpectedSRID) Error() string {
+	return fmt.Sprintf("proj: expected SRID %d, got %d", e.Expected, e.Actual)
+}
+
+// A Projection is a map projection.
+type Projection struct {
+	forward        func(lon, lat float64) (x, y float64)
+	inverse        func(x, y float64) (lon, lat float64)
+	srid           int
+	geographicSRID int
+}
+
+// Forward returns the projected coordinates of the point (lon, lat).
+func (p *Projection) Forward(lon, lat float64) (x, y float64) {
+	return p.forward(lon, lat)
+}
+
+// Inverse returns the geographic coordinates of the projected point (x, y).
+func (p *Projection) Inverse(x, y float64) (lon, lat float64) {
+	return p.inverse(x, y)
+}
+
+// ForwardCoord projects c in place. It can be passed to geom.TransformInPlace.
+func (p *Projection) ForwardCoord(c geom.Coord) {
+	c[0], c[1] = p.forward(c[0], c[1])
+}
+
+// InverseCoord unprojects c in place. It can be passed to
+// geom.TransformInPlace.
+func (p *Projection) InverseCoord(c geom.Coord) {
+	c[0], c[1] = p.inverse(c[0], c[1])
+}
+
+// SRID returns the SRID of the projected coordinates of p, or zero if it is
+// unknown.
+func (p *Projection) SRID() int {
+	return p.srid
+}
+
+// GeographicSRID returns the SRID of the geographic coordinates of p, or
+// zero if it is unknown.
+func (p *Projection) GeographicSRID() int {
+	return p.geographicSRID
+}
+
+// WithSRID returns a copy of p whose projected coordinates have SRID srid.
+func (p *Projection) WithSRID(srid int) *Projection {
+	q := *p
+	q.srid = srid
+	return &q
+}
+
+// WithGeographicSRID returns a copy of p whose geographic coordinates have
+// SRID srid.
+func (p *Projection) WithGeographicSRID(srid int) *Projection {
+	q := *p
+	q.geographicSRID = srid
+	return &q
+}
+
+// Project returns a copy of g projected with p, with the SRID of p. The
+// ordinates other than x and y, such as Z and M, are unchanged. If g has an
+// SRID other than zero then it must be the geographic SRID of p.
+func (p *Projection) Project(g geom.T) (geom.T, error) {
+	return transform(g, p.geographicSRID, p.srid, p.ForwardCoord)
+}
+
+// Unproject returns a copy of g unprojected with p, with the geographic SRID
+// of p. The ordinates other than x and y, such as Z and M, are unchanged. If
+// g has an SRID other than zero then it must be the SRID of p.
+func (p *Projection) Unproject(g geom.T) (geom.T, error) {
+	return transform(g, p.srid, p.geographicSRID, p.InverseCoord)
+}
+
+// inUnits returns p with projected coordinates in units of toMeter meters.
+func (p *Projection) inUnits(toMeter float64) *Projection {
+	if toMeter == 1 {
+		return p
+	}
+	return &Projection{
+		forward: func(lon, lat float64) (x, y float64) {
+			x, y = p.forward(lon, lat)
+			return x / toMeter, y / toMeter
+		},
+		inverse: func(x, y float64) (lon, lat float64) {
+			return p.inverse(x*toMeter, y*toMeter)
+		},
+		srid:           p.srid,
+		geographicSRID: p.geographicSRID,
+	}
+}
+
+// transform returns a copy of g with f applied to each coordinate and with
+// SRID toSRID. It returns an error if g has an SRID other than zero or
+// fromSRID, unless fromSRID is zero.
+func transform(g geom.T, fromSRID, toSRID int, f func(geom.Coord)) (geom.T, error) {
+	if srid := g.SRID(); srid != 0 && fromSRID != 0 && srid != fromSRID {
+		return nil, ErrUnexpectedSRID{Expected: fromSRID, Actual: srid}
+	}
+	g, err := clone(g)
+	if err != nil {
+		return nil, err
+	}
+	transformInPlace(g, f)
+	return geom.SetSRID(g, toSRID)
+}
+
+// transformInPlace applies f to each coordinate of g, including the
+// coordinates of the children of geometry collections.
+func transformInPlace(g geom.T, f func(geom.Coord)) {
+	if gc, ok := g.(*geom.GeometryCollection); ok {
+		for _, child := range gc.Geoms() {
+			transformInPlace(child, f)
+		}
+		return
+	}
+	geom.TransformInPlace(g, f)
+}
+
+// clone returns a deep copy of g. The types of the ewkb package, which
+// implement geom.T by embedding the geom types, are unwrapped.
+func clone(g geom.T) (geom.T, error) {
+	switch g := g.(type) {
+	case *geom.Point:
+		return g.Clone(), nil
+	case *geom.MultiPoint:
+		return g.Clone(), nil
+	case *geom.LineString:
+		return g.Clone(), nil
+	case *geom.LinearRing:
+		return g.Clone(), nil
+	case *geom.MultiLineString:
+		return g.Clone(), nil
+	case *geom.Polygon:
+		return g.Clone(), nil
+	case *geom.MultiPolygon:
+		return g.Clone(), nil
+	case ewkb.Point:
+		return clone(g.Point)
+	case ewkb.LineString:
+		return clone(g.LineString)
+	case ewkb.Polygon:
+		return clone(g.Polygon)
+	case ewkb.MultiPoint:
+		return clone(g.MultiPoint)
+	case ewkb.MultiLineString:
+		return clone(g.MultiLineString)
+	case ewkb.MultiPolygon:
+		return clone(g.MultiPolygon)
+	case ewkb.GeometryCollection:
+		return clone(g.GeometryCollection)
+	case *geom.GeometryCollection:
+		result := geom.NewGeometryCollection().SetSRID(g.SRID())
+		for _, child := range g.Geoms() {
+			childClone, err := clone(child)
+			if err != nil {
+				return nil, err
+			}
+			if err := result.Push(childClone); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	default:
+		return nil, geom.ErrUnsupportedType{Value: g}
+	}
+}
+
+// geographicSRID returns the SRID of geographic coordinates on e, if it is
+// known.
+func geographicSRID(e *geodesic.Ellipsoid) int {
+	if e == geodesic.WGS84 {
+		return 4326
+	}
+	return 0
+}
+
+// ellipsoid holds the parameters of an ellipsoid that are used by the
+// projections.
+type ellipsoid struct {
+	a, f, e2, e float64
+}
+
+// newEllipsoid returns the parameters of the ellipsoid with equatorial radius
+// a and flattening f.
+func newEllipsoid(a, f float64) ellipsoid {
+	e2 := f * (2 - f)
+	return ellipsoid{a: a, f: f, e2: e2, e: math.Sqrt(e2)}
+}
+
+// parallelRadius returns the radius of the parallel at latitude lat divided
+// by the equatorial radius.
+func (e ellipsoid) parallelRadius(lat float64) float64 {
+	s, c := math.Sincos(lat * degree)
+	return c / math.Sqrt(1-e.e2*s*s)
+}
+
+// conformalTan returns the tangent of the conformal latitude for the
+// latitude with tangent tau.
+func (e ellipsoid) conformalTan(tau float64) float64 {
+	tau1 := math.Hypot(1, tau)
+	sig := math.Sinh(e.e * math.Atanh(e.e*tau/tau1))
+	return math.Hypot(1, sig)*tau - sig*tau1
+}
+
+// latitudeTan returns the tangent of the latitude for the conformal latitude
+// with tangent taup, the inverse of conformalTan, using Newton's method.
+func (e ellipsoid) latitudeTan(taup float64) float64 {
+	e2m := 1 - e.e2
+	tau := taup / e2m
+	stol := 1e-15 * max(1, math.Abs(taup))
+	for range 8 {
+		taupa := e.conformalTan(tau)
+		dtau := (taup - taupa) * (1 + e2m*tau*tau) / (e2m * math.Hypot(1, tau) * math.Hypot(1, taupa))
+		tau += dtau
+		if !(math.Abs(dtau) >= stol) {
+			break
+		}
+	}
+	return tau
+}
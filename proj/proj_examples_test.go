@@ -0,0 +1,53 @@
+package proj_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/proj"
+)
+
+func ExampleProjection_Project() {
+	london := geom.NewPointFlat(geom.XY, []float64{-0.1275, 51.507222}).SetSRID(4326)
+	projected, err := proj.WebMercator.Project(london)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%.2f %d\n", projected.FlatCoords(), projected.SRID())
+	// Output: [-14193.24 6711510.64] 3857
+}
+
+func ExampleProjectUTM() {
+	track := geom.NewLineStringFlat(geom.XY, []float64{6.0, 46.0, 6.1, 46.1}).SetSRID(4326)
+	projected, err := proj.ProjectUTM(track)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%.0f %d\n", projected.FlatCoords(), projected.SRID())
+	// Output: [267707 5098424 275855 5109247] 32632
+}
+
+func ExampleReproject() {
+	// The Eiffel Tower in Lambert-93.
+	tower := geom.NewPointFlat(geom.XY, []float64{2.2945, 48.8584}).SetSRID(4326)
+	reprojected, err := proj.Reproject(tower, 2154)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%.0f %d\n", reprojected.FlatCoords(), reprojected.SRID())
+	// Output: [648237 6862272] 2154
+}
+
+func ExampleRegistry_RegisterPROJ() {
+	registry := proj.NewRegistry()
+	if err := registry.RegisterPROJ(100001, "+proj=tmerc +lon_0=10 +k=1 +x_0=100000 +datum=WGS84 +units=km"); err != nil {
+		panic(err)
+	}
+	p := geom.NewPointFlat(geom.XY, []float64{10, 45}).SetSRID(4326)
+	reprojected, err := registry.Reproject(p, 100001)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%.3f %d\n", reprojected.FlatCoords(), reprojected.SRID())
+	// Output: [100.000 4984.944] 100001
+}
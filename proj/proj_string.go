@@ -0,0 +1,195 @@
+package proj
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+// An ErrUnsupportedDefinition is returned when a PROJ or WKT definition of a
+// CRS cannot be parsed or uses features that are not supported.
+type ErrUnsupportedDefinition struct {
+	Definition string
+	Reason     string
+}
+
+func (e ErrUnsupportedDefinition) Error() string {
+	return fmt.Sprintf("proj: %s: %s", e.Reason, e.Definition)
+}
+
+// projEllipsoids are the ellipsoids that can be named with +ellps.
+var projEllipsoids = map[string]*geodesic.Ellipsoid{
+	"WGS84":  geodesic.WGS84,
+	"GRS80":  grs80,
+	"airy":   airy1830,
+	"bessel": bessel1841,
+	"clrk66": clarke1866,
+	"intl":   intl1924,
+}
+
+// projDatums are the datums that can be named with +datum.
+var projDatums = map[string]CRS{
+	"WGS84":  {Ellipsoid: geodesic.WGS84},
+	"NAD83":  {Ellipsoid: grs80},
+	"OSGB36": {Ellipsoid: airy1830, ToWGS84: osgb36ToWGS84},
+	"potsdam": {
+		Ellipsoid: bessel1841,
+		ToWGS84:   []float64{598.1, 73.7, 418.2, 0.202, 0.045, -2.455, 6.7},
+	},
+}
+
+// projUnits are the linear units that can be named with +units, in meters.
+var projUnits = map[string]float64{
+	"m":     1,
+	"km":    1000,
+	"ft":    0.3048,
+	"us-ft": 1200.0 / 3937,
+}
+
+// ParsePROJ returns the CRS defined by the PROJ string s, for example
+// "+proj=utm +zone=32 +datum=WGS84 +units=m +no_defs". The supported
+// projections are longlat, merc, webmerc, tmerc, utm, and lcc, with the
+// parameters +lat_0, +lon_0, +lat_1, +lat_2, +lat_ts, +k or +k_0, +x_0,
+// +y_0, +zone, and +south. The datum is given by +datum, +towgs84,
+// +nadgrids=@null, or neither, in which case it is treated as equivalent to
+// WGS84, and the ellipsoid is given by +ellps, +R, or +a with +b, +rf, or
+// +f. The units are given by +units or +to_meter. Any other parameter,
+// except +no_defs, +type=crs, and +wktext, is an error.
+func ParsePROJ(s string) (*CRS, error) {
+	unsupported := func(format string, args ...any) error {
+		return ErrUnsupportedDefinition{Definition: s, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	params := make(map[string]string)
+	for _, field := range strings.Fields(s) {
+		key, value, _ := strings.Cut(strings.TrimPrefix(field, "+"), "=")
+		if !strings.HasPrefix(field, "+") || key == "" {
+			return nil, unsupported("invalid parameter %q", field)
+		}
+		params[key] = value
+	}
+	floats := make(map[string]float64)
+	for key, value := range params {
+		switch key {
+		case "a", "b", "f", "rf", "R", "lat_0", "lon_0", "lat_1", "lat_2", "lat_ts", "k", "k_0", "x_0", "y_0", "to_meter":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, unsupported("invalid value for +%s", key)
+			}
+			floats[key] = f
+		case "proj", "datum", "ellps", "towgs84", "nadgrids", "units", "zone", "south", "no_defs", "type", "wktext":
+		default:
+			return nil, unsupported("unsupported parameter +%s", key)
+		}
+	}
+	float := func(key string, defaultValue float64) float64 {
+		if f, ok := floats[key]; ok {
+			return f
+		}
+		return defaultValue
+	}
+
+	crs := &CRS{Ellipsoid: geodesic.WGS84}
+	if name, ok := params["datum"]; ok {
+		datum, ok := projDatums[name]
+		if !ok {
+			return nil, unsupported("unsupported datum %q", name)
+		}
+		*crs = datum
+		crs.ToWGS84 = slices.Clone(datum.ToWGS84)
+	}
+	if name, ok := params["ellps"]; ok {
+		e, ok := projEllipsoids[name]
+		if !ok {
+			return nil, unsupported("unsupported ellipsoid %q", name)
+		}
+		crs.Ellipsoid = e
+	}
+	if r, ok := floats["R"]; ok {
+		crs.Ellipsoid = geodesic.NewEllipsoid(r, 0)
+	}
+	if a, ok := floats["a"]; ok {
+		var f float64
+		switch {
+		case floats["b"] != 0:
+			f = (a - floats["b"]) / a
+		case floats["rf"] != 0:
+			f = 1 / floats["rf"]
+		default:
+			f = floats["f"]
+		}
+		crs.Ellipsoid = geodesic.NewEllipsoid(a, f)
+	}
+	if value, ok := params["towgs84"]; ok {
+		fields := strings.Split(value, ",")
+		if len(fields) != 3 && len(fields) != 7 {
+			return nil, unsupported("+towgs84 must have 3 or 7 parameters")
+		}
+		crs.ToWGS84 = make([]float64, len(fields))
+		for i, field := range fields {
+			f, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, unsupported("invalid value for +towgs84")
+			}
+			crs.ToWGS84[i] = f
+		}
+	}
+	// The null grid means that the coordinates are WGS84 coordinates,
+	// whatever the ellipsoid of the projection.
+	e := crs.Ellipsoid
+	if value, ok := params["nadgrids"]; ok {
+		if value != "@null" {
+			return nil, unsupported("unsupported grid %q", value)
+		}
+		crs.Ellipsoid, crs.ToWGS84 = geodesic.WGS84, nil
+	}
+
+	toMeter := 1.0
+	if name, ok := params["units"]; ok {
+		if toMeter, ok = projUnits[name]; !ok {
+			return nil, unsupported("unsupported units %q", name)
+		}
+	}
+	toMeter = float("to_meter", toMeter)
+
+	lat0, lon0 := float("lat_0", 0), float("lon_0", 0)
+	k0 := float("k_0", float("k", 1))
+	x0, y0 := float("x_0", 0), float("y_0", 0)
+	switch name := params["proj"]; name {
+	case "longlat", "latlong", "lonlat", "latlon":
+		return crs, nil
+	case "merc":
+		if latTS, ok := floats["lat_ts"]; ok {
+			k0 = newEllipsoid(e.EquatorialRadius(), e.Flattening()).parallelRadius(latTS)
+		}
+		crs.Projection = NewMercator(e, lon0, k0, x0, y0)
+	case "webmerc":
+		crs.Projection = NewMercator(geodesic.NewEllipsoid(e.EquatorialRadius(), 0), lon0, 1, x0, y0)
+	case "tmerc":
+		crs.Projection = NewTransverseMercator(e, lon0, lat0, k0, x0, y0)
+	case "utm":
+		zone, err := strconv.Atoi(params["zone"])
+		if err != nil || zone < 1 || 60 < zone {
+			return nil, unsupported("invalid +zone")
+		}
+		y0 = 0
+		if _, south := params["south"]; south {
+			y0 = 10000000
+		}
+		crs.Projection = NewTransverseMercator(e, float64(6*zone-183), 0, 0.9996, 500000, y0)
+	case "lcc":
+		lat1, ok := floats["lat_1"]
+		if !ok {
+			return nil, unsupported("+lat_1 is required")
+		}
+		lat2 := float("lat_2", lat1)
+		crs.Projection = newLambertConformalConic(e, lat1, lat2, float("lat_0", lat1), lon0, k0, x0, y0)
+	default:
+		return nil, unsupported("unsupported projection %q", name)
+	}
+	crs.Projection = crs.Projection.inUnits(toMeter)
+	return crs, nil
+}
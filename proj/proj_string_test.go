@@ -0,0 +1,163 @@
+package proj_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom/geodesic"
+	"github.com/twpayne/go-geom/proj"
+)
+
+func TestParsePROJ(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		s               string
+		expectedSRID    int
+		expectedToWGS84 []float64
+		lon, lat        float64
+	}{
+		{
+			name:         "longlat",
+			s:            "+proj=longlat +datum=WGS84 +no_defs +type=crs",
+			expectedSRID: 4326,
+			lon:          -0.1275,
+			lat:          51.507222,
+		},
+		{
+			name:         "utm",
+			s:            "+proj=utm +zone=31 +datum=WGS84 +units=m +no_defs",
+			expectedSRID: 32631,
+			lon:          2.35,
+			lat:          48.85,
+		},
+		{
+			name:         "utm_south",
+			s:            "+proj=utm +zone=56 +south +ellps=WGS84 +towgs84=0,0,0,0,0,0,0 +units=m +no_defs",
+			expectedSRID: 32756,
+			lon:          151.2,
+			lat:          -33.9,
+		},
+		{
+			name:            "tmerc",
+			s:               "+proj=tmerc +lat_0=49 +lon_0=-2 +k=0.9996012717 +x_0=400000 +y_0=-100000 +ellps=airy +towgs84=446.448,-125.157,542.06,0.15,0.247,0.842,-20.489 +units=m +no_defs",
+			expectedSRID:    27700,
+			expectedToWGS84: []float64{446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489},
+			lon:             -0.1275,
+			lat:             51.507222,
+		},
+		{
+			name:            "tmerc_datum",
+			s:               "+proj=tmerc +lat_0=49 +lon_0=-2 +k=0.9996012717 +x_0=400000 +y_0=-100000 +datum=OSGB36 +units=m +no_defs",
+			expectedSRID:    27700,
+			expectedToWGS84: []float64{446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489},
+			lon:             -0.1275,
+			lat:             51.507222,
+		},
+		{
+			name:         "lcc",
+			s:            "+proj=lcc +lat_0=46.5 +lon_0=3 +lat_1=49 +lat_2=44 +x_0=700000 +y_0=6600000 +ellps=GRS80 +towgs84=0,0,0,0,0,0,0 +units=m +no_defs",
+			expectedSRID: 2154,
+			lon:          2.35,
+			lat:          48.85,
+		},
+		{
+			name:         "google",
+			s:            "+proj=merc +a=6378137 +b=6378137 +lat_ts=0 +lon_0=0 +x_0=0 +y_0=0 +k=1 +units=m +nadgrids=@null +wktext +no_defs",
+			expectedSRID: 3857,
+			lon:          -0.1275,
+			lat:          51.507222,
+		},
+		{
+			name:         "webmerc",
+			s:            "+proj=webmerc +datum=WGS84",
+			expectedSRID: 3857,
+			lon:          151.2,
+			lat:          -33.9,
+		},
+		{
+			name:         "world_mercator",
+			s:            "+proj=merc +lon_0=0 +k=1 +x_0=0 +y_0=0 +datum=WGS84 +units=m +no_defs",
+			expectedSRID: 3395,
+			lon:          151.2,
+			lat:          -33.9,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			crs, err := proj.ParsePROJ(tc.s)
+			assert.NoError(t, err)
+			expected, err := proj.Lookup(tc.expectedSRID)
+			assert.NoError(t, err)
+			assertInDelta(t, expected.Ellipsoid.EquatorialRadius(), crs.Ellipsoid.EquatorialRadius(), 1e-9)
+			assertInDelta(t, expected.Ellipsoid.Flattening(), crs.Ellipsoid.Flattening(), 1e-12)
+			if tc.expectedToWGS84 != nil {
+				assert.Equal(t, tc.expectedToWGS84, crs.ToWGS84)
+			}
+			assert.Equal(t, expected.Projection == nil, crs.Projection == nil)
+			if crs.Projection != nil {
+				expectedX, expectedY := expected.Projection.Forward(tc.lon, tc.lat)
+				x, y := crs.Projection.Forward(tc.lon, tc.lat)
+				assertInDelta(t, expectedX, x, 1e-6)
+				assertInDelta(t, expectedY, y, 1e-6)
+			}
+		})
+	}
+}
+
+func TestParsePROJUnits(t *testing.T) {
+	// The NAD27 Texas South Central example from EPSG Guidance Note 7-2.
+	crs, err := proj.ParsePROJ("+proj=lcc +lat_1=28.38333333333333 +lat_2=30.28333333333333 +lat_0=27.83333333333333 +lon_0=-99 +x_0=609601.2192024384 +y_0=0 +ellps=clrk66 +units=us-ft +no_defs")
+	assert.NoError(t, err)
+	x, y := crs.Projection.Forward(-96, 28.5)
+	assertInDelta(t, 2963503.91, x, 0.01)
+	assertInDelta(t, 254759.80, y, 0.01)
+	lon, lat := crs.Projection.Inverse(x, y)
+	assertInDelta(t, -96, lon, 1e-9)
+	assertInDelta(t, 28.5, lat, 1e-9)
+
+	crs, err = proj.ParsePROJ("+proj=longlat +R=6371000")
+	assert.NoError(t, err)
+	assert.Equal(t, 6371000.0, crs.Ellipsoid.EquatorialRadius())
+	assert.Equal(t, 0.0, crs.Ellipsoid.Flattening())
+
+	crs, err = proj.ParsePROJ("+proj=longlat +a=6378137 +rf=298.257222101")
+	assert.NoError(t, err)
+	assertInDelta(t, 1/298.257222101, crs.Ellipsoid.Flattening(), 1e-15)
+	assert.NotEqual(t, geodesic.WGS84, crs.Ellipsoid)
+}
+
+func TestParsePROJDatumNotShared(t *testing.T) {
+	const s = "+proj=longlat +datum=OSGB36 +no_defs"
+	crs, err := proj.ParsePROJ(s)
+	assert.NoError(t, err)
+	crs.ToWGS84[0] = 0
+	crs, err = proj.ParsePROJ(s)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489}, crs.ToWGS84)
+}
+
+func TestParsePROJErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"proj=longlat",
+		"+proj=aea +lat_1=29.5 +lat_2=45.5",
+		"+proj=utm",
+		"+proj=utm +zone=61",
+		"+proj=lcc +lat_0=46.5",
+		"+proj=longlat +foo=bar",
+		"+proj=longlat +datum=NAD27",
+		"+proj=longlat +ellps=unknown",
+		"+proj=longlat +nadgrids=conus",
+		"+proj=longlat +towgs84=1,2",
+		"+proj=tmerc +lon_0=x",
+		"+proj=tmerc +units=furlong",
+	} {
+		t.Run(s, func(t *testing.T) {
+			_, err := proj.ParsePROJ(s)
+			var errUnsupportedDefinition proj.ErrUnsupportedDefinition
+			assert.True(t, errors.As(err, &errUnsupportedDefinition))
+			assert.Equal(t, s, errUnsupportedDefinition.Definition)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package proj_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/proj"
+)
+
+func assertInDelta(t *testing.T, expected, actual, delta float64) {
+	t.Helper()
+	if math.Abs(expected-actual) > delta {
+		t.Errorf("expected %v ± %v but got %v", expected, delta, actual)
+	}
+}
+
+func assertFlatCoordsInDelta(t *testing.T, expected, actual []float64, delta float64) {
+	t.Helper()
+	assert.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		assertInDelta(t, expected[i], actual[i], delta)
+	}
+}
+
+func TestProjectionProject(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		g           geom.T
+		expected    []float64
+		expectedErr error
+	}{
+		{
+			name:     "point",
+			g:        geom.NewPointFlat(geom.XYZ, []float64{180, 0, 100}).SetSRID(4326),
+			expected: []float64{20037508.342789244, 0, 100},
+		},
+		{
+			name:     "linestring_without_srid",
+			g:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, -90, 0}),
+			expected: []float64{0, 0, -10018754.171394622, 0},
+		},
+		{
+			name:        "unexpected_srid",
+			g:           geom.NewPointFlat(geom.XY, []float64{0, 0}).SetSRID(3857),
+			expectedErr: proj.ErrUnexpectedSRID{Expected: 4326, Actual: 3857},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := proj.WebMercator.Project(tc.g)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 3857, actual.SRID())
+			assertFlatCoordsInDelta(t, tc.expected, actual.FlatCoords(), 1e-8)
+		})
+	}
+}
+
+func TestProjectionUnproject(t *testing.T) {
+	polygon := geom.NewPolygonFlat(geom.XYM, []float64{
+		0, 0, 1,
+		1000000, 0, 2,
+		1000000, 1000000, 3,
+		0, 0, 1,
+	}, []int{12}).SetSRID(3857)
+	collection := geom.NewGeometryCollection().MustPush(
+		geom.NewPointFlat(geom.XY, []float64{-1000000, 2000000}),
+		polygon,
+	).SetSRID(3857)
+	actual, err := proj.WebMercator.Unproject(collection)
+	assert.NoError(t, err)
+	assert.Equal(t, 4326, actual.SRID())
+	children := actual.(*geom.GeometryCollection).Geoms()
+	assertFlatCoordsInDelta(t, []float64{-8.983152841195214, 17.678914238335743}, children[0].FlatCoords(), 1e-12)
+	assertFlatCoordsInDelta(t, []float64{
+		0, 0, 1,
+		8.983152841195214, 0, 2,
+		8.983152841195214, 8.946573850543425, 3,
+		0, 0, 1,
+	}, children[1].FlatCoords(), 1e-12)
+	// The input is not modified.
+	assert.Equal(t, 1000000.0, polygon.FlatCoords()[3])
+
+	_, err = proj.WebMercator.Unproject(geom.NewPointFlat(geom.XY, []float64{0, 0}).SetSRID(4326))
+	assert.Equal[error](t, proj.ErrUnexpectedSRID{Expected: 3857, Actual: 4326}, err)
+}
+
+func TestProjectionWithSRID(t *testing.T) {
+	p := proj.WebMercator.WithSRID(900913).WithGeographicSRID(4979)
+	assert.Equal(t, 900913, p.SRID())
+	assert.Equal(t, 4979, p.GeographicSRID())
+	assert.Equal(t, 3857, proj.WebMercator.SRID())
+	assert.Equal(t, 4326, proj.WebMercator.GeographicSRID())
+}
+
+func TestTransformInPlace(t *testing.T) {
+	ls := geom.NewLineStringFlat(geom.XY, []float64{-90, 10, 90, -10})
+	geom.TransformInPlace(ls, proj.WebMercator.ForwardCoord)
+	geom.TransformInPlace(ls, proj.WebMercator.InverseCoord)
+	assertFlatCoordsInDelta(t, []float64{-90, 10, 90, -10}, ls.FlatCoords(), 1e-12)
+}
@@ -0,0 +1,160 @@
+package proj
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+// An ErrInvalidUTMZone is returned when a UTM zone is not in the range [1,
+// 60].
+type ErrInvalidUTMZone int
+
+func (e ErrInvalidUTMZone) Error() string {
+	return fmt.Sprintf("proj: invalid UTM zone %d", int(e))
+}
+
+// tmOrder is the order of the series used by the transverse Mercator
+// projection.
+const tmOrder = 6
+
+// A transverseMercator is a transverse Mercator projection computed with
+// Krüger's series to sixth order in the third flattening, which is accurate
+// to a few nanometers within 4000km of the central meridian on the WGS84
+// ellipsoid. See C. F. F. Karney, Transverse Mercator with an accuracy of a
+// few nanometers, J. Geodesy 85, 475-485 (2011),
+// https://doi.org/10.1007/s00190-011-0445-3.
+type transverseMercator struct {
+	ellipsoid
+	lon0, k0, x0, y0 float64
+	// a1 is the rectifying radius and xi0 is the rectifying latitude of the
+	// latitude of origin.
+	a1, xi0  float64
+	alp, bet [tmOrder + 1]float64
+}
+
+// NewTransverseMercator returns the transverse Mercator projection of
+// coordinates on e with central meridian lon0, latitude of origin lat0,
+// scale factor k0 on the central meridian, false easting x0, and false
+// northing y0. The projected coordinates are in the units of the equatorial
+// radius of e and have SRID zero. The geographic coordinates have SRID 4326
+// if e is geodesic.WGS84 and zero otherwise. e must not be prolate.
+func NewTransverseMercator(e *geodesic.Ellipsoid, lon0, lat0, k0, x0, y0 float64) *Projection {
+	tm := &transverseMercator{
+		ellipsoid: newEllipsoid(e.EquatorialRadius(), e.Flattening()),
+		lon0:      lon0,
+		k0:        k0,
+		x0:        x0,
+		y0:        y0,
+	}
+	n := tm.f / (2 - tm.f)
+	n2 := n * n
+	tm.a1 = tm.a / (1 + n) * (1 + n2*(1.0/4+n2*(1.0/64+n2/256)))
+	tm.alp = [tmOrder + 1]float64{
+		0,
+		n * (1.0/2 + n*(-2.0/3+n*(5.0/16+n*(41.0/180+n*(-127.0/288+n*7891.0/37800))))),
+		n2 * (13.0/48 + n*(-3.0/5+n*(557.0/1440+n*(281.0/630+n*-1983433.0/1935360)))),
+		n2 * n * (61.0/240 + n*(-103.0/140+n*(15061.0/26880+n*167603.0/181440))),
+		n2 * n2 * (49561.0/161280 + n*(-179.0/168+n*6601661.0/7257600)),
+		n2 * n2 * n * (34729.0/80640 + n*-3418889.0/1995840),
+		n2 * n2 * n2 * 212378941.0 / 319334400,
+	}
+	tm.bet = [tmOrder + 1]float64{
+		0,
+		n * (1.0/2 + n*(-2.0/3+n*(37.0/96+n*(-1.0/360+n*(-81.0/512+n*96199.0/604800))))),
+		n2 * (1.0/48 + n*(1.0/15+n*(-437.0/1440+n*(46.0/105+n*-1118711.0/3870720)))),
+		n2 * n * (17.0/480 + n*(-37.0/840+n*(-209.0/4480+n*5569.0/90720))),
+		n2 * n2 * (4397.0/161280 + n*(-11.0/504+n*-830251.0/7257600)),
+		n2 * n2 * n * (4583.0/161280 + n*-108847.0/3991680),
+		n2 * n2 * n2 * 20648693.0 / 638668800,
+	}
+	tm.xi0, _ = tm.rectify(math.Atan(tm.conformalTan(math.Tan(lat0*degree))), 0)
+	return &Projection{
+		forward:        tm.forward,
+		inverse:        tm.inverse,
+		geographicSRID: geographicSRID(e),
+	}
+}
+
+// NewUTM returns the Universal Transverse Mercator projection of WGS84
+// coordinates, EPSG:4326, for zone in the northern hemisphere if north is
+// true and the southern hemisphere otherwise. The projected coordinates have
+// SRID 32600 + zone in the northern hemisphere and 32700 + zone in the
+// southern hemisphere.
+func NewUTM(zone int, north bool) (*Projection, error) {
+	if zone < 1 || 60 < zone {
+		return nil, ErrInvalidUTMZone(zone)
+	}
+	y0, srid := 0.0, 32600+zone
+	if !north {
+		y0, srid = 10000000, 32700+zone
+	}
+	return NewTransverseMercator(geodesic.WGS84, float64(6*zone-183), 0, 0.9996, 500000, y0).WithSRID(srid), nil
+}
+
+// UTMZone returns the UTM zone of the point (lon, lat) and whether it is in
+// the northern hemisphere, including the exceptions for southwest Norway and
+// Svalbard.
+func UTMZone(lon, lat float64) (zone int, north bool) {
+	lon = math.Remainder(lon, 360)
+	zone = min(int(math.Floor((lon+180)/6))+1, 60)
+	switch {
+	case 56 <= lat && lat < 64 && 3 <= lon && lon < 12:
+		zone = 32
+	case 72 <= lat && lat <= 84 && 0 <= lon && lon < 42:
+		// Svalbard uses only the odd zones 31, 33, 35, and 37.
+		zone = 2*int(math.Floor((lon+3)/12)) + 31
+	}
+	return zone, lat >= 0
+}
+
+// ProjectUTM returns a copy of g, which must have WGS84 coordinates,
+// projected into the UTM zone of the center of its bounds. g must not be
+// empty.
+func ProjectUTM(g geom.T) (geom.T, error) {
+	if g.Empty() {
+		return nil, errEmptyGeometry
+	}
+	bounds := g.Bounds()
+	zone, north := UTMZone((bounds.Min(0)+bounds.Max(0))/2, (bounds.Min(1)+bounds.Max(1))/2)
+	utm, err := NewUTM(zone, north)
+	if err != nil {
+		return nil, err
+	}
+	return utm.Project(g)
+}
+
+func (tm *transverseMercator) forward(lon, lat float64) (x, y float64) {
+	slam, clam := math.Sincos(math.Remainder(lon-tm.lon0, 360) * degree)
+	taup := tm.conformalTan(math.Tan(lat * degree))
+	xi, eta := tm.rectify(math.Atan2(taup, clam), math.Asinh(slam/math.Hypot(taup, clam)))
+	return tm.x0 + tm.k0*tm.a1*eta, tm.y0 + tm.k0*tm.a1*(xi-tm.xi0)
+}
+
+func (tm *transverseMercator) inverse(x, y float64) (lon, lat float64) {
+	xi := (y-tm.y0)/(tm.k0*tm.a1) + tm.xi0
+	eta := (x - tm.x0) / (tm.k0 * tm.a1)
+	xip, etap := xi, eta
+	for j := 1; j <= tmOrder; j++ {
+		s, c := math.Sincos(float64(2*j) * xi)
+		xip -= tm.bet[j] * s * math.Cosh(float64(2*j)*eta)
+		etap -= tm.bet[j] * c * math.Sinh(float64(2*j)*eta)
+	}
+	s, c := math.Sinh(etap), math.Cos(xip)
+	tau := tm.latitudeTan(math.Sin(xip) / math.Hypot(s, c))
+	return math.Remainder(tm.lon0+math.Atan2(s, c)/degree, 360), math.Atan(tau) / degree
+}
+
+// rectify returns the rectifying coordinates (xi, eta) of the conformal
+// coordinates (xip, etap) on a sphere.
+func (tm *transverseMercator) rectify(xip, etap float64) (xi, eta float64) {
+	xi, eta = xip, etap
+	for j := 1; j <= tmOrder; j++ {
+		s, c := math.Sincos(float64(2*j) * xip)
+		xi += tm.alp[j] * s * math.Cosh(float64(2*j)*etap)
+		eta += tm.alp[j] * c * math.Sinh(float64(2*j)*etap)
+	}
+	return xi, eta
+}
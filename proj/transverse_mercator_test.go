@@ -0,0 +1,138 @@
+package proj_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/geodesic"
+	"github.com/twpayne/go-geom/proj"
+)
+
+func TestTransverseMercator(t *testing.T) {
+	// The British National Grid example from EPSG Guidance Note 7-2.
+	airy := geodesic.NewEllipsoid(6377563.396, 1/299.3249646)
+	bng := proj.NewTransverseMercator(airy, -2, 49, 0.9996012717, 400000, -100000)
+	assert.Equal(t, 0, bng.SRID())
+	assert.Equal(t, 0, bng.GeographicSRID())
+	x, y := bng.Forward(0.5, 50.5)
+	assertInDelta(t, 577274.99, x, 0.01)
+	assertInDelta(t, 69740.50, y, 0.01)
+	lon, lat := bng.Inverse(x, y)
+	assertInDelta(t, 0.5, lon, 1e-12)
+	assertInDelta(t, 50.5, lat, 1e-12)
+}
+
+func TestTransverseMercatorRoundTrip(t *testing.T) {
+	tm := proj.NewTransverseMercator(geodesic.WGS84, 9, 0, 1, 0, 0)
+	assert.Equal(t, 4326, tm.GeographicSRID())
+	for _, lat := range []float64{-89, -60, -30, 0, 30, 60, 89} {
+		for _, lon := range []float64{-21, -6, 0, 6, 9, 24, 39} {
+			x, y := tm.Forward(lon, lat)
+			actualLon, actualLat := tm.Inverse(x, y)
+			assertInDelta(t, lon, actualLon, 1e-12)
+			assertInDelta(t, lat, actualLat, 1e-12)
+		}
+	}
+	// The distance from the equator to the pole along the central meridian
+	// is the length of the meridian quadrant.
+	_, y := tm.Forward(9, 90)
+	assertInDelta(t, 10001965.729, y, 1e-3)
+}
+
+func TestNewUTM(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		zone         int
+		north        bool
+		lon, lat     float64
+		expectedSRID int
+		x, y         float64
+	}{
+		{
+			name:         "31N",
+			zone:         31,
+			north:        true,
+			lon:          3,
+			expectedSRID: 32631,
+			x:            500000,
+		},
+		{
+			name:         "31S",
+			zone:         31,
+			lon:          3,
+			expectedSRID: 32731,
+			x:            500000,
+			y:            10000000,
+		},
+		{
+			name:         "1N",
+			zone:         1,
+			north:        true,
+			lon:          -177,
+			lat:          90,
+			expectedSRID: 32601,
+			x:            500000,
+			y:            9997964.943,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			utm, err := proj.NewUTM(tc.zone, tc.north)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedSRID, utm.SRID())
+			assert.Equal(t, 4326, utm.GeographicSRID())
+			x, y := utm.Forward(tc.lon, tc.lat)
+			assertInDelta(t, tc.x, x, 1e-3)
+			assertInDelta(t, tc.y, y, 1e-3)
+		})
+	}
+}
+
+func TestNewUTMInvalidZone(t *testing.T) {
+	for _, zone := range []int{0, 61} {
+		_, err := proj.NewUTM(zone, true)
+		assert.Equal[error](t, proj.ErrInvalidUTMZone(zone), err)
+	}
+}
+
+func TestUTMZone(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		lon, lat      float64
+		expectedZone  int
+		expectedNorth bool
+	}{
+		{name: "greenwich", lon: 0, lat: 51.5, expectedZone: 31, expectedNorth: true},
+		{name: "west_of_greenwich", lon: -0.1, lat: 51.5, expectedZone: 30, expectedNorth: true},
+		{name: "antimeridian_west", lon: -180, lat: 0, expectedZone: 1, expectedNorth: true},
+		{name: "antimeridian_east", lon: 180, lat: 0, expectedZone: 60, expectedNorth: true},
+		{name: "wrapped", lon: 183, lat: -10, expectedZone: 1},
+		{name: "sydney", lon: 151.2, lat: -33.9, expectedZone: 56},
+		{name: "bergen", lon: 5.3, lat: 60.4, expectedZone: 32, expectedNorth: true},
+		{name: "svalbard_31", lon: 8, lat: 78, expectedZone: 31, expectedNorth: true},
+		{name: "svalbard_33", lon: 15.6, lat: 78.2, expectedZone: 33, expectedNorth: true},
+		{name: "svalbard_37", lon: 33, lat: 80, expectedZone: 37, expectedNorth: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			zone, north := proj.UTMZone(tc.lon, tc.lat)
+			assert.Equal(t, tc.expectedZone, zone)
+			assert.Equal(t, tc.expectedNorth, north)
+		})
+	}
+}
+
+func TestProjectUTM(t *testing.T) {
+	ls := geom.NewLineStringFlat(geom.XY, []float64{150.9, -34.1, 151.5, -33.7}).SetSRID(4326)
+	actual, err := proj.ProjectUTM(ls)
+	assert.NoError(t, err)
+	assert.Equal(t, 32756, actual.SRID())
+	utm, err := proj.NewUTM(56, false)
+	assert.NoError(t, err)
+	unprojected, err := utm.Unproject(actual)
+	assert.NoError(t, err)
+	assertFlatCoordsInDelta(t, ls.FlatCoords(), unprojected.FlatCoords(), 1e-12)
+
+	_, err = proj.ProjectUTM(geom.NewLineString(geom.XY))
+	assert.Error(t, err)
+}
@@ -0,0 +1,269 @@
+package proj
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/twpayne/go-geom/geodesic"
+)
+
+// A wktNode is a node of a WKT CRS definition, a keyword followed by values
+// in brackets. Each value is a string, a float64, or a *wktNode.
+type wktNode struct {
+	keyword string
+	values  []any
+}
+
+// child returns the first child of n with keyword.
+func (n *wktNode) child(keyword string) *wktNode {
+	for _, value := range n.values {
+		if child, ok := value.(*wktNode); ok && strings.EqualFold(child.keyword, keyword) {
+			return child
+		}
+	}
+	return nil
+}
+
+// numbers returns the numeric values of n.
+func (n *wktNode) numbers() []float64 {
+	var numbers []float64
+	for _, value := range n.values {
+		if number, ok := value.(float64); ok {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+// name returns the first value of n if it is a string.
+func (n *wktNode) name() string {
+	if len(n.values) == 0 {
+		return ""
+	}
+	name, _ := n.values[0].(string)
+	return name
+}
+
+// A wktParser parses WKT CRS definitions.
+type wktParser struct {
+	s   string
+	pos int
+}
+
+// skipSpace skips white space.
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// node parses a node.
+func (p *wktParser) node() (*wktNode, bool) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] == '_' || 'A' <= p.s[p.pos] && p.s[p.pos] <= 'Z' || 'a' <= p.s[p.pos] && p.s[p.pos] <= 'z' || '0' <= p.s[p.pos] && p.s[p.pos] <= '9') {
+		p.pos++
+	}
+	n := &wktNode{keyword: p.s[start:p.pos]}
+	p.skipSpace()
+	if n.keyword == "" || p.pos == len(p.s) || (p.s[p.pos] != '[' && p.s[p.pos] != '(') {
+		return n, n.keyword != ""
+	}
+	closing := byte(']')
+	if p.s[p.pos] == '(' {
+		closing = ')'
+	}
+	p.pos++
+	for {
+		p.skipSpace()
+		if p.pos == len(p.s) {
+			return nil, false
+		}
+		switch c := p.s[p.pos]; {
+		case c == '"':
+			end := strings.IndexByte(p.s[p.pos+1:], '"')
+			if end < 0 {
+				return nil, false
+			}
+			n.values = append(n.values, p.s[p.pos+1:p.pos+1+end])
+			p.pos += end + 2
+		case c == '-' || c == '+' || c == '.' || '0' <= c && c <= '9':
+			start := p.pos
+			for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+				p.pos++
+			}
+			number, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+			if err != nil {
+				return nil, false
+			}
+			n.values = append(n.values, number)
+		default:
+			child, ok := p.node()
+			if !ok {
+				return nil, false
+			}
+			if child.values == nil {
+				n.values = append(n.values, child.keyword)
+			} else {
+				n.values = append(n.values, child)
+			}
+		}
+		p.skipSpace()
+		if p.pos == len(p.s) {
+			return nil, false
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case closing:
+			p.pos++
+			return n, true
+		default:
+			return nil, false
+		}
+	}
+}
+
+// wktDatumToWGS84 are the Helmert parameters of datums that are commonly
+// given without a TOWGS84 node.
+var wktDatumToWGS84 = map[string][]float64{
+	"OSGB_1936":   osgb36ToWGS84,
+	"D_OSGB_1936": osgb36ToWGS84,
+}
+
+// ParseWKT returns the CRS defined by the WKT string s. The supported subset
+// is WKT 1 GEOGCS and PROJCS definitions, as used by .prj files and by the
+// srtext column of PostGIS's spatial_ref_sys table, with the Greenwich prime
+// meridian, angular units of degrees, and the projections
+// Transverse_Mercator, Lambert_Conformal_Conic_1SP,
+// Lambert_Conformal_Conic_2SP, Mercator_1SP, Mercator_2SP, and
+// Popular_Visualisation_Pseudo_Mercator. Datums without a TOWGS84 node are
+// treated as equivalent to WGS84, except for OSGB 1936. If the definition
+// has a PROJ4 EXTENSION node then its PROJ string is used instead.
+func ParseWKT(s string) (*CRS, error) {
+	unsupported := func(reason string) error {
+		return ErrUnsupportedDefinition{Definition: s, Reason: reason}
+	}
+
+	p := &wktParser{s: s}
+	root, ok := p.node()
+	p.skipSpace()
+	if !ok || root.values == nil || p.pos != len(s) {
+		return nil, unsupported("invalid WKT")
+	}
+
+	// GDAL writes CRSs that cannot be described in WKT 1, such as Web
+	// Mercator, with a PROJ string extension.
+	if extension := root.child("EXTENSION"); extension != nil && strings.EqualFold(extension.name(), "PROJ4") && len(extension.values) == 2 {
+		if projString, ok := extension.values[1].(string); ok {
+			return ParsePROJ(projString)
+		}
+	}
+
+	var geogcs *wktNode
+	switch strings.ToUpper(root.keyword) {
+	case "GEOGCS":
+		geogcs = root
+	case "PROJCS":
+		if geogcs = root.child("GEOGCS"); geogcs == nil {
+			return nil, unsupported("missing GEOGCS")
+		}
+	default:
+		return nil, unsupported("unsupported WKT keyword " + root.keyword)
+	}
+
+	datum := geogcs.child("DATUM")
+	if datum == nil {
+		return nil, unsupported("missing DATUM")
+	}
+	spheroid := datum.child("SPHEROID")
+	if spheroid == nil || len(spheroid.numbers()) < 2 {
+		return nil, unsupported("missing SPHEROID")
+	}
+	a, rf := spheroid.numbers()[0], spheroid.numbers()[1]
+	crs := &CRS{}
+	switch {
+	case rf == 0:
+		crs.Ellipsoid = geodesic.NewEllipsoid(a, 0)
+	case a == 6378137 && rf == 298.257223563:
+		crs.Ellipsoid = geodesic.WGS84
+	default:
+		crs.Ellipsoid = geodesic.NewEllipsoid(a, 1/rf)
+	}
+	if toWGS84 := datum.child("TOWGS84"); toWGS84 != nil {
+		crs.ToWGS84 = toWGS84.numbers()
+		if n := len(crs.ToWGS84); n != 3 && n != 7 {
+			return nil, unsupported("TOWGS84 must have 3 or 7 parameters")
+		}
+	} else {
+		crs.ToWGS84 = slices.Clone(wktDatumToWGS84[datum.name()])
+	}
+	if primem := geogcs.child("PRIMEM"); primem != nil {
+		if numbers := primem.numbers(); len(numbers) != 0 && numbers[0] != 0 {
+			return nil, unsupported("unsupported prime meridian")
+		}
+	}
+	if unit := geogcs.child("UNIT"); unit != nil {
+		if numbers := unit.numbers(); len(numbers) != 0 && math.Abs(numbers[0]-degree) > 1e-12 {
+			return nil, unsupported("unsupported angular unit")
+		}
+	}
+	if root == geogcs {
+		return crs, nil
+	}
+
+	toMeter := 1.0
+	if unit := root.child("UNIT"); unit != nil {
+		if numbers := unit.numbers(); len(numbers) != 0 {
+			toMeter = numbers[0]
+		}
+	}
+	params := make(map[string]float64)
+	for _, value := range root.values {
+		if param, ok := value.(*wktNode); ok && strings.EqualFold(param.keyword, "PARAMETER") {
+			if numbers := param.numbers(); len(numbers) != 0 {
+				params[strings.ToLower(param.name())] = numbers[0]
+			}
+		}
+	}
+	param := func(name string, defaultValue float64) float64 {
+		if value, ok := params[name]; ok {
+			return value
+		}
+		return defaultValue
+	}
+	lat0 := param("latitude_of_origin", 0)
+	lon0 := param("central_meridian", 0)
+	k0 := param("scale_factor", 1)
+	x0 := param("false_easting", 0) * toMeter
+	y0 := param("false_northing", 0) * toMeter
+
+	projection := root.child("PROJECTION")
+	if projection == nil {
+		return nil, unsupported("missing PROJECTION")
+	}
+	e := crs.Ellipsoid
+	switch name := projection.name(); strings.ToLower(name) {
+	case "transverse_mercator":
+		crs.Projection = NewTransverseMercator(e, lon0, lat0, k0, x0, y0)
+	case "lambert_conformal_conic_1sp":
+		crs.Projection = newLambertConformalConic(e, lat0, lat0, lat0, lon0, k0, x0, y0)
+	case "lambert_conformal_conic_2sp", "lambert_conformal_conic":
+		lat1 := param("standard_parallel_1", lat0)
+		lat2 := param("standard_parallel_2", lat1)
+		crs.Projection = newLambertConformalConic(e, lat1, lat2, lat0, lon0, k0, x0, y0)
+	case "mercator_1sp":
+		crs.Projection = NewMercator(e, lon0, k0, x0, y0)
+	case "mercator_2sp":
+		k0 = newEllipsoid(e.EquatorialRadius(), e.Flattening()).parallelRadius(param("standard_parallel_1", 0))
+		crs.Projection = NewMercator(e, lon0, k0, x0, y0)
+	case "popular_visualisation_pseudo_mercator":
+		crs.Projection = NewMercator(geodesic.NewEllipsoid(e.EquatorialRadius(), 0), lon0, 1, x0, y0)
+	default:
+		return nil, unsupported("unsupported projection " + name)
+	}
+	crs.Projection = crs.Projection.inUnits(toMeter)
+	return crs, nil
+}
@@ -0,0 +1,133 @@
+package proj_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom/geodesic"
+	"github.com/twpayne/go-geom/proj"
+)
+
+func TestParseWKT(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		s               string
+		expectedSRID    int
+		expectedToWGS84 []float64
+		lon, lat        float64
+	}{
+		{
+			name:         "geogcs",
+			s:            `GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563,AUTHORITY["EPSG","7030"]],AUTHORITY["EPSG","6326"]],PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],AUTHORITY["EPSG","4326"]]`,
+			expectedSRID: 4326,
+		},
+		{
+			name:         "utm",
+			s:            `PROJCS["WGS 84 / UTM zone 32N",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563,AUTHORITY["EPSG","7030"]],AUTHORITY["EPSG","6326"]],PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],AUTHORITY["EPSG","4326"]],PROJECTION["Transverse_Mercator"],PARAMETER["latitude_of_origin",0],PARAMETER["central_meridian",9],PARAMETER["scale_factor",0.9996],PARAMETER["false_easting",500000],PARAMETER["false_northing",0],UNIT["metre",1,AUTHORITY["EPSG","9001"]],AXIS["Easting",EAST],AXIS["Northing",NORTH],AUTHORITY["EPSG","32632"]]`,
+			expectedSRID: 32632,
+			lon:          10,
+			lat:          50,
+		},
+		{
+			name:            "british_national_grid",
+			s:               `PROJCS["OSGB 1936 / British National Grid",GEOGCS["OSGB 1936",DATUM["OSGB_1936",SPHEROID["Airy 1830",6377563.396,299.3249646,AUTHORITY["EPSG","7001"]],TOWGS84[446.448,-125.157,542.06,0.15,0.247,0.842,-20.489],AUTHORITY["EPSG","6277"]],PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],AUTHORITY["EPSG","4277"]],PROJECTION["Transverse_Mercator"],PARAMETER["latitude_of_origin",49],PARAMETER["central_meridian",-2],PARAMETER["scale_factor",0.9996012717],PARAMETER["false_easting",400000],PARAMETER["false_northing",-100000],UNIT["metre",1,AUTHORITY["EPSG","9001"]],AXIS["Easting",EAST],AXIS["Northing",NORTH],AUTHORITY["EPSG","27700"]]`,
+			expectedSRID:    27700,
+			expectedToWGS84: []float64{446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489},
+			lon:             -0.1275,
+			lat:             51.507222,
+		},
+		{
+			name:            "british_national_grid_esri",
+			s:               `PROJCS["British_National_Grid",GEOGCS["GCS_OSGB_1936",DATUM["D_OSGB_1936",SPHEROID["Airy_1830",6377563.396,299.3249646]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]],PROJECTION["Transverse_Mercator"],PARAMETER["False_Easting",400000.0],PARAMETER["False_Northing",-100000.0],PARAMETER["Central_Meridian",-2.0],PARAMETER["Scale_Factor",0.9996012717],PARAMETER["Latitude_Of_Origin",49.0],UNIT["Meter",1.0]]`,
+			expectedSRID:    27700,
+			expectedToWGS84: []float64{446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489},
+			lon:             -0.1275,
+			lat:             51.507222,
+		},
+		{
+			name:         "lambert_93_esri",
+			s:            `PROJCS["RGF_1993_Lambert_93",GEOGCS["GCS_RGF_1993",DATUM["D_RGF_1993",SPHEROID["GRS_1980",6378137.0,298.257222101]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]],PROJECTION["Lambert_Conformal_Conic"],PARAMETER["False_Easting",700000.0],PARAMETER["False_Northing",6600000.0],PARAMETER["Central_Meridian",3.0],PARAMETER["Standard_Parallel_1",49.0],PARAMETER["Standard_Parallel_2",44.0],PARAMETER["Latitude_Of_Origin",46.5],UNIT["Meter",1.0]]`,
+			expectedSRID: 2154,
+			lon:          2.35,
+			lat:          48.85,
+		},
+		{
+			name:         "web_mercator",
+			s:            `PROJCS["WGS 84 / Pseudo-Mercator",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563,AUTHORITY["EPSG","7030"]],AUTHORITY["EPSG","6326"]],PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],AUTHORITY["EPSG","4326"]],PROJECTION["Mercator_1SP"],PARAMETER["central_meridian",0],PARAMETER["scale_factor",1],PARAMETER["false_easting",0],PARAMETER["false_northing",0],UNIT["metre",1,AUTHORITY["EPSG","9001"]],AXIS["X",EAST],AXIS["Y",NORTH],EXTENSION["PROJ4","+proj=merc +a=6378137 +b=6378137 +lat_ts=0.0 +lon_0=0.0 +x_0=0.0 +y_0=0 +k=1.0 +units=m +nadgrids=@null +wktext +no_defs"],AUTHORITY["EPSG","3857"]]`,
+			expectedSRID: 3857,
+			lon:          -0.1275,
+			lat:          51.507222,
+		},
+		{
+			name:         "pseudo_mercator",
+			s:            `PROJCS["WGS 84 / Pseudo-Mercator",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]],PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]],PROJECTION["Popular_Visualisation_Pseudo_Mercator"],PARAMETER["central_meridian",0],PARAMETER["false_easting",0],PARAMETER["false_northing",0],UNIT["metre",1]]`,
+			expectedSRID: 3857,
+			lon:          -0.1275,
+			lat:          51.507222,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			crs, err := proj.ParseWKT(tc.s)
+			assert.NoError(t, err)
+			expected, err := proj.Lookup(tc.expectedSRID)
+			assert.NoError(t, err)
+			assertInDelta(t, expected.Ellipsoid.EquatorialRadius(), crs.Ellipsoid.EquatorialRadius(), 1e-9)
+			assertInDelta(t, expected.Ellipsoid.Flattening(), crs.Ellipsoid.Flattening(), 1e-12)
+			assert.Equal(t, tc.expectedToWGS84, crs.ToWGS84)
+			assert.Equal(t, expected.Projection == nil, crs.Projection == nil)
+			if crs.Projection != nil {
+				expectedX, expectedY := expected.Projection.Forward(tc.lon, tc.lat)
+				x, y := crs.Projection.Forward(tc.lon, tc.lat)
+				assertInDelta(t, expectedX, x, 1e-6)
+				assertInDelta(t, expectedY, y, 1e-6)
+			}
+		})
+	}
+}
+
+func TestParseWKTUnits(t *testing.T) {
+	// The NAD27 Texas South Central example from EPSG Guidance Note 7-2, with
+	// the false easting in US survey feet.
+	crs, err := proj.ParseWKT(`PROJCS["NAD27 / Texas South Central",GEOGCS["NAD27",DATUM["North_American_Datum_1927",SPHEROID["Clarke 1866",6378206.4,294.9786982138982]],PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]],PROJECTION["Lambert_Conformal_Conic_2SP"],PARAMETER["standard_parallel_1",28.38333333333333],PARAMETER["standard_parallel_2",30.28333333333333],PARAMETER["latitude_of_origin",27.83333333333333],PARAMETER["central_meridian",-99],PARAMETER["false_easting",2000000],PARAMETER["false_northing",0],UNIT["US survey foot",0.3048006096012192]]`)
+	assert.NoError(t, err)
+	assert.NotEqual(t, geodesic.WGS84, crs.Ellipsoid)
+	x, y := crs.Projection.Forward(-96, 28.5)
+	assertInDelta(t, 2963503.91, x, 0.01)
+	assertInDelta(t, 254759.80, y, 0.01)
+}
+
+func TestParseWKTDatumNotShared(t *testing.T) {
+	const s = `GEOGCS["GCS_OSGB_1936",DATUM["D_OSGB_1936",SPHEROID["Airy_1830",6377563.396,299.3249646]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]]`
+	crs, err := proj.ParseWKT(s)
+	assert.NoError(t, err)
+	crs.ToWGS84[0] = 0
+	crs, err = proj.ParseWKT(s)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489}, crs.ToWGS84)
+}
+
+func TestParseWKTErrors(t *testing.T) {
+	for _, s := range []string{
+		``,
+		`GEOGCS`,
+		`GEOGCS["WGS 84"`,
+		`GEOGCS["WGS 84"] trailing`,
+		`GEOCCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]]]`,
+		`GEOGCS["WGS 84"]`,
+		`GEOGCS["WGS 84",DATUM["WGS_1984"]]`,
+		`GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563],TOWGS84[1,2]]]`,
+		`GEOGCS["NTF (Paris)",DATUM["NTF",SPHEROID["Clarke 1880 (IGN)",6378249.2,293.466021293627]],PRIMEM["Paris",2.33722917]]`,
+		`GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]],UNIT["grad",0.015707963267949]]`,
+		`PROJCS["WGS 84 / Antarctic Polar Stereographic",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]]],PROJECTION["Polar_Stereographic"]]`,
+		`PROJCS["Missing",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]]]]`,
+		`PROJCS["Missing"]`,
+	} {
+		t.Run(s, func(t *testing.T) {
+			_, err := proj.ParseWKT(s)
+			var errUnsupportedDefinition proj.ErrUnsupportedDefinition
+			assert.True(t, errors.As(err, &errUnsupportedDefinition))
+		})
+	}
+}
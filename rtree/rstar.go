@@ -0,0 +1,505 @@
+package rtree
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/twpayne/go-geom"
+)
+
+// An RStarTree is a dynamic R-tree, to which values can be inserted and from
+// which they can be deleted at any time, using the R*-tree algorithms of N.
+// Beckmann, H.-P. Kriegel, R. Schneider, and B. Seeger, The R*-tree: an
+// efficient and robust access method for points and rectangles, SIGMOD '90,
+// https://doi.org/10.1145/93597.98741.
+//
+// An RStarTree is safe for concurrent use. Queries run concurrently with
+// each other, but not with modifications, which wait for queries in progress
+// to finish. The functions passed to queries, and the bodies of loops over
+// their iterators, must not modify the tree.
+type RStarTree[V comparable] struct {
+	mu         sync.RWMutex
+	root       *node[V]
+	height     int
+	size       int
+	maxEntries int
+	minEntries int
+}
+
+// NewRStarTree returns a new empty RStarTree with DefaultNodeCapacity.
+func NewRStarTree[V comparable]() *RStarTree[V] {
+	return NewRStarTreeWithNodeCapacity[V](DefaultNodeCapacity)
+}
+
+// NewRStarTreeWithNodeCapacity returns a new empty RStarTree with at most
+// nodeCapacity children in each node. nodeCapacity must be at least 4.
+func NewRStarTreeWithNodeCapacity[V comparable](nodeCapacity int) *RStarTree[V] {
+	if nodeCapacity < 4 {
+		panic("rtree: node capacity must be at least 4")
+	}
+	return &RStarTree[V]{
+		root:       &node[V]{},
+		maxEntries: nodeCapacity,
+		minEntries: max(2, nodeCapacity*2/5),
+	}
+}
+
+// Len returns the number of values in t.
+func (t *RStarTree[V]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.size
+}
+
+// Bounds returns the bounds of all the values in t, with layout geom.XY.
+func (t *RStarTree[V]) Bounds() *geom.Bounds {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.size == 0 {
+		return geom.NewBounds(geom.XY)
+	}
+	return t.root.box.bounds()
+}
+
+// Insert inserts value with bounds into t. A value may be inserted more than
+// once, with the same or different bounds. If bounds is empty then Insert
+// does nothing.
+func (t *RStarTree[V]) Insert(bounds *geom.Bounds, value V) {
+	b, ok := newBox(bounds)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.insert(entry[V]{box: b, bounds: bounds, value: value})
+}
+
+// Delete deletes value with bounds from t. bounds must have the same x and y
+// extent as when value was inserted. If value was inserted more than once
+// with the same extent then only one is deleted. It returns whether value
+// was found.
+func (t *RStarTree[V]) Delete(bounds *geom.Bounds, value V) bool {
+	b, ok := newBox(bounds)
+	if !ok {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path, index := t.find(b, value)
+	if path == nil {
+		return false
+	}
+	t.delete(path, index)
+	return true
+}
+
+// Update moves value from oldBounds to newBounds, as if by Delete(oldBounds,
+// value) followed by Insert(newBounds, value), but faster if value has not
+// moved far. It returns whether value was found. If it was not found then t
+// is unchanged.
+func (t *RStarTree[V]) Update(oldBounds, newBounds *geom.Bounds, value V) bool {
+	oldBox, ok := newBox(oldBounds)
+	if !ok {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path, index := t.find(oldBox, value)
+	if path == nil {
+		return false
+	}
+	b, ok := newBox(newBounds)
+	leaf := path[len(path)-1]
+	switch {
+	case !ok:
+		t.delete(path, index)
+	case leaf.box.contains(b):
+		// The value is still within its leaf, so update it in place and
+		// shrink the boxes of the leaf and its ancestors.
+		leaf.entries[index] = entry[V]{box: b, bounds: newBounds, value: value}
+		for i := len(path) - 1; i >= 0; i-- {
+			path[i].updateBox()
+		}
+	default:
+		t.delete(path, index)
+		t.insert(entry[V]{box: b, bounds: newBounds, value: value})
+	}
+	return true
+}
+
+// Search returns the values whose bounds overlap bounds, including those
+// that only touch it.
+func (t *RStarTree[V]) Search(bounds *geom.Bounds) []V {
+	var values []V
+	t.Visit(bounds, func(_ *geom.Bounds, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Visit calls visit with the bounds and value of each value whose bounds
+// overlap bounds, until visit returns false. It returns false if visit
+// returned false.
+func (t *RStarTree[V]) Visit(bounds *geom.Bounds, visit func(bounds *geom.Bounds, value V) bool) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.root.visit(bounds, visit)
+}
+
+// All returns an iterator over the bounds and values of the values whose
+// bounds overlap bounds.
+func (t *RStarTree[V]) All(bounds *geom.Bounds) iter.Seq2[*geom.Bounds, V] {
+	return func(yield func(*geom.Bounds, V) bool) {
+		t.Visit(bounds, yield)
+	}
+}
+
+// Nearest returns the k values whose bounds are nearest to coord, nearest
+// first. The distance to a value is zero if coord is inside its bounds.
+// Values at the same distance are returned in an unspecified order.
+func (t *RStarTree[V]) Nearest(coord geom.Coord, k int) []V {
+	return nearest(t.NearestAll(coord), k)
+}
+
+// NearestAll returns an iterator over the distances from coord to the bounds
+// of the values of t and the values, nearest first.
+func (t *RStarTree[V]) NearestAll(coord geom.Coord) iter.Seq2[float64, V] {
+	return func(yield func(float64, V) bool) {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+		for distance, value := range t.root.nearestAll(coord) {
+			if !yield(distance, value) {
+				return
+			}
+		}
+	}
+}
+
+// insert inserts e into t.
+func (t *RStarTree[V]) insert(e entry[V]) {
+	t.insertAtLevel(e.box, e, nil, 0, make(map[int]bool))
+	t.size++
+}
+
+// insertAtLevel inserts e, if level is zero, or child into a node at level,
+// where the leaves are at level zero. b is the box of e or child.
+// reinserted records the levels at which entries have already been
+// reinserted during the current insertion.
+func (t *RStarTree[V]) insertAtLevel(b box, e entry[V], child *node[V], level int, reinserted map[int]bool) {
+	path := []*node[V]{t.root}
+	n := t.root
+	for nodeLevel := t.height; nodeLevel > level; nodeLevel-- {
+		n = n.children[n.chooseSubtree(b, nodeLevel == 1)]
+		path = append(path, n)
+	}
+	if level == 0 {
+		n.entries = append(n.entries, e)
+	} else {
+		n.children = append(n.children, child)
+	}
+
+	// Update the boxes of the nodes on the path, and treat any overflow by
+	// either reinserting some members of the node or splitting it.
+	for i := len(path) - 1; i >= 0; i-- {
+		n := path[i]
+		if n.numMembers() <= t.maxEntries {
+			if i == len(path)-1 && n.numMembers() == 1 {
+				n.box = b
+			} else {
+				n.box = n.box.union(b)
+			}
+			continue
+		}
+		nodeLevel := t.height - i
+		if i > 0 && !reinserted[nodeLevel] {
+			reinserted[nodeLevel] = true
+			removed := n.removeFarthest(t.maxEntries*3/10 + 1)
+			for j := i; j >= 0; j-- {
+				path[j].updateBox()
+			}
+			for _, m := range removed {
+				t.insertAtLevel(m.box, m.entry, m.child, nodeLevel, reinserted)
+			}
+			return
+		}
+		sibling := n.split(t.minEntries)
+		if i == 0 {
+			t.root = &node[V]{children: []*node[V]{n, sibling}}
+			t.root.updateBox()
+			t.height++
+			return
+		}
+		path[i-1].children = append(path[i-1].children, sibling)
+		path[i-1].updateBox()
+	}
+}
+
+// find returns the path from the root to the leaf that contains the entry
+// with box b and value, and the index of the entry in the leaf. It returns
+// nil if there is no such entry.
+func (t *RStarTree[V]) find(b box, value V) ([]*node[V], int) {
+	var find func(path []*node[V]) ([]*node[V], int)
+	find = func(path []*node[V]) ([]*node[V], int) {
+		n := path[len(path)-1]
+		for i, e := range n.entries {
+			if e.box == b && e.value == value {
+				return path, i
+			}
+		}
+		for _, child := range n.children {
+			if child.box.contains(b) {
+				if path, i := find(append(path, child)); path != nil {
+					return path, i
+				}
+			}
+		}
+		return nil, -1
+	}
+	return find([]*node[V]{t.root})
+}
+
+// delete deletes the entry at index in the leaf at the end of path, and
+// reinserts the members of any nodes that become underfull.
+func (t *RStarTree[V]) delete(path []*node[V], index int) {
+	leaf := path[len(path)-1]
+	leaf.entries = slices.Delete(leaf.entries, index, index+1)
+	t.size--
+
+	var orphans []*node[V]
+	var orphanLevels []int
+	for i := len(path) - 1; i > 0; i-- {
+		n, parent := path[i], path[i-1]
+		if n.numMembers() < t.minEntries {
+			parent.children = slices.DeleteFunc(parent.children, func(child *node[V]) bool {
+				return child == n
+			})
+			orphans = append(orphans, n)
+			orphanLevels = append(orphanLevels, t.height-i)
+		} else {
+			n.updateBox()
+		}
+	}
+	t.root.updateBox()
+
+	for i, orphan := range orphans {
+		for _, e := range orphan.entries {
+			t.insertAtLevel(e.box, e, nil, 0, make(map[int]bool))
+		}
+		for _, child := range orphan.children {
+			t.insertAtLevel(child.box, entry[V]{}, child, orphanLevels[i], make(map[int]bool))
+		}
+	}
+	for t.height > 0 && len(t.root.children) == 1 {
+		t.root = t.root.children[0]
+		t.height--
+	}
+}
+
+// A member is an entry or a child of a node.
+type member[V any] struct {
+	box   box
+	entry entry[V]
+	child *node[V]
+}
+
+func (n *node[V]) numMembers() int {
+	return len(n.entries) + len(n.children)
+}
+
+func (n *node[V]) memberBoxes() []box {
+	boxes := make([]box, 0, n.numMembers())
+	for _, e := range n.entries {
+		boxes = append(boxes, e.box)
+	}
+	for _, child := range n.children {
+		boxes = append(boxes, child.box)
+	}
+	return boxes
+}
+
+// permute reorders the members of n so that the ith member is the
+// order[i]th member before.
+func (n *node[V]) permute(order []int) {
+	if n.children == nil {
+		entries := make([]entry[V], 0, len(n.entries))
+		for _, i := range order {
+			entries = append(entries, n.entries[i])
+		}
+		n.entries = entries
+	} else {
+		children := make([]*node[V], 0, len(n.children))
+		for _, i := range order {
+			children = append(children, n.children[i])
+		}
+		n.children = children
+	}
+}
+
+// updateBox sets the box of n to the union of the boxes of its members.
+func (n *node[V]) updateBox() {
+	boxes := n.memberBoxes()
+	if len(boxes) == 0 {
+		n.box = box{}
+		return
+	}
+	n.box = boxes[0]
+	for _, b := range boxes[1:] {
+		n.box = n.box.union(b)
+	}
+}
+
+// chooseSubtree returns the index of the child of n in which to insert an
+// entry or node with box b. If the children of n are leaves then this is
+// the child whose overlap with the other children increases least,
+// otherwise it is the child whose area increases least.
+func (n *node[V]) chooseSubtree(b box, childrenAreLeaves bool) int {
+	best := 0
+	bestOverlap, bestEnlargement, bestArea := math.Inf(1), math.Inf(1), math.Inf(1)
+	for i, child := range n.children {
+		enlarged := child.box.union(b)
+		overlap := 0.0
+		if childrenAreLeaves && enlarged != child.box {
+			for j, other := range n.children {
+				if j != i {
+					overlap += enlarged.overlapArea(other.box) - child.box.overlapArea(other.box)
+				}
+			}
+		}
+		area := child.box.area()
+		enlargement := enlarged.area() - area
+		if cmp.Or(
+			cmp.Compare(overlap, bestOverlap),
+			cmp.Compare(enlargement, bestEnlargement),
+			cmp.Compare(area, bestArea),
+		) < 0 {
+			best, bestOverlap, bestEnlargement, bestArea = i, overlap, enlargement, area
+		}
+	}
+	return best
+}
+
+// removeFarthest removes and returns the count members of n whose centers
+// are farthest from the center of n, nearest first. The box of n is not
+// updated.
+func (n *node[V]) removeFarthest(count int) []member[V] {
+	boxes := n.memberBoxes()
+	cx, cy := n.box.center()
+	distance2 := func(i int) float64 {
+		x, y := boxes[i].center()
+		return (x-cx)*(x-cx) + (y-cy)*(y-cy)
+	}
+	order := make([]int, len(boxes))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Compare(distance2(i), distance2(j))
+	})
+	n.permute(order)
+
+	keep := len(boxes) - count
+	removed := make([]member[V], 0, count)
+	for i := keep; i < len(boxes); i++ {
+		if n.children == nil {
+			removed = append(removed, member[V]{box: n.entries[i].box, entry: n.entries[i]})
+		} else {
+			removed = append(removed, member[V]{box: n.children[i].box, child: n.children[i]})
+		}
+	}
+	if n.children == nil {
+		n.entries = n.entries[:keep]
+	} else {
+		n.children = n.children[:keep]
+	}
+	return removed
+}
+
+// split moves some of the members of n to a new sibling, which it returns,
+// leaving at least minEntries members in each. The split is along the axis
+// with the smallest total perimeter of the possible distributions, at the
+// distribution with the least overlap and then the least area.
+func (n *node[V]) split(minEntries int) *node[V] {
+	boxes := n.memberBoxes()
+	numMembers := len(boxes)
+
+	// distributions returns, for each possible split point of order, the
+	// boxes of the members before and after it.
+	distributions := func(order []int) (before, after []box) {
+		before, after = make([]box, numMembers), make([]box, numMembers)
+		before[0] = boxes[order[0]]
+		for i := 1; i < numMembers; i++ {
+			before[i] = before[i-1].union(boxes[order[i]])
+		}
+		after[numMembers-1] = boxes[order[numMembers-1]]
+		for i := numMembers - 2; i >= 0; i-- {
+			after[i] = after[i+1].union(boxes[order[i]])
+		}
+		return before, after
+	}
+	sortedOrders := func(lower, upper func(box) float64) [][]int {
+		var orders [][]int
+		for _, key := range []func(box) float64{lower, upper} {
+			order := make([]int, numMembers)
+			for i := range order {
+				order[i] = i
+			}
+			slices.SortStableFunc(order, func(i, j int) int {
+				return cmp.Or(
+					cmp.Compare(key(boxes[i]), key(boxes[j])),
+					cmp.Compare(lower(boxes[i])+upper(boxes[i]), lower(boxes[j])+upper(boxes[j])),
+				)
+			})
+			orders = append(orders, order)
+		}
+		return orders
+	}
+
+	var bestOrders [][]int
+	bestMargin := math.Inf(1)
+	for _, axisOrders := range [][][]int{
+		sortedOrders(func(b box) float64 { return b.minX }, func(b box) float64 { return b.maxX }),
+		sortedOrders(func(b box) float64 { return b.minY }, func(b box) float64 { return b.maxY }),
+	} {
+		margin := 0.0
+		for _, order := range axisOrders {
+			before, after := distributions(order)
+			for k := minEntries; k <= numMembers-minEntries; k++ {
+				margin += before[k-1].margin() + after[k].margin()
+			}
+		}
+		if margin < bestMargin {
+			bestOrders, bestMargin = axisOrders, margin
+		}
+	}
+
+	var bestOrder []int
+	bestK := 0
+	bestOverlap, bestArea := math.Inf(1), math.Inf(1)
+	for _, order := range bestOrders {
+		before, after := distributions(order)
+		for k := minEntries; k <= numMembers-minEntries; k++ {
+			overlap := before[k-1].overlapArea(after[k])
+			area := before[k-1].area() + after[k].area()
+			if overlap < bestOverlap || overlap == bestOverlap && area < bestArea {
+				bestOrder, bestK, bestOverlap, bestArea = order, k, overlap, area
+			}
+		}
+	}
+
+	n.permute(bestOrder)
+	sibling := &node[V]{}
+	if n.children == nil {
+		sibling.entries = slices.Clone(n.entries[bestK:])
+		n.entries = n.entries[:bestK]
+	} else {
+		sibling.children = slices.Clone(n.children[bestK:])
+		n.children = n.children[:bestK]
+	}
+	n.updateBox()
+	sibling.updateBox()
+	return sibling
+}
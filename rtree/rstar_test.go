@@ -0,0 +1,196 @@
+package rtree_test
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/rtree"
+)
+
+func randomBounds(r *rand.Rand) *geom.Bounds {
+	x, y := 1000*r.Float64(), 1000*r.Float64()
+	return geom.NewBounds(geom.XY).Set(x, y, x+10*r.Float64(), y+10*r.Float64())
+}
+
+// checkRStarTree checks that the results of queries on tree match those on
+// items, a map from values to their bounds.
+func checkRStarTree(t *testing.T, r *rand.Rand, tree *rtree.RStarTree[int], items map[int]*geom.Bounds) {
+	t.Helper()
+	if tree.Len() != len(items) {
+		t.Fatalf("expected %d values, got %d", len(items), tree.Len())
+	}
+	expectedBounds := geom.NewBounds(geom.XY)
+	for _, bounds := range items {
+		expectedBounds.Extend(bounds.Polygon())
+	}
+	if got := tree.Bounds(); !reflect.DeepEqual(got, expectedBounds) {
+		t.Fatalf("expected bounds %v, got %v", expectedBounds, got)
+	}
+	for range 20 {
+		x, y := 1000*r.Float64(), 1000*r.Float64()
+		bounds := geom.NewBounds(geom.XY).Set(x, y, x+100*r.Float64(), y+100*r.Float64())
+		var expected []int
+		for value, itemBounds := range items {
+			if itemBounds.Overlaps(geom.XY, bounds) {
+				expected = append(expected, value)
+			}
+		}
+		slices.Sort(expected)
+		got := tree.Search(bounds)
+		slices.Sort(got)
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+
+		coord := geom.Coord{x, y}
+		distances := make([]float64, 0, len(items))
+		for _, itemBounds := range items {
+			distances = append(distances, boundsDistance(itemBounds, coord))
+		}
+		slices.Sort(distances)
+		k := min(5, len(items))
+		values := tree.Nearest(coord, k)
+		if len(values) != k {
+			t.Fatalf("expected %d values, got %d", k, len(values))
+		}
+		for i, value := range values {
+			if distance := boundsDistance(items[value], coord); distance != distances[i] {
+				t.Fatalf("%v: expected distance %v for value %d, got %v", coord, distances[i], i, distance)
+			}
+		}
+	}
+}
+
+func TestRStarTree(t *testing.T) {
+	for _, nodeCapacity := range []int{4, 5, 16} {
+		r := rand.New(rand.NewPCG(1, uint64(nodeCapacity)))
+		tree := rtree.NewRStarTreeWithNodeCapacity[int](nodeCapacity)
+		items := make(map[int]*geom.Bounds)
+		checkRStarTree(t, r, tree, items)
+
+		for value := range 2000 {
+			bounds := randomBounds(r)
+			tree.Insert(bounds, value)
+			items[value] = bounds
+		}
+		checkRStarTree(t, r, tree, items)
+
+		for value := range 2000 {
+			switch {
+			case value%3 == 0:
+				if !tree.Delete(items[value], value) {
+					t.Fatalf("%d: expected Delete to return true", value)
+				}
+				delete(items, value)
+			case value%3 == 1:
+				// Move the value a little, so that it usually stays in its
+				// leaf.
+				bounds := items[value].Clone()
+				dx, dy := r.Float64()-0.5, r.Float64()-0.5
+				bounds.Set(bounds.Min(0)+dx, bounds.Min(1)+dy, bounds.Max(0)+dx, bounds.Max(1)+dy)
+				if !tree.Update(items[value], bounds, value) {
+					t.Fatalf("%d: expected Update to return true", value)
+				}
+				items[value] = bounds
+			default:
+				bounds := randomBounds(r)
+				if !tree.Update(items[value], bounds, value) {
+					t.Fatalf("%d: expected Update to return true", value)
+				}
+				items[value] = bounds
+			}
+		}
+		checkRStarTree(t, r, tree, items)
+
+		for value := range items {
+			if !tree.Delete(items[value], value) {
+				t.Fatalf("%d: expected Delete to return true", value)
+			}
+			delete(items, value)
+		}
+		checkRStarTree(t, r, tree, items)
+	}
+}
+
+func TestRStarTreeNotFound(t *testing.T) {
+	tree := rtree.NewRStarTree[string]()
+	bounds := geom.NewBounds(geom.XY).Set(0, 0, 1, 1)
+	tree.Insert(bounds, "a")
+	tree.Insert(geom.NewBounds(geom.XY), "b")
+
+	for _, tc := range []struct {
+		name   string
+		bounds *geom.Bounds
+		value  string
+	}{
+		{name: "other_value", bounds: bounds, value: "b"},
+		{name: "other_bounds", bounds: geom.NewBounds(geom.XY).Set(0, 0, 2, 2), value: "a"},
+		{name: "empty_bounds", bounds: geom.NewBounds(geom.XY), value: "b"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tree.Delete(tc.bounds, tc.value) {
+				t.Error("expected Delete to return false")
+			}
+			if tree.Update(tc.bounds, bounds, tc.value) {
+				t.Error("expected Update to return false")
+			}
+			if tree.Len() != 1 {
+				t.Errorf("expected 1 value, got %d", tree.Len())
+			}
+		})
+	}
+}
+
+func TestRStarTreeDuplicates(t *testing.T) {
+	tree := rtree.NewRStarTree[string]()
+	bounds := geom.NewBounds(geom.XY).Set(0, 0, 1, 1)
+	for range 3 {
+		tree.Insert(bounds, "a")
+	}
+	if !tree.Update(bounds, geom.NewBounds(geom.XY), "a") {
+		t.Error("expected Update to return true")
+	}
+	if got := tree.Search(bounds); !reflect.DeepEqual(got, []string{"a", "a"}) {
+		t.Errorf("expected [a a], got %v", got)
+	}
+}
+
+func TestRStarTreeConcurrent(t *testing.T) {
+	tree := rtree.NewRStarTree[int]()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r := rand.New(rand.NewPCG(1, 2))
+		bounds := make([]*geom.Bounds, 0, 1000)
+		for value := range 1000 {
+			bounds = append(bounds, randomBounds(r))
+			tree.Insert(bounds[value], value)
+		}
+		for value := range 1000 {
+			newBounds := randomBounds(r)
+			tree.Update(bounds[value], newBounds, value)
+			bounds[value] = newBounds
+		}
+	}()
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewPCG(3, uint64(i)))
+			for range 200 {
+				tree.Search(randomBounds(r))
+				tree.Nearest(geom.Coord{1000 * r.Float64(), 1000 * r.Float64()}, 3)
+				tree.Bounds()
+			}
+		}()
+	}
+	wg.Wait()
+	if tree.Len() != 1000 {
+		t.Errorf("expected 1000 values, got %d", tree.Len())
+	}
+}
@@ -0,0 +1,346 @@
+// Package rtree implements R-trees, spatial indexes that find the values
+// whose bounds overlap a query or are nearest to a point.
+//
+// Only the x and y dimensions of bounds are indexed, so the bounds of values
+// and queries may have any layout.
+package rtree
+
+import (
+	"cmp"
+	"container/heap"
+	"iter"
+	"math"
+	"slices"
+
+	"github.com/twpayne/go-geom"
+)
+
+// DefaultNodeCapacity is the default maximum number of children of each
+// node.
+const DefaultNodeCapacity = 16
+
+// An Item is a value and its bounds.
+type Item[V any] struct {
+	Bounds *geom.Bounds
+	Value  V
+}
+
+// An RTree is a read-only R-tree, bulk loaded with the Sort-Tile-Recursive
+// (STR) algorithm. It is safe for concurrent use.
+type RTree[V any] struct {
+	root *node[V]
+	size int
+}
+
+// A node is a node of an R-tree. Leaf nodes have entries and other nodes
+// have children.
+type node[V any] struct {
+	box      box
+	children []*node[V]
+	entries  []entry[V]
+}
+
+// An entry is an indexed item.
+type entry[V any] struct {
+	box    box
+	bounds *geom.Bounds
+	value  V
+}
+
+// New returns a new RTree containing items, with DefaultNodeCapacity. Items
+// with empty bounds are never returned by queries.
+func New[V any](items []Item[V]) *RTree[V] {
+	return NewWithNodeCapacity(items, DefaultNodeCapacity)
+}
+
+// NewWithNodeCapacity returns a new RTree containing items, with at most
+// nodeCapacity children in each node. Larger nodes make the tree shallower
+// but each node slower to search. nodeCapacity must be at least 2.
+func NewWithNodeCapacity[V any](items []Item[V], nodeCapacity int) *RTree[V] {
+	if nodeCapacity < 2 {
+		panic("rtree: node capacity must be at least 2")
+	}
+	t := &RTree[V]{
+		size: len(items),
+	}
+	entries := make([]entry[V], 0, len(items))
+	for _, item := range items {
+		if b, ok := newBox(item.Bounds); ok {
+			entries = append(entries, entry[V]{box: b, bounds: item.Bounds, value: item.Value})
+		}
+	}
+	if len(entries) == 0 {
+		return t
+	}
+
+	strSort(entries, func(e entry[V]) box { return e.box }, nodeCapacity)
+	nodes := make([]*node[V], 0, (len(entries)+nodeCapacity-1)/nodeCapacity)
+	for start := 0; start < len(entries); start += nodeCapacity {
+		n := &node[V]{entries: entries[start:min(start+nodeCapacity, len(entries))]}
+		n.box = n.entries[0].box
+		for _, e := range n.entries[1:] {
+			n.box = n.box.union(e.box)
+		}
+		nodes = append(nodes, n)
+	}
+	for len(nodes) > 1 {
+		strSort(nodes, func(n *node[V]) box { return n.box }, nodeCapacity)
+		parents := make([]*node[V], 0, (len(nodes)+nodeCapacity-1)/nodeCapacity)
+		for start := 0; start < len(nodes); start += nodeCapacity {
+			parent := &node[V]{children: nodes[start:min(start+nodeCapacity, len(nodes))]}
+			parent.box = parent.children[0].box
+			for _, child := range parent.children[1:] {
+				parent.box = parent.box.union(child.box)
+			}
+			parents = append(parents, parent)
+		}
+		nodes = parents
+	}
+	t.root = nodes[0]
+	return t
+}
+
+// Len returns the number of items in t.
+func (t *RTree[V]) Len() int {
+	return t.size
+}
+
+// Bounds returns the bounds of all the items in t, with layout geom.XY.
+func (t *RTree[V]) Bounds() *geom.Bounds {
+	if t.root == nil {
+		return geom.NewBounds(geom.XY)
+	}
+	return t.root.box.bounds()
+}
+
+// Search returns the values of the items whose bounds overlap bounds,
+// including those that only touch it.
+func (t *RTree[V]) Search(bounds *geom.Bounds) []V {
+	var values []V
+	t.Visit(bounds, func(_ *geom.Bounds, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Visit calls visit with the bounds and value of each item whose bounds
+// overlap bounds, until visit returns false. It returns false if visit
+// returned false.
+func (t *RTree[V]) Visit(bounds *geom.Bounds, visit func(bounds *geom.Bounds, value V) bool) bool {
+	return t.root.visit(bounds, visit)
+}
+
+// All returns an iterator over the bounds and values of the items whose
+// bounds overlap bounds.
+func (t *RTree[V]) All(bounds *geom.Bounds) iter.Seq2[*geom.Bounds, V] {
+	return func(yield func(*geom.Bounds, V) bool) {
+		t.Visit(bounds, yield)
+	}
+}
+
+// Nearest returns the values of the k items whose bounds are nearest to
+// coord, nearest first. The distance to an item is zero if coord is inside
+// its bounds. Items at the same distance are returned in an unspecified
+// order.
+func (t *RTree[V]) Nearest(coord geom.Coord, k int) []V {
+	return nearest(t.NearestAll(coord), k)
+}
+
+// NearestAll returns an iterator over the distances from coord to the bounds
+// of the items of t and their values, nearest first.
+func (t *RTree[V]) NearestAll(coord geom.Coord) iter.Seq2[float64, V] {
+	return t.root.nearestAll(coord)
+}
+
+// nearest returns the first k values of seq.
+func nearest[V any](seq iter.Seq2[float64, V], k int) []V {
+	if k <= 0 {
+		return nil
+	}
+	var values []V
+	for _, value := range seq {
+		values = append(values, value)
+		if len(values) == k {
+			break
+		}
+	}
+	return values
+}
+
+// visit calls visit with the bounds and value of each entry in the subtree
+// rooted at n whose bounds overlap bounds, until visit returns false. n may
+// be nil.
+func (n *node[V]) visit(bounds *geom.Bounds, visit func(bounds *geom.Bounds, value V) bool) bool {
+	b, ok := newBox(bounds)
+	if !ok || n == nil {
+		return true
+	}
+	stack := []*node[V]{n}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !n.box.overlaps(b) {
+			continue
+		}
+		for _, e := range n.entries {
+			if e.box.overlaps(b) && !visit(e.bounds, e.value) {
+				return false
+			}
+		}
+		for i := len(n.children) - 1; i >= 0; i-- {
+			stack = append(stack, n.children[i])
+		}
+	}
+	return true
+}
+
+// nearestAll returns an iterator over the distances from coord to the
+// bounds of the entries in the subtree rooted at n, and their values,
+// nearest first. n may be nil.
+func (n *node[V]) nearestAll(coord geom.Coord) iter.Seq2[float64, V] {
+	return func(yield func(float64, V) bool) {
+		if n == nil {
+			return
+		}
+		x, y := coord[0], coord[1]
+		queue := nearestQueue[V]{{distance2: n.box.distance2(x, y), node: n}}
+		for queue.Len() > 0 {
+			item := heap.Pop(&queue).(nearestItem[V])
+			if item.node == nil {
+				if !yield(math.Sqrt(item.distance2), item.value) {
+					return
+				}
+				continue
+			}
+			for _, e := range item.node.entries {
+				heap.Push(&queue, nearestItem[V]{distance2: e.box.distance2(x, y), value: e.value})
+			}
+			for _, child := range item.node.children {
+				heap.Push(&queue, nearestItem[V]{distance2: child.box.distance2(x, y), node: child})
+			}
+		}
+	}
+}
+
+// strSort sorts s into the order of the Sort-Tile-Recursive algorithm, so
+// that consecutive runs of nodeCapacity elements are close together. s is
+// sorted by the x ordinate of the centers of the boxes, divided into
+// vertical slices of whole runs, and each slice is sorted by the y ordinate.
+func strSort[T any](s []T, boxOf func(T) box, nodeCapacity int) {
+	centerX := func(a T) float64 {
+		x, _ := boxOf(a).center()
+		return x
+	}
+	centerY := func(a T) float64 {
+		_, y := boxOf(a).center()
+		return y
+	}
+	slices.SortStableFunc(s, func(a, b T) int {
+		return cmp.Compare(centerX(a), centerX(b))
+	})
+	numNodes := (len(s) + nodeCapacity - 1) / nodeCapacity
+	sliceSize := int(math.Ceil(math.Sqrt(float64(numNodes)))) * nodeCapacity
+	for start := 0; start < len(s); start += sliceSize {
+		slices.SortStableFunc(s[start:min(start+sliceSize, len(s))], func(a, b T) int {
+			return cmp.Compare(centerY(a), centerY(b))
+		})
+	}
+}
+
+// A box is a two-dimensional bounding box.
+type box struct {
+	minX, minY, maxX, maxY float64
+}
+
+// newBox returns the x and y extent of b and whether it is non-empty.
+func newBox(b *geom.Bounds) (box, bool) {
+	if b == nil || b.Layout().Stride() < 2 || b.IsEmpty() {
+		return box{}, false
+	}
+	return box{minX: b.Min(0), minY: b.Min(1), maxX: b.Max(0), maxY: b.Max(1)}, true
+}
+
+func (b box) area() float64 {
+	return (b.maxX - b.minX) * (b.maxY - b.minY)
+}
+
+func (b box) bounds() *geom.Bounds {
+	return geom.NewBounds(geom.XY).Set(b.minX, b.minY, b.maxX, b.maxY)
+}
+
+func (b box) center() (float64, float64) {
+	return b.minX/2 + b.maxX/2, b.minY/2 + b.maxY/2
+}
+
+func (b box) contains(b2 box) bool {
+	return b.minX <= b2.minX && b2.maxX <= b.maxX && b.minY <= b2.minY && b2.maxY <= b.maxY
+}
+
+// distance2 returns the square of the distance from (x, y) to b.
+func (b box) distance2(x, y float64) float64 {
+	dx := max(b.minX-x, 0, x-b.maxX)
+	dy := max(b.minY-y, 0, y-b.maxY)
+	return dx*dx + dy*dy
+}
+
+// margin returns half the perimeter of b.
+func (b box) margin() float64 {
+	return b.maxX - b.minX + b.maxY - b.minY
+}
+
+// overlapArea returns the area of the intersection of b and b2.
+func (b box) overlapArea(b2 box) float64 {
+	dx := min(b.maxX, b2.maxX) - max(b.minX, b2.minX)
+	dy := min(b.maxY, b2.maxY) - max(b.minY, b2.minY)
+	if dx <= 0 || dy <= 0 {
+		return 0
+	}
+	return dx * dy
+}
+
+func (b box) overlaps(b2 box) bool {
+	return b.minX <= b2.maxX && b2.minX <= b.maxX && b.minY <= b2.maxY && b2.minY <= b.maxY
+}
+
+func (b box) union(b2 box) box {
+	return box{
+		minX: min(b.minX, b2.minX),
+		minY: min(b.minY, b2.minY),
+		maxX: max(b.maxX, b2.maxX),
+		maxY: max(b.maxY, b2.maxY),
+	}
+}
+
+// A nearestItem is a node or the value of an entry in a nearestQueue.
+type nearestItem[V any] struct {
+	distance2 float64
+	node      *node[V]
+	value     V
+}
+
+// A nearestQueue is a priority queue of nodes and values with the nearest
+// first.
+type nearestQueue[V any] []nearestItem[V]
+
+func (q nearestQueue[V]) Len() int { return len(q) }
+
+func (q nearestQueue[V]) Less(i, j int) bool {
+	if q[i].distance2 != q[j].distance2 {
+		return q[i].distance2 < q[j].distance2
+	}
+	// Return values before nodes at the same distance, so that they are
+	// returned as soon as possible.
+	return q[i].node == nil && q[j].node != nil
+}
+
+func (q nearestQueue[V]) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *nearestQueue[V]) Push(x any) { *q = append(*q, x.(nearestItem[V])) }
+
+func (q *nearestQueue[V]) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,47 @@
+package rtree_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/rtree"
+)
+
+func ExampleRTree_Search() {
+	polygons := []*geom.Polygon{
+		geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}),
+		geom.NewPolygonFlat(geom.XY, []float64{10, 0, 20, 0, 20, 10, 10, 0}, []int{8}),
+		geom.NewPolygonFlat(geom.XY, []float64{0, 10, 10, 10, 10, 20, 0, 10}, []int{8}),
+	}
+	items := make([]rtree.Item[int], 0, len(polygons))
+	for i, polygon := range polygons {
+		items = append(items, rtree.Item[int]{Bounds: polygon.Bounds(), Value: i})
+	}
+	tree := rtree.New(items)
+
+	fmt.Println(tree.Search(geom.NewBounds(geom.XY).Set(12, 5, 12, 5)))
+	fmt.Println(tree.Nearest(geom.Coord{5, 25}, 2))
+	// Output:
+	// [1]
+	// [2 0]
+}
+
+func ExampleRStarTree() {
+	tree := rtree.NewRStarTree[string]()
+	position := geom.NewPointFlat(geom.XY, []float64{1, 1})
+	tree.Insert(position.Bounds(), "bus")
+	tree.Insert(geom.NewPointFlat(geom.XY, []float64{5, 5}).Bounds(), "tram")
+
+	// Move the bus.
+	newPosition := geom.NewPointFlat(geom.XY, []float64{4, 6})
+	tree.Update(position.Bounds(), newPosition.Bounds(), "bus")
+
+	fmt.Println(tree.Search(geom.NewBounds(geom.XY).Set(3, 3, 6, 6)))
+	fmt.Println(tree.Nearest(geom.Coord{0, 10}, 1))
+	bounds := tree.Bounds()
+	fmt.Println(bounds.Min(0), bounds.Min(1), bounds.Max(0), bounds.Max(1))
+	// Output:
+	// [tram bus]
+	// [bus]
+	// 4 5 5 6
+}
@@ -0,0 +1,164 @@
+package rtree_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/rtree"
+)
+
+func randomItems(r *rand.Rand, n int) []rtree.Item[int] {
+	items := make([]rtree.Item[int], 0, n)
+	for i := range n {
+		x, y := 1000*r.Float64(), 1000*r.Float64()
+		w, h := 10*r.Float64(), 10*r.Float64()
+		items = append(items, rtree.Item[int]{
+			Bounds: geom.NewBounds(geom.XY).Set(x, y, x+w, y+h),
+			Value:  i,
+		})
+	}
+	return items
+}
+
+func boundsDistance(b *geom.Bounds, coord geom.Coord) float64 {
+	dx := max(b.Min(0)-coord[0], 0, coord[0]-b.Max(0))
+	dy := max(b.Min(1)-coord[1], 0, coord[1]-b.Max(1))
+	return math.Hypot(dx, dy)
+}
+
+func TestRTreeSearch(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	items := randomItems(r, 2000)
+	for _, nodeCapacity := range []int{2, 4, 16, 64} {
+		tree := rtree.NewWithNodeCapacity(items, nodeCapacity)
+		if tree.Len() != len(items) {
+			t.Errorf("expected %d items, got %d", len(items), tree.Len())
+		}
+		for range 100 {
+			x, y := 1000*r.Float64(), 1000*r.Float64()
+			bounds := geom.NewBounds(geom.XY).Set(x, y, x+50*r.Float64(), y+50*r.Float64())
+			var expected []int
+			for _, item := range items {
+				if item.Bounds.Overlaps(geom.XY, bounds) {
+					expected = append(expected, item.Value)
+				}
+			}
+			got := tree.Search(bounds)
+			slices.Sort(got)
+			if !reflect.DeepEqual(got, expected) {
+				t.Fatalf("nodeCapacity=%d: expected %v, got %v", nodeCapacity, expected, got)
+			}
+		}
+	}
+}
+
+func TestRTreeNearest(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	items := randomItems(r, 1000)
+	tree := rtree.New(items)
+	for range 100 {
+		coord := geom.Coord{1100*r.Float64() - 50, 1100*r.Float64() - 50}
+		distances := make([]float64, 0, len(items))
+		for _, item := range items {
+			distances = append(distances, boundsDistance(item.Bounds, coord))
+		}
+		slices.Sort(distances)
+		got := tree.Nearest(coord, 10)
+		if len(got) != 10 {
+			t.Fatalf("expected 10 values, got %d", len(got))
+		}
+		for i, value := range got {
+			if distance := boundsDistance(items[value].Bounds, coord); distance != distances[i] {
+				t.Fatalf("%v: expected distance %v for value %d, got %v", coord, distances[i], i, distance)
+			}
+		}
+	}
+}
+
+func TestRTreeVisit(t *testing.T) {
+	items := []rtree.Item[string]{
+		{Bounds: geom.NewBounds(geom.XY).Set(0, 0, 1, 1), Value: "a"},
+		{Bounds: geom.NewBounds(geom.XY).Set(2, 2, 3, 3), Value: "b"},
+		{Bounds: geom.NewBounds(geom.XY).Set(1, 1, 2, 2), Value: "c"},
+	}
+	tree := rtree.NewWithNodeCapacity(items, 2)
+	bounds := geom.NewBounds(geom.XY).Set(0, 0, 3, 3)
+
+	var visited []string
+	if tree.Visit(bounds, func(_ *geom.Bounds, value string) bool {
+		visited = append(visited, value)
+		return len(visited) < 2
+	}) {
+		t.Error("expected Visit to return false")
+	}
+	if len(visited) != 2 {
+		t.Errorf("expected 2 values, got %v", visited)
+	}
+
+	var all []string
+	for b, value := range tree.All(bounds) {
+		if !b.Overlaps(geom.XY, bounds) {
+			t.Errorf("%s: %v does not overlap %v", value, b, bounds)
+		}
+		all = append(all, value)
+	}
+	slices.Sort(all)
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(all, expected) {
+		t.Errorf("expected %v, got %v", expected, all)
+	}
+}
+
+func TestRTreeEmpty(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		items []rtree.Item[int]
+	}{
+		{
+			name: "no_items",
+		},
+		{
+			name: "empty_bounds",
+			items: []rtree.Item[int]{
+				{Bounds: geom.NewBounds(geom.XY), Value: 1},
+				{Value: 2},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := rtree.New(tc.items)
+			if tree.Len() != len(tc.items) {
+				t.Errorf("expected %d items, got %d", len(tc.items), tree.Len())
+			}
+			if !tree.Bounds().IsEmpty() {
+				t.Errorf("expected empty bounds, got %v", tree.Bounds())
+			}
+			if got := tree.Search(geom.NewBounds(geom.XY).Set(-1e9, -1e9, 1e9, 1e9)); got != nil {
+				t.Errorf("expected nil, got %v", got)
+			}
+			if got := tree.Nearest(geom.Coord{0, 0}, 1); got != nil {
+				t.Errorf("expected nil, got %v", got)
+			}
+		})
+	}
+}
+
+func TestRTreeBounds(t *testing.T) {
+	tree := rtree.New([]rtree.Item[int]{
+		{Bounds: geom.NewBounds(geom.XYZ).Set(0, 1, 2, 3, 4, 5), Value: 1},
+		{Bounds: geom.NewBounds(geom.XY).Set(-1, 2, 1, 6), Value: 2},
+	})
+	expected := geom.NewBounds(geom.XY).Set(-1, 1, 3, 6)
+	if got := tree.Bounds(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := tree.Search(geom.NewBounds(geom.XY).Set(2.5, 5.5, 2.5, 5.5)); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+	if got := tree.Search(geom.NewBounds(geom.XY).Set(3, 4, 3, 4)); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("expected [1], got %v", got)
+	}
+}
@@ -0,0 +1,238 @@
+package transform
+
+import (
+	"errors"
+	"math"
+
+	"github.com/twpayne/go-geom"
+)
+
+// ErrSingular is returned when inverting an affine transformation that has no
+// inverse.
+var ErrSingular = errors.New("transform: singular affine transformation")
+
+// An Affine is an affine transformation of 3D coordinates. The first three
+// columns of each row are the linear part and the fourth column is the
+// translation, so
+//
+//	x' = a[0][0]*x + a[0][1]*y + a[0][2]*z + a[0][3]
+//	y' = a[1][0]*x + a[1][1]*y + a[1][2]*z + a[1][3]
+//	z' = a[2][0]*x + a[2][1]*y + a[2][2]*z + a[2][3]
+//
+// Coordinates without a Z ordinate are transformed with z equal to zero and
+// the M ordinate is never changed. The zero value is not the identity, use
+// Identity instead.
+type Affine [3][4]float64
+
+// Identity is the identity transformation.
+var Identity = Affine{
+	{1, 0, 0, 0},
+	{0, 1, 0, 0},
+	{0, 0, 1, 0},
+}
+
+// NewAffine2D returns the 2D affine transformation x' = a*x + b*y + xoff, y'
+// = d*x + e*y + yoff, which leaves z unchanged. The parameters are in the
+// same order as those of PostGIS's ST_Affine.
+func NewAffine2D(a, b, d, e, xoff, yoff float64) Affine {
+	return Affine{
+		{a, b, 0, xoff},
+		{d, e, 0, yoff},
+		{0, 0, 1, 0},
+	}
+}
+
+// Translate returns the translation by (dx, dy, dz).
+func Translate(dx, dy, dz float64) Affine {
+	return Affine{
+		{1, 0, 0, dx},
+		{0, 1, 0, dy},
+		{0, 0, 1, dz},
+	}
+}
+
+// Scale returns the scaling by sx, sy, and sz about the origin.
+func Scale(sx, sy, sz float64) Affine {
+	return Affine{
+		{sx, 0, 0, 0},
+		{0, sy, 0, 0},
+		{0, 0, sz, 0},
+	}
+}
+
+// ScaleAround returns the scaling of x and y by sx and sy about origin,
+// which leaves z unchanged. If origin is nil then the scaling is about (0,
+// 0).
+func ScaleAround(sx, sy float64, origin geom.Coord) Affine {
+	x0, y0 := originXY(origin)
+	return NewAffine2D(sx, 0, 0, sy, x0-sx*x0, y0-sy*y0)
+}
+
+// Rotate returns the counterclockwise rotation by angle radians about
+// origin in the xy plane, which leaves z unchanged. If origin is nil then
+// the rotation is about (0, 0).
+func Rotate(angle float64, origin geom.Coord) Affine {
+	x0, y0 := originXY(origin)
+	sin, cos := math.Sincos(angle)
+	return NewAffine2D(cos, -sin, sin, cos, x0-cos*x0+sin*y0, y0-sin*x0-cos*y0)
+}
+
+// RotateX returns the rotation by angle radians about the x axis, which
+// turns the y axis towards the z axis.
+func RotateX(angle float64) Affine {
+	sin, cos := math.Sincos(angle)
+	return Affine{
+		{1, 0, 0, 0},
+		{0, cos, -sin, 0},
+		{0, sin, cos, 0},
+	}
+}
+
+// RotateY returns the rotation by angle radians about the y axis, which
+// turns the z axis towards the x axis.
+func RotateY(angle float64) Affine {
+	sin, cos := math.Sincos(angle)
+	return Affine{
+		{cos, 0, sin, 0},
+		{0, 1, 0, 0},
+		{-sin, 0, cos, 0},
+	}
+}
+
+// Shear returns the shear x' = x + shx*y, y' = shy*x + y, which leaves z
+// unchanged.
+func Shear(shx, shy float64) Affine {
+	return NewAffine2D(1, shx, shy, 1, 0, 0)
+}
+
+// Reflect returns the reflection in the line through p0 and p1 in the xy
+// plane, which leaves z unchanged. If p0 and p1 have the same x and y
+// ordinates then it returns the reflection in the point p0.
+func Reflect(p0, p1 geom.Coord) Affine {
+	x0, y0 := p0[0], p0[1]
+	dx, dy := p1[0]-x0, p1[1]-y0
+	length2 := dx*dx + dy*dy
+	if length2 == 0 {
+		return NewAffine2D(-1, 0, 0, -1, 2*x0, 2*y0)
+	}
+	a := (dx*dx - dy*dy) / length2
+	b := 2 * dx * dy / length2
+	return NewAffine2D(a, b, b, -a, x0-a*x0-b*y0, y0-b*x0+a*y0)
+}
+
+// Then returns the transformation that applies a and then b.
+func (a Affine) Then(b Affine) Affine {
+	var c Affine
+	for i := range 3 {
+		for j := range 4 {
+			c[i][j] = b[i][0]*a[0][j] + b[i][1]*a[1][j] + b[i][2]*a[2][j]
+		}
+		c[i][3] += b[i][3]
+	}
+	return c
+}
+
+// Determinant returns the determinant of the linear part of a.
+func (a Affine) Determinant() float64 {
+	return a[0][0]*(a[1][1]*a[2][2]-a[1][2]*a[2][1]) -
+		a[0][1]*(a[1][0]*a[2][2]-a[1][2]*a[2][0]) +
+		a[0][2]*(a[1][0]*a[2][1]-a[1][1]*a[2][0])
+}
+
+// Invert returns the inverse of a. It returns ErrSingular if a has no
+// inverse.
+func (a Affine) Invert() (Affine, error) {
+	det := a.Determinant()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Affine{}, ErrSingular
+	}
+	var inv Affine
+	// The inverse of the linear part is its adjugate divided by its
+	// determinant.
+	for i := range 3 {
+		for j := range 3 {
+			i1, i2 := (j+1)%3, (j+2)%3
+			j1, j2 := (i+1)%3, (i+2)%3
+			inv[i][j] = (a[i1][j1]*a[i2][j2] - a[i1][j2]*a[i2][j1]) / det
+		}
+	}
+	for i := range 3 {
+		inv[i][3] = -(inv[i][0]*a[0][3] + inv[i][1]*a[1][3] + inv[i][2]*a[2][3])
+	}
+	return inv, nil
+}
+
+// Transform returns the point (x, y, z) transformed by a.
+func (a Affine) Transform(x, y, z float64) (float64, float64, float64) {
+	return a[0][0]*x + a[0][1]*y + a[0][2]*z + a[0][3],
+		a[1][0]*x + a[1][1]*y + a[1][2]*z + a[1][3],
+		a[2][0]*x + a[2][1]*y + a[2][2]*z + a[2][3]
+}
+
+// TransformCoord transforms c, which has layout layout, in place.
+func (a Affine) TransformCoord(c geom.Coord, layout geom.Layout) {
+	if zIndex := layout.ZIndex(); zIndex != -1 {
+		c[0], c[1], c[zIndex] = a.Transform(c[0], c[1], c[zIndex])
+	} else {
+		c[0], c[1], _ = a.Transform(c[0], c[1], 0)
+	}
+}
+
+// Apply returns a copy of g transformed by a. The copy has the layout and
+// SRID of g, and its bounds are those of the transformed coordinates. It
+// returns a geom.ErrUnsupportedType error if g is not one of the geom types.
+func (a Affine) Apply(g geom.T) (geom.T, error) {
+	switch g := g.(type) {
+	case *geom.Point:
+		return a.applyInPlace(g.Clone()), nil
+	case *geom.MultiPoint:
+		return a.applyInPlace(g.Clone()), nil
+	case *geom.LineString:
+		return a.applyInPlace(g.Clone()), nil
+	case *geom.LinearRing:
+		return a.applyInPlace(g.Clone()), nil
+	case *geom.MultiLineString:
+		return a.applyInPlace(g.Clone()), nil
+	case *geom.Polygon:
+		return a.applyInPlace(g.Clone()), nil
+	case *geom.MultiPolygon:
+		return a.applyInPlace(g.Clone()), nil
+	case *geom.GeometryCollection:
+		result := geom.NewGeometryCollection().SetSRID(g.SRID())
+		// If the geometries of g have different layouts then g has no
+		// explicit layout, otherwise its layout is the same as theirs.
+		if layout := g.Layout(); g.CheckLayout(layout) == nil {
+			if err := result.SetLayout(layout); err != nil {
+				return nil, err
+			}
+		}
+		for _, child := range g.Geoms() {
+			transformed, err := a.Apply(child)
+			if err != nil {
+				return nil, err
+			}
+			if err := result.Push(transformed); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	default:
+		return nil, geom.ErrUnsupportedType{Value: g}
+	}
+}
+
+// applyInPlace transforms the coordinates of g in place.
+func (a Affine) applyInPlace(g geom.T) geom.T {
+	layout := g.Layout()
+	return geom.TransformInPlace(g, func(c geom.Coord) {
+		a.TransformCoord(c, layout)
+	})
+}
+
+// originXY returns the x and y ordinates of origin, or zero if origin is nil.
+func originXY(origin geom.Coord) (float64, float64) {
+	if origin == nil {
+		return 0, 0
+	}
+	return origin[0], origin[1]
+}
@@ -0,0 +1,29 @@
+package transform_test
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/transform"
+)
+
+func ExampleAffine_Apply() {
+	square := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}, []int{10})
+
+	// Rotate the square by 90 degrees about its center, then move it up by 10.
+	a := transform.Rotate(math.Pi/2, geom.Coord{1, 1}).Then(transform.Translate(0, 10, 0))
+	rotated, err := a.Apply(square)
+	if err != nil {
+		panic(err)
+	}
+	coords := rotated.FlatCoords()
+	for i, c := range coords {
+		coords[i] = math.Round(c) + 0 // Avoid printing -0.
+	}
+	fmt.Println(coords)
+	fmt.Println(rotated.Bounds().Min(1), rotated.Bounds().Max(1))
+	// Output:
+	// [2 10 2 12 0 12 0 10 2 10]
+	// 10 12
+}
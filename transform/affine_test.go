@@ -0,0 +1,223 @@
+package transform
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-geom"
+)
+
+func assertFlatCoordsInDelta(t *testing.T, expected, actual []float64, delta float64) {
+	t.Helper()
+	assert.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		if math.Abs(expected[i]-actual[i]) > delta {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+	}
+}
+
+func TestAffineTransform(t *testing.T) {
+	for i, tc := range []struct {
+		affine   Affine
+		c        geom.Coord
+		layout   geom.Layout
+		expected geom.Coord
+	}{
+		{
+			affine:   Identity,
+			c:        geom.Coord{1, 2},
+			layout:   geom.XY,
+			expected: geom.Coord{1, 2},
+		},
+		{
+			affine:   Translate(1, 2, 3),
+			c:        geom.Coord{1, 2},
+			layout:   geom.XY,
+			expected: geom.Coord{2, 4},
+		},
+		{
+			affine:   Translate(1, 2, 3),
+			c:        geom.Coord{1, 2, 3},
+			layout:   geom.XYZ,
+			expected: geom.Coord{2, 4, 6},
+		},
+		{
+			affine:   Translate(1, 2, 3),
+			c:        geom.Coord{1, 2, 3},
+			layout:   geom.XYM,
+			expected: geom.Coord{2, 4, 3},
+		},
+		{
+			affine:   Translate(1, 2, 3),
+			c:        geom.Coord{1, 2, 3, 4},
+			layout:   geom.XYZM,
+			expected: geom.Coord{2, 4, 6, 4},
+		},
+		{
+			affine:   Scale(2, 3, 4),
+			c:        geom.Coord{1, 1, 1},
+			layout:   geom.XYZ,
+			expected: geom.Coord{2, 3, 4},
+		},
+		{
+			affine:   ScaleAround(2, 3, geom.Coord{1, 1}),
+			c:        geom.Coord{2, 2, 5},
+			layout:   geom.XYZ,
+			expected: geom.Coord{3, 4, 5},
+		},
+		{
+			affine:   Rotate(math.Pi/2, nil),
+			c:        geom.Coord{1, 0},
+			layout:   geom.XY,
+			expected: geom.Coord{0, 1},
+		},
+		{
+			affine:   Rotate(math.Pi/2, geom.Coord{1, 1}),
+			c:        geom.Coord{2, 1},
+			layout:   geom.XY,
+			expected: geom.Coord{1, 2},
+		},
+		{
+			affine:   RotateX(math.Pi / 2),
+			c:        geom.Coord{1, 1, 0},
+			layout:   geom.XYZ,
+			expected: geom.Coord{1, 0, 1},
+		},
+		{
+			affine:   RotateY(math.Pi / 2),
+			c:        geom.Coord{0, 1, 1},
+			layout:   geom.XYZ,
+			expected: geom.Coord{1, 1, 0},
+		},
+		{
+			affine:   Shear(1, 0),
+			c:        geom.Coord{1, 2},
+			layout:   geom.XY,
+			expected: geom.Coord{3, 2},
+		},
+		{
+			affine:   Reflect(geom.Coord{0, 0}, geom.Coord{1, 0}),
+			c:        geom.Coord{1, 2},
+			layout:   geom.XY,
+			expected: geom.Coord{1, -2},
+		},
+		{
+			affine:   Reflect(geom.Coord{0, 0}, geom.Coord{1, 1}),
+			c:        geom.Coord{1, 0},
+			layout:   geom.XY,
+			expected: geom.Coord{0, 1},
+		},
+		{
+			affine:   Reflect(geom.Coord{1, 0}, geom.Coord{1, 5}),
+			c:        geom.Coord{3, 2},
+			layout:   geom.XY,
+			expected: geom.Coord{-1, 2},
+		},
+		{
+			affine:   Reflect(geom.Coord{1, 1}, geom.Coord{1, 1}),
+			c:        geom.Coord{2, 3},
+			layout:   geom.XY,
+			expected: geom.Coord{0, -1},
+		},
+		{
+			affine:   NewAffine2D(1, 2, 3, 4, 5, 6),
+			c:        geom.Coord{1, 1, 1},
+			layout:   geom.XYZ,
+			expected: geom.Coord{8, 13, 1},
+		},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			c := append(geom.Coord(nil), tc.c...)
+			tc.affine.TransformCoord(c, tc.layout)
+			assertFlatCoordsInDelta(t, tc.expected, c, 1e-12)
+		})
+	}
+}
+
+func TestAffineThen(t *testing.T) {
+	a := Translate(1, 0, 0).Then(Rotate(math.Pi/2, nil)).Then(Scale(2, 2, 2))
+	x, y, z := a.Transform(1, 0, 1)
+	assertFlatCoordsInDelta(t, []float64{0, 4, 2}, []float64{x, y, z}, 1e-12)
+}
+
+func TestAffineInvert(t *testing.T) {
+	for i, a := range []Affine{
+		Identity,
+		Translate(1, 2, 3),
+		Scale(2, 3, 4),
+		Rotate(1, geom.Coord{5, 6}),
+		RotateX(1).Then(RotateY(2)).Then(Translate(1, 2, 3)),
+		Shear(1, 2),
+		Reflect(geom.Coord{1, 2}, geom.Coord{3, 5}),
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			inv, err := a.Invert()
+			assert.NoError(t, err)
+			for _, identity := range []Affine{a.Then(inv), inv.Then(a)} {
+				for row := range 3 {
+					assertFlatCoordsInDelta(t, Identity[row][:], identity[row][:], 1e-12)
+				}
+			}
+		})
+	}
+
+	_, err := Scale(1, 0, 1).Invert()
+	assert.Equal(t, ErrSingular, err)
+	_, err = Affine{}.Invert()
+	assert.Equal(t, ErrSingular, err)
+}
+
+func TestAffineApply(t *testing.T) {
+	a := Translate(10, 20, 30)
+
+	ls := geom.NewLineStringFlat(geom.XYZM, []float64{0, 0, 0, 1, 1, 1, 1, 2}).SetSRID(4326)
+	g, err := a.Apply(ls)
+	assert.NoError(t, err)
+	got := g.(*geom.LineString)
+	assert.Equal(t, []float64{10, 20, 30, 1, 11, 21, 31, 2}, got.FlatCoords())
+	assert.Equal(t, 4326, got.SRID())
+	assert.Equal(t, geom.NewBounds(geom.XYZM).Set(10, 20, 30, 1, 11, 21, 31, 2), got.Bounds())
+	assert.Equal(t, []float64{0, 0, 0, 1, 1, 1, 1, 2}, ls.FlatCoords())
+
+	polygon := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 1, 0, 1, 1, 0, 0}, []int{8})
+	g, err = a.Apply(polygon)
+	assert.NoError(t, err)
+	gotPolygon := g.(*geom.Polygon)
+	assert.Equal(t, []float64{10, 20, 11, 20, 11, 21, 10, 20}, gotPolygon.FlatCoords())
+	assert.Equal(t, []int{8}, gotPolygon.Ends())
+
+	gc := geom.NewGeometryCollection().MustPush(
+		geom.NewPointFlat(geom.XY, []float64{1, 2}),
+		geom.NewGeometryCollection().MustPush(geom.NewPointFlat(geom.XYM, []float64{3, 4, 5})),
+	).SetSRID(3857)
+	g, err = a.Apply(gc)
+	assert.NoError(t, err)
+	gotGC := g.(*geom.GeometryCollection)
+	assert.Equal(t, 3857, gotGC.SRID())
+	assert.Equal(t, []float64{11, 22}, gotGC.Geom(0).FlatCoords())
+	assert.Equal(t, []float64{13, 24, 5}, gotGC.Geom(1).(*geom.GeometryCollection).Geom(0).FlatCoords())
+	assert.Equal(t, []float64{1, 2}, gc.Geom(0).FlatCoords())
+
+	emptyGC := geom.NewGeometryCollection().MustSetLayout(geom.XYZ).SetSRID(4326)
+	g, err = a.Apply(emptyGC)
+	assert.NoError(t, err)
+	gotEmptyGC := g.(*geom.GeometryCollection)
+	assert.Equal(t, geom.XYZ, gotEmptyGC.Layout())
+	assert.Equal(t, 4326, gotEmptyGC.SRID())
+	assert.Equal(t, 0, gotEmptyGC.NumGeoms())
+
+	xymGC := geom.NewGeometryCollection().MustSetLayout(geom.XYM).MustPush(geom.NewPointFlat(geom.XYM, []float64{1, 2, 3}))
+	g, err = a.Apply(xymGC)
+	assert.NoError(t, err)
+	gotXYMGC := g.(*geom.GeometryCollection)
+	assert.Equal(t, geom.XYM, gotXYMGC.Layout())
+	assert.NoError(t, gotXYMGC.Push(geom.NewPointFlat(geom.XYM, []float64{0, 0, 0})))
+	assert.Error(t, gotXYMGC.Push(geom.NewPointFlat(geom.XY, []float64{0, 0})))
+
+	_, err = a.Apply(nil)
+	assert.Equal(t, error(geom.ErrUnsupportedType{}), err)
+}
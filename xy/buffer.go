@@ -0,0 +1,364 @@
+package xy
+
+import (
+	"math"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal/planargraph"
+)
+
+// A CapStyle is the style of the ends of buffered lines.
+type CapStyle int
+
+const (
+	// CapRound ends lines with a semicircle.
+	CapRound CapStyle = iota
+	// CapFlat ends lines with a straight edge through the endpoint.
+	CapFlat
+	// CapSquare ends lines with a straight edge the buffer distance beyond
+	// the endpoint.
+	CapSquare
+)
+
+// A JoinStyle is the style of the corners where buffered or offset segments
+// meet.
+type JoinStyle int
+
+const (
+	// JoinRound joins segments with a circular arc.
+	JoinRound JoinStyle = iota
+	// JoinMitre joins segments by extending them until they meet, unless
+	// this is further than the mitre limit, in which case JoinBevel is used.
+	JoinMitre
+	// JoinBevel joins segments with a straight edge between their ends.
+	JoinBevel
+)
+
+const (
+	defaultQuadrantSegments = 8
+	defaultMitreLimit       = 5
+)
+
+type bufferParams struct {
+	capStyle         CapStyle
+	joinStyle        JoinStyle
+	quadrantSegments int
+	mitreLimit       float64
+}
+
+// A BufferOption is an option to Buffer or OffsetCurve.
+type BufferOption func(*bufferParams)
+
+// BufferOptionCapStyle sets the cap style. The default is CapRound.
+func BufferOptionCapStyle(capStyle CapStyle) BufferOption {
+	return func(p *bufferParams) {
+		p.capStyle = capStyle
+	}
+}
+
+// BufferOptionJoinStyle sets the join style. The default is JoinRound.
+func BufferOptionJoinStyle(joinStyle JoinStyle) BufferOption {
+	return func(p *bufferParams) {
+		p.joinStyle = joinStyle
+	}
+}
+
+// BufferOptionQuadrantSegments sets the number of segments used to
+// approximate a quarter circle. The default is 8 and the minimum is 1.
+func BufferOptionQuadrantSegments(quadrantSegments int) BufferOption {
+	return func(p *bufferParams) {
+		p.quadrantSegments = max(quadrantSegments, 1)
+	}
+}
+
+// BufferOptionMitreLimit sets the mitre limit, the maximum distance of a
+// mitre join from its vertex as a multiple of the buffer distance. The
+// default is 5.
+func BufferOptionMitreLimit(mitreLimit float64) BufferOption {
+	return func(p *bufferParams) {
+		p.mitreLimit = mitreLimit
+	}
+}
+
+// Buffer returns the area within distance of g. If distance is negative then
+// the polygons of g are eroded by -distance and other geometries are
+// ignored. Closed lines are joined at their endpoints rather than capped.
+// Flat caps on a single point give an empty result.
+//
+// The result is a *geom.Polygon, a *geom.MultiPolygon if there is more than
+// one Polygon, or an empty *geom.Polygon. It is computed from the x and y
+// ordinates only, so it has layout geom.XY, and it has the SRID of g.
+func Buffer(g geom.T, distance float64, opts ...BufferOption) (geom.T, error) {
+	if err := checkSupported(g); err != nil {
+		return nil, err
+	}
+	b := &bufferBuilder{
+		params: bufferParams{
+			capStyle:         CapRound,
+			joinStyle:        JoinRound,
+			quadrantSegments: defaultQuadrantSegments,
+			mitreLimit:       defaultMitreLimit,
+		},
+		distance: distance,
+	}
+	for _, opt := range opts {
+		opt(&b.params)
+	}
+	return unionPolygonal(b.buffer(g), g.SRID()), nil
+}
+
+// A bufferBuilder computes buffers as the union of simple pieces: a
+// rectangle for each segment, and a piece for each join and cap.
+type bufferBuilder struct {
+	params   bufferParams
+	distance float64
+}
+
+// buffer returns the non-overlapping polygons of the buffer of g.
+func (b *bufferBuilder) buffer(g geom.T) []unionPolygon {
+	if b.distance <= 0 {
+		var polygons []unionPolygon
+		switch g := g.(type) {
+		case *geom.Polygon:
+			polygons = b.erodePolygon(g)
+		case *geom.MultiPolygon:
+			for i := range g.NumPolygons() {
+				polygons = append(polygons, b.erodePolygon(g.Polygon(i))...)
+			}
+		case *geom.GeometryCollection:
+			for _, child := range g.Geoms() {
+				polygons = append(polygons, b.buffer(child)...)
+			}
+		}
+		return cascadedUnion(polygons)
+	}
+	return cascadedUnion(b.pieces(g))
+}
+
+// pieces returns the pieces of the buffer of g, which overlap.
+func (b *bufferBuilder) pieces(g geom.T) []unionPolygon {
+	var pieces []unionPolygon
+	stride := g.Stride()
+	switch g := g.(type) {
+	case *geom.Point:
+		if !g.Empty() {
+			pieces = b.pointPieces(pieces, g.X(), g.Y())
+		}
+	case *geom.MultiPoint:
+		flatCoords := finiteCoords(g.FlatCoords(), stride)
+		for i := 0; i < len(flatCoords); i += stride {
+			pieces = b.pointPieces(pieces, flatCoords[i], flatCoords[i+1])
+		}
+	case *geom.LineString:
+		pieces = b.linePieces(pieces, xyCoords(g.FlatCoords(), stride))
+	case *geom.LinearRing:
+		pieces = b.linePieces(pieces, xyCoords(g.FlatCoords(), stride))
+	case *geom.MultiLineString:
+		for i := range g.NumLineStrings() {
+			pieces = b.linePieces(pieces, xyCoords(g.LineString(i).FlatCoords(), stride))
+		}
+	case *geom.Polygon:
+		if g.NumLinearRings() == 0 {
+			break
+		}
+		rings := polygonRings(g)
+		pieces = append(pieces, unionPolygon{rings: rings, box: ringBox(rings[0])})
+		for _, ring := range rings {
+			pieces = b.linePieces(pieces, ring)
+		}
+	case *geom.MultiPolygon:
+		for i := range g.NumPolygons() {
+			pieces = append(pieces, b.pieces(g.Polygon(i))...)
+		}
+	case *geom.GeometryCollection:
+		for _, child := range g.Geoms() {
+			pieces = append(pieces, b.pieces(child)...)
+		}
+	}
+	return pieces
+}
+
+// erodePolygon returns the polygons of p eroded by -b.distance, by
+// subtracting the buffer of its rings.
+func (b *bufferBuilder) erodePolygon(p *geom.Polygon) []unionPolygon {
+	if p.NumLinearRings() == 0 {
+		return nil
+	}
+	rings := polygonRings(p)
+	builder := planargraph.NewBuilder(2)
+	for _, ring := range rings {
+		builder.AddRing(0, ring)
+	}
+	if b.distance < 0 {
+		eroder := &bufferBuilder{params: b.params, distance: -b.distance}
+		var pieces []unionPolygon
+		for _, ring := range rings {
+			pieces = eroder.linePieces(pieces, ring)
+		}
+		for _, piece := range cascadedUnion(pieces) {
+			for _, ring := range piece.rings {
+				builder.AddRing(1, ring)
+			}
+		}
+	}
+	return unionGraph(builder, func(inside []bool) bool {
+		return inside[0] && !inside[1]
+	})
+}
+
+// pointPieces appends the pieces of the buffer of the point (x, y) to
+// pieces.
+func (b *bufferBuilder) pointPieces(pieces []unionPolygon, x, y float64) []unionPolygon {
+	d := b.distance
+	switch b.params.capStyle {
+	case CapRound:
+		return appendPiece(pieces, b.circle(x, y))
+	case CapSquare:
+		return appendPiece(pieces, []float64{x - d, y - d, x + d, y - d, x + d, y + d, x - d, y + d, x - d, y - d})
+	default:
+		return pieces
+	}
+}
+
+// linePieces appends the pieces of the buffer of the line with xy
+// coordinates line, without repeated points, to pieces.
+func (b *bufferBuilder) linePieces(pieces []unionPolygon, line []float64) []unionPolygon {
+	n := len(line) / 2
+	switch {
+	case n == 0:
+		return pieces
+	case n == 1:
+		return b.pointPieces(pieces, line[0], line[1])
+	}
+	d := b.distance
+	for i := 1; i < n; i++ {
+		x0, y0, x1, y1 := line[2*i-2], line[2*i-1], line[2*i], line[2*i+1]
+		nx, ny := leftNormal(x0, y0, x1, y1, d)
+		pieces = appendPiece(pieces, []float64{
+			x0 + nx, y0 + ny, x0 - nx, y0 - ny, x1 - nx, y1 - ny, x1 + nx, y1 + ny, x0 + nx, y0 + ny,
+		})
+	}
+	for i := 1; i < n-1; i++ {
+		pieces = b.joinPieces(pieces, line[2*i-2:2*i+4])
+	}
+	if closed := n >= 4 && line[0] == line[2*n-2] && line[1] == line[2*n-1]; closed {
+		return b.joinPieces(pieces, []float64{line[2*n-4], line[2*n-3], line[0], line[1], line[2], line[3]})
+	}
+	pieces = b.capPieces(pieces, line[2], line[3], line[0], line[1])
+	return b.capPieces(pieces, line[2*n-4], line[2*n-3], line[2*n-2], line[2*n-1])
+}
+
+// joinPieces appends the pieces of the join at the middle of the three
+// points in coords to pieces.
+func (b *bufferBuilder) joinPieces(pieces []unionPolygon, coords []float64) []unionPolygon {
+	x0, y0, x1, y1, x2, y2 := coords[0], coords[1], coords[2], coords[3], coords[4], coords[5]
+	cross := (x1-x0)*(y2-y1) - (y1-y0)*(x2-x1)
+	if cross == 0 {
+		if b.params.joinStyle == JoinRound && (x1-x0)*(x2-x1)+(y1-y0)*(y2-y1) < 0 {
+			return appendPiece(pieces, b.circle(x1, y1))
+		}
+		return pieces
+	}
+	// The join is on the outside of the turn, which is on the right of a
+	// left turn and the left of a right turn.
+	d := b.distance
+	if cross > 0 {
+		d = -d
+	}
+	nx0, ny0 := leftNormal(x0, y0, x1, y1, d)
+	nx1, ny1 := leftNormal(x1, y1, x2, y2, d)
+	switch b.params.joinStyle {
+	case JoinRound:
+		return appendPiece(pieces, b.fan(x1, y1, nx0, ny0, nx1, ny1))
+	case JoinMitre:
+		if mx, my, ok := mitre(nx0, ny0, nx1, ny1, b.distance, b.params.mitreLimit); ok {
+			return appendPiece(pieces, []float64{x1, y1, x1 + nx0, y1 + ny0, x1 + mx, y1 + my, x1 + nx1, y1 + ny1, x1, y1})
+		}
+	}
+	return appendPiece(pieces, []float64{x1, y1, x1 + nx0, y1 + ny0, x1 + nx1, y1 + ny1, x1, y1})
+}
+
+// fan returns a ring from (x, y) along the shorter circular arc from the
+// offset (nx0, ny0) to the offset (nx1, ny1), which have length
+// b.distance, and back to (x, y).
+func (b *bufferBuilder) fan(x, y, nx0, ny0, nx1, ny1 float64) []float64 {
+	angle0 := math.Atan2(ny0, nx0)
+	sweep := math.Atan2(ny1, nx1) - angle0
+	switch {
+	case sweep > math.Pi:
+		sweep -= 2 * math.Pi
+	case sweep < -math.Pi:
+		sweep += 2 * math.Pi
+	}
+	n := int(math.Ceil(math.Abs(sweep)*float64(2*b.params.quadrantSegments)/math.Pi - 1e-9))
+	ring := make([]float64, 0, 2*n+6)
+	ring = append(ring, x, y, x+nx0, y+ny0)
+	for i := 1; i < n; i++ {
+		angle := angle0 + sweep*float64(i)/float64(n)
+		ring = append(ring, x+b.distance*math.Cos(angle), y+b.distance*math.Sin(angle))
+	}
+	return append(ring, x+nx1, y+ny1, x, y)
+}
+
+// capPieces appends the pieces of the cap at the end (x1, y1) of the
+// segment from (x0, y0) to pieces.
+func (b *bufferBuilder) capPieces(pieces []unionPolygon, x0, y0, x1, y1 float64) []unionPolygon {
+	switch b.params.capStyle {
+	case CapRound:
+		return appendPiece(pieces, b.circle(x1, y1))
+	case CapSquare:
+		nx, ny := leftNormal(x0, y0, x1, y1, b.distance)
+		// The direction of the segment is the left normal rotated clockwise.
+		dx, dy := ny, -nx
+		return appendPiece(pieces, []float64{
+			x1 + nx, y1 + ny, x1 - nx, y1 - ny, x1 + dx - nx, y1 + dy - ny, x1 + dx + nx, y1 + dy + ny, x1 + nx, y1 + ny,
+		})
+	default:
+		return pieces
+	}
+}
+
+// circle returns a ring approximating the circle of radius b.distance
+// centered on (x, y).
+func (b *bufferBuilder) circle(x, y float64) []float64 {
+	n := 4 * b.params.quadrantSegments
+	ring := make([]float64, 0, 2*n+2)
+	for i := range n {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		ring = append(ring, x+b.distance*math.Cos(angle), y+b.distance*math.Sin(angle))
+	}
+	return append(ring, ring[0], ring[1])
+}
+
+// leftNormal returns the vector of length d perpendicular to, and to the
+// left of, the segment from (x0, y0) to (x1, y1).
+func leftNormal(x0, y0, x1, y1, d float64) (float64, float64) {
+	length := math.Hypot(x1-x0, y1-y0)
+	return -d * (y1 - y0) / length, d * (x1 - x0) / length
+}
+
+// mitre returns the offset of the mitre point of the join between the
+// offset vectors (nx0, ny0) and (nx1, ny1), which have length d, and
+// whether it is within mitreLimit times d.
+func mitre(nx0, ny0, nx1, ny1, d, mitreLimit float64) (float64, float64, bool) {
+	ux, uy := (nx0+nx1)/2, (ny0+ny1)/2
+	u2 := ux*ux + uy*uy
+	if u2 == 0 || d*d > mitreLimit*mitreLimit*u2 {
+		return 0, 0, false
+	}
+	scale := d * d / u2
+	return scale * ux, scale * uy, true
+}
+
+func appendPiece(pieces []unionPolygon, ring []float64) []unionPolygon {
+	return append(pieces, unionPolygon{rings: [][]float64{ring}, box: ringBox(ring)})
+}
+
+// polygonRings returns the xy coordinates of the rings of p.
+func polygonRings(p *geom.Polygon) [][]float64 {
+	rings := make([][]float64, p.NumLinearRings())
+	for i := range rings {
+		rings[i] = xyCoords(p.LinearRing(i).FlatCoords(), p.Stride())
+	}
+	return rings
+}
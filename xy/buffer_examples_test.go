@@ -0,0 +1,18 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleBuffer() {
+	route := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0})
+	corridor, err := xy.Buffer(route, 2, xy.BufferOptionCapStyle(xy.CapFlat))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(corridor.FlatCoords())
+	// Output: [0 -2 10 -2 10 2 0 2 0 -2]
+}
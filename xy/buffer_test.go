@@ -0,0 +1,180 @@
+package xy_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestBuffer(t *testing.T) {
+	square := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}).SetSRID(3857)
+	corner := geom.NewLineStringFlat(geom.XYZ, []float64{0, 0, 1, 10, 0, 2, 10, 10, 3})
+	// The area of the regular 32-gon that approximates a unit circle.
+	circleArea := 16 * math.Sin(math.Pi/16)
+	for _, tc := range []struct {
+		name         string
+		g            geom.T
+		distance     float64
+		opts         []xy.BufferOption
+		expected     geom.T
+		expectedArea float64
+	}{
+		{
+			name:         "point",
+			g:            geom.NewPointFlat(geom.XY, []float64{1, 2}),
+			distance:     1,
+			expectedArea: circleArea,
+		},
+		{
+			name:     "point_square_cap",
+			g:        geom.NewPointFlat(geom.XY, []float64{1, 2}),
+			distance: 1,
+			opts:     []xy.BufferOption{xy.BufferOptionCapStyle(xy.CapSquare)},
+			expected: geom.NewPolygonFlat(geom.XY, []float64{0, 1, 2, 1, 2, 3, 0, 3, 0, 1}, []int{10}),
+		},
+		{
+			name:     "point_flat_cap",
+			g:        geom.NewPointFlat(geom.XY, []float64{1, 2}),
+			distance: 1,
+			opts:     []xy.BufferOption{xy.BufferOptionCapStyle(xy.CapFlat)},
+			expected: geom.NewPolygon(geom.XY),
+		},
+		{
+			name:         "point_quadrant_segments",
+			g:            geom.NewPointFlat(geom.XY, []float64{1, 2}),
+			distance:     1,
+			opts:         []xy.BufferOption{xy.BufferOptionQuadrantSegments(1)},
+			expectedArea: 2,
+		},
+		{
+			name:     "line_flat_cap",
+			g:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			distance: 1,
+			opts:     []xy.BufferOption{xy.BufferOptionCapStyle(xy.CapFlat)},
+			expected: geom.NewPolygonFlat(geom.XY, []float64{0, -1, 10, -1, 10, 1, 0, 1, 0, -1}, []int{10}),
+		},
+		{
+			name:     "line_square_cap",
+			g:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			distance: 1,
+			opts:     []xy.BufferOption{xy.BufferOptionCapStyle(xy.CapSquare)},
+			expected: geom.NewPolygonFlat(geom.XY, []float64{-1, -1, 0, -1, 10, -1, 11, -1, 11, 1, 10, 1, 0, 1, -1, 1, -1, -1}, []int{18}),
+		},
+		{
+			name:         "line_round_cap",
+			g:            geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			distance:     1,
+			expectedArea: 20 + circleArea,
+		},
+		{
+			name:         "corner_mitre_join",
+			g:            corner,
+			distance:     1,
+			opts:         []xy.BufferOption{xy.BufferOptionCapStyle(xy.CapFlat), xy.BufferOptionJoinStyle(xy.JoinMitre)},
+			expectedArea: 40,
+		},
+		{
+			name:     "corner_mitre_join_limit",
+			g:        corner,
+			distance: 1,
+			opts: []xy.BufferOption{
+				xy.BufferOptionCapStyle(xy.CapFlat),
+				xy.BufferOptionJoinStyle(xy.JoinMitre),
+				xy.BufferOptionMitreLimit(1.2),
+			},
+			expectedArea: 39.5,
+		},
+		{
+			name:         "corner_bevel_join",
+			g:            corner,
+			distance:     1,
+			opts:         []xy.BufferOption{xy.BufferOptionCapStyle(xy.CapFlat), xy.BufferOptionJoinStyle(xy.JoinBevel)},
+			expectedArea: 39.5,
+		},
+		{
+			name:         "corner_round_join",
+			g:            corner,
+			distance:     1,
+			opts:         []xy.BufferOption{xy.BufferOptionCapStyle(xy.CapFlat)},
+			expectedArea: 39 + circleArea/4,
+		},
+		{
+			name:         "polygon",
+			g:            square,
+			distance:     1,
+			expectedArea: 140 + circleArea,
+		},
+		{
+			name:     "polygon_mitre_join",
+			g:        square,
+			distance: 1,
+			opts:     []xy.BufferOption{xy.BufferOptionJoinStyle(xy.JoinMitre)},
+			expected: geom.NewPolygonFlat(geom.XY, []float64{
+				-1, -1, 0, -1, 10, -1, 11, -1, 11, 0, 11, 10, 11, 11, 10, 11, 0, 11, -1, 11, -1, 10, -1, 0, -1, -1,
+			}, []int{26}).SetSRID(3857),
+		},
+		{
+			name:     "polygon_erosion",
+			g:        square,
+			distance: -1,
+			expected: geom.NewPolygonFlat(geom.XY, []float64{1, 1, 9, 1, 9, 9, 1, 9, 1, 1}, []int{10}).SetSRID(3857),
+		},
+		{
+			name:     "polygon_erosion_collapse",
+			g:        square,
+			distance: -5,
+			expected: geom.NewPolygon(geom.XY).SetSRID(3857),
+		},
+		{
+			name: "polygon_with_hole_erosion",
+			g: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+				4, 4, 4, 6, 6, 6, 6, 4, 4, 4,
+			}, []int{10, 20}),
+			distance:     -1,
+			opts:         []xy.BufferOption{xy.BufferOptionJoinStyle(xy.JoinMitre)},
+			expectedArea: 64 - 16,
+		},
+		{
+			name:     "line_negative_distance",
+			g:        corner,
+			distance: -1,
+			expected: geom.NewPolygon(geom.XY),
+		},
+		{
+			name: "geometrycollection",
+			g: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{20, 20}),
+				square,
+			),
+			distance:     1,
+			opts:         []xy.BufferOption{xy.BufferOptionJoinStyle(xy.JoinMitre), xy.BufferOptionCapStyle(xy.CapSquare)},
+			expectedArea: 144 + 4,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := xy.Buffer(tc.g, tc.distance, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := xy.Validate(got); err != nil {
+				t.Errorf("expected a valid result but got %v", err)
+			}
+			if tc.expected != nil && !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v but got %v", tc.expected, got)
+			}
+			if tc.expectedArea != 0 {
+				polygonal, ok := got.(interface{ Area() float64 })
+				if !ok {
+					t.Fatalf("expected a polygonal result but got %T", got)
+				}
+				if area := polygonal.Area(); math.Abs(area-tc.expectedArea) > 1e-9 {
+					t.Errorf("expected area %f but got %f", tc.expectedArea, area)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package xy
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/twpayne/go-geom"
+)
+
+// ConcaveHull returns a concave hull of the points of g: a polygon that
+// contains all of them, formed by removing the triangles with long edges from
+// the border of their Delaunay triangulation. ratio, from zero to one, sets
+// the maximum length of the edges that remain on the border as a fraction of
+// the way from the shortest to the longest edge of the triangulation, so a
+// ratio of one gives the convex hull and a ratio of zero gives the most
+// concave hull. Triangles are only removed if the hull remains a single
+// polygon containing all of the points. If allowHoles is true then the hull
+// may also have holes, formed by removing triangles with long edges from its
+// interior.
+//
+// The result is a *geom.Polygon with layout geom.XY and the SRID of g. If the
+// points are all collinear, or there are fewer than three distinct points,
+// then the result is their convex hull, which may be a *geom.Point or
+// *geom.LineString, or nil if g is empty. If g is of an unsupported type,
+// including nil, then the result is nil.
+func ConcaveHull(g geom.T, ratio float64, allowHoles bool) geom.T {
+	if checkSupported(g) != nil {
+		return nil
+	}
+	flatCoords := appendXYCoords(nil, g)
+	// The hull is built from the triangles alone, which refer to points by
+	// their index in flatCoords, so the distinct vertices are not needed.
+	t, _ := delaunay(flatCoords, 2)
+	if len(t.triangles) == 0 {
+		hull := ConvexHullFlat(geom.XY, flatCoords)
+		if hull == nil {
+			return nil
+		}
+		return setSRID(hull, g.SRID())
+	}
+
+	minLength, maxLength := math.Inf(1), 0.0
+	for _, triangle := range t.triangles {
+		for j := range 3 {
+			length := t.edgeLength(triangle[j], triangle[(j+1)%3])
+			minLength = min(minLength, length)
+			maxLength = max(maxLength, length)
+		}
+	}
+	ratio = min(max(ratio, 0), 1)
+
+	h := &concaveHull{
+		t:              t,
+		maxLength:      minLength + ratio*(maxLength-minLength),
+		removed:        make([]bool, len(t.triangles)),
+		boundaryVertex: make([]bool, len(flatCoords)/2),
+	}
+	for i, triangle := range t.triangles {
+		for j := range 3 {
+			if h.neighbor(i, j) == -1 {
+				h.boundaryVertex[triangle[j]] = true
+				h.boundaryVertex[triangle[(j+1)%3]] = true
+			}
+		}
+	}
+	for i := range t.triangles {
+		h.push(i)
+	}
+	h.erode()
+
+	if allowHoles {
+		// Start holes at the interior triangles with the longest edges.
+		var seeds []int
+		for i := range t.triangles {
+			if h.isHoleSeed(i) {
+				seeds = append(seeds, i)
+			}
+		}
+		sort.SliceStable(seeds, func(i, j int) bool {
+			return h.longestEdge(seeds[i]) > h.longestEdge(seeds[j])
+		})
+		for _, i := range seeds {
+			if h.isHoleSeed(i) {
+				h.remove(i)
+				h.erode()
+			}
+		}
+	}
+
+	return h.polygon().SetSRID(g.SRID())
+}
+
+// appendXYCoords appends the x and y ordinates of the points of g, without
+// consecutive repeated points, to flatCoords.
+func appendXYCoords(flatCoords []float64, g geom.T) []float64 {
+	if gc, ok := g.(*geom.GeometryCollection); ok {
+		for _, child := range gc.Geoms() {
+			flatCoords = appendXYCoords(flatCoords, child)
+		}
+		return flatCoords
+	}
+	return append(flatCoords, xyCoords(g.FlatCoords(), g.Stride())...)
+}
+
+// setSRID sets the SRID of g, which is a *geom.Point, *geom.LineString, or
+// *geom.Polygon.
+func setSRID(g geom.T, srid int) geom.T {
+	switch g := g.(type) {
+	case *geom.Point:
+		return g.SetSRID(srid)
+	case *geom.LineString:
+		return g.SetSRID(srid)
+	case *geom.Polygon:
+		return g.SetSRID(srid)
+	default:
+		return g
+	}
+}
+
+// A concaveHull is the state of the erosion of a Delaunay triangulation into
+// a concave hull.
+type concaveHull struct {
+	t              *triangulation
+	maxLength      float64
+	removed        []bool
+	boundaryVertex []bool
+	queue          concaveHullQueue
+}
+
+// neighbor returns the index of the triangle across edge j of triangle i, or
+// -1 if there is no such triangle or it has been removed.
+func (h *concaveHull) neighbor(i, j int) int {
+	triangle := h.t.triangles[i]
+	k, ok := h.t.halfEdges[[2]int{triangle[(j+1)%3], triangle[j]}]
+	if !ok || h.removed[k] {
+		return -1
+	}
+	return k
+}
+
+// borderEdge returns the index of the only edge of triangle i that is on the
+// boundary of the hull, or -1 if triangle i does not have exactly one such
+// edge.
+func (h *concaveHull) borderEdge(i int) int {
+	border := -1
+	for j := range 3 {
+		if h.neighbor(i, j) == -1 {
+			if border != -1 {
+				return -1
+			}
+			border = j
+		}
+	}
+	return border
+}
+
+// longestEdge returns the length of the longest edge of triangle i.
+func (h *concaveHull) longestEdge(i int) float64 {
+	triangle := h.t.triangles[i]
+	return max(
+		h.t.edgeLength(triangle[0], triangle[1]),
+		h.t.edgeLength(triangle[1], triangle[2]),
+		h.t.edgeLength(triangle[2], triangle[0]),
+	)
+}
+
+// isRemovable returns whether triangle i can be removed from the border of
+// the hull. It must have exactly one long edge on the border, and its
+// opposite vertex must not be on the border, otherwise removing it would
+// either remove a vertex or split the hull.
+func (h *concaveHull) isRemovable(i int) (float64, bool) {
+	if h.removed[i] {
+		return 0, false
+	}
+	j := h.borderEdge(i)
+	if j == -1 {
+		return 0, false
+	}
+	triangle := h.t.triangles[i]
+	length := h.t.edgeLength(triangle[j], triangle[(j+1)%3])
+	if length <= h.maxLength || h.boundaryVertex[triangle[(j+2)%3]] {
+		return 0, false
+	}
+	return length, true
+}
+
+// isHoleSeed returns whether triangle i can be removed to start a hole. It
+// must have a long edge and none of its vertices can be on the boundary.
+func (h *concaveHull) isHoleSeed(i int) bool {
+	if h.removed[i] {
+		return false
+	}
+	for _, v := range h.t.triangles[i] {
+		if h.boundaryVertex[v] {
+			return false
+		}
+	}
+	return h.longestEdge(i) > h.maxLength
+}
+
+// push adds triangle i to the queue if it is removable.
+func (h *concaveHull) push(i int) {
+	if length, ok := h.isRemovable(i); ok {
+		heap.Push(&h.queue, concaveHullItem{triangle: i, length: length})
+	}
+}
+
+// remove removes triangle i and adds its neighbors to the queue.
+func (h *concaveHull) remove(i int) {
+	h.removed[i] = true
+	for j, v := range h.t.triangles[i] {
+		h.boundaryVertex[v] = true
+		if k := h.neighbor(i, j); k != -1 {
+			h.push(k)
+		}
+	}
+}
+
+// erode removes triangles from the border of the hull in order of decreasing
+// border edge length until none can be removed.
+func (h *concaveHull) erode() {
+	for h.queue.Len() > 0 {
+		item := heap.Pop(&h.queue).(concaveHullItem)
+		if _, ok := h.isRemovable(item.triangle); ok {
+			h.remove(item.triangle)
+		}
+	}
+}
+
+// polygon returns the polygon formed by the remaining triangles.
+func (h *concaveHull) polygon() *geom.Polygon {
+	next := make(map[int]int)
+	var starts []int
+	for i, triangle := range h.t.triangles {
+		if h.removed[i] {
+			continue
+		}
+		for j := range 3 {
+			if h.neighbor(i, j) == -1 {
+				next[triangle[j]] = triangle[(j+1)%3]
+				starts = append(starts, triangle[j])
+			}
+		}
+	}
+	var shell []float64
+	var holes [][]float64
+	for _, start := range starts {
+		if _, ok := next[start]; !ok {
+			continue
+		}
+		var ring []float64
+		for v := start; ; {
+			x, y := h.t.xy(v)
+			ring = append(ring, x, y)
+			w := next[v]
+			delete(next, v)
+			if v = w; v == start {
+				break
+			}
+		}
+		ring = append(ring, ring[0], ring[1])
+		if SignedArea(geom.XY, ring) < 0 {
+			shell = ring
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+	flatCoords := shell
+	ends := []int{len(flatCoords)}
+	for _, hole := range holes {
+		flatCoords = append(flatCoords, hole...)
+		ends = append(ends, len(flatCoords))
+	}
+	return geom.NewPolygonFlat(geom.XY, flatCoords, ends)
+}
+
+// edgeLength returns the length of the edge between vertices a and b.
+func (t *triangulation) edgeLength(a, b int) float64 {
+	ax, ay := t.xy(a)
+	bx, by := t.xy(b)
+	return math.Hypot(bx-ax, by-ay)
+}
+
+// A concaveHullItem is a triangle in a concaveHullQueue.
+type concaveHullItem struct {
+	triangle int
+	length   float64
+}
+
+// A concaveHullQueue is a priority queue of triangles with the longest border
+// edge first.
+type concaveHullQueue []concaveHullItem
+
+func (q concaveHullQueue) Len() int { return len(q) }
+
+func (q concaveHullQueue) Less(i, j int) bool {
+	if q[i].length != q[j].length {
+		return q[i].length > q[j].length
+	}
+	return q[i].triangle < q[j].triangle
+}
+
+func (q concaveHullQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *concaveHullQueue) Push(x any) { *q = append(*q, x.(concaveHullItem)) }
+
+func (q *concaveHullQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,27 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleConcaveHull() {
+	// Points covering an L-shaped area.
+	var flatCoords []float64
+	for x := 0; x <= 4; x++ {
+		for y := 0; y <= 4; y++ {
+			if x <= 1 || y <= 1 {
+				flatCoords = append(flatCoords, float64(x), float64(y))
+			}
+		}
+	}
+	mp := geom.NewMultiPointFlat(geom.XY, flatCoords)
+
+	fmt.Println(xy.ConcaveHull(mp, 1, false).(*geom.Polygon).Area())
+	fmt.Println(xy.ConcaveHull(mp, 0, false).(*geom.Polygon).Area())
+	// Output:
+	// 11.5
+	// 7
+}
@@ -0,0 +1,145 @@
+package xy_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+// lShapedPoints returns a grid of points in the shape of an L.
+func lShapedPoints() *geom.MultiPoint {
+	var flatCoords []float64
+	for x := 0; x <= 8; x++ {
+		for y := 0; y <= 8; y++ {
+			if x <= 2 || y <= 2 {
+				flatCoords = append(flatCoords, float64(x), float64(y))
+			}
+		}
+	}
+	return geom.NewMultiPointFlat(geom.XY, flatCoords).SetSRID(4326)
+}
+
+// annularPoints returns a grid of points in the shape of a square with a
+// square hole.
+func annularPoints() *geom.MultiPoint {
+	var flatCoords []float64
+	for x := 0; x <= 6; x++ {
+		for y := 0; y <= 6; y++ {
+			if x <= 1 || y <= 1 || x >= 5 || y >= 5 {
+				flatCoords = append(flatCoords, float64(x), float64(y))
+			}
+		}
+	}
+	return geom.NewMultiPointFlat(geom.XY, flatCoords)
+}
+
+func TestConcaveHull(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		g             geom.T
+		ratio         float64
+		allowHoles    bool
+		expectedArea  float64
+		expectedRings int
+	}{
+		{
+			name:          "l_shape_concave",
+			g:             lShapedPoints(),
+			ratio:         0,
+			expectedArea:  28,
+			expectedRings: 1,
+		},
+		{
+			name:          "l_shape_intermediate",
+			g:             lShapedPoints(),
+			ratio:         0.5,
+			expectedArea:  32.5,
+			expectedRings: 1,
+		},
+		{
+			name:          "l_shape_convex",
+			g:             lShapedPoints(),
+			ratio:         1,
+			expectedArea:  46,
+			expectedRings: 1,
+		},
+		{
+			name:          "annulus_without_holes",
+			g:             annularPoints(),
+			ratio:         0,
+			expectedArea:  36,
+			expectedRings: 1,
+		},
+		{
+			name:          "annulus_with_holes",
+			g:             annularPoints(),
+			ratio:         0,
+			allowHoles:    true,
+			expectedArea:  20,
+			expectedRings: 2,
+		},
+		{
+			name: "geometry_collection",
+			g: geom.NewGeometryCollection().MustPush(
+				geom.NewLineStringFlat(geom.XYZ, []float64{0, 0, 1, 4, 0, 2, 4, 1, 3}),
+				geom.NewPointFlat(geom.XY, []float64{0, 4}),
+				geom.NewPointFlat(geom.XY, []float64{1, 1}),
+				geom.NewPointFlat(geom.XY, []float64{1, 4}),
+			),
+			ratio:         0,
+			expectedArea:  7,
+			expectedRings: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := xy.ConcaveHull(tc.g, tc.ratio, tc.allowHoles).(*geom.Polygon)
+			if !ok {
+				t.Fatalf("expected a *geom.Polygon, got %T", got)
+			}
+			if got.Area() != tc.expectedArea {
+				t.Errorf("expected area %v, got %v", tc.expectedArea, got.Area())
+			}
+			if got.NumLinearRings() != tc.expectedRings {
+				t.Errorf("expected %d rings, got %d", tc.expectedRings, got.NumLinearRings())
+			}
+			if got.SRID() != tc.g.SRID() {
+				t.Errorf("expected SRID %d, got %d", tc.g.SRID(), got.SRID())
+			}
+			if err := xy.Validate(got); err != nil {
+				t.Errorf("expected valid polygon, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConcaveHullDegenerate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		g        geom.T
+		expected geom.T
+	}{
+		{
+			name:     "point",
+			g:        geom.NewPointFlat(geom.XY, []float64{1, 2}).SetSRID(4326),
+			expected: geom.NewPointFlat(geom.XY, []float64{1, 2}).SetSRID(4326),
+		},
+		{
+			name:     "empty",
+			g:        geom.NewMultiPoint(geom.XY),
+			expected: nil,
+		},
+		{
+			name:     "unsupported",
+			g:        nil,
+			expected: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := xy.ConcaveHull(tc.g, 0, false); !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
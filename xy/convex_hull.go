@@ -183,14 +183,15 @@ func (calc *convexHullCalculator) reduce(inputPts []float64) []float64 {
 	}
 
 	/**
-	 * Add all unique points not in the interior poly.
+	 * Add all unique points not in the interior poly, which must be closed.
 	 * CGAlgorithms.isPointInRing is not defined for points actually on the ring,
 	 * but this doesn't matter since the points of the interior polygon
 	 * are forced to be in the reduced set.
 	 */
+	ring := append(polyPts[:len(polyPts):len(polyPts)], polyPts[:calc.stride]...)
 	for i := 0; i < len(inputPts); i += calc.stride {
 		pt := geom.Coord(inputPts[i : i+calc.stride])
-		if !IsPointInRing(calc.layout, pt, polyPts) {
+		if !IsPointInRing(calc.layout, pt, ring) {
 			reducedSet.Insert(pt)
 		}
 	}
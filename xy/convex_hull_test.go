@@ -1,11 +1,13 @@
 package xy
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
 	"github.com/twpayne/go-geom"
 	"github.com/twpayne/go-geom/xy/internal"
+	"github.com/twpayne/go-geom/xy/location"
 )
 
 func TestConvexHull(t *testing.T) {
@@ -46,6 +48,22 @@ func TestConvexHull(t *testing.T) {
 	}
 }
 
+func TestConvexHullManyPoints(t *testing.T) {
+	// More than 50 points are reduced to those outside an octagon of extreme
+	// points before the Graham scan.
+	var flatCoords []float64
+	for i := range 60 {
+		angle := 2 * math.Pi * float64(i) / 60
+		flatCoords = append(flatCoords, math.Round(100*math.Cos(angle)), math.Round(100*math.Sin(angle)))
+	}
+	convexHull := ConvexHullFlat(geom.XY, flatCoords).(*geom.Polygon)
+	for i := 0; i < len(flatCoords); i += 2 {
+		if LocatePointInRing(geom.XY, geom.Coord(flatCoords[i:i+2]), convexHull.FlatCoords()) == location.Exterior {
+			t.Errorf("%v is outside %v", flatCoords[i:i+2], convexHull.FlatCoords())
+		}
+	}
+}
+
 func TestPresort(t *testing.T) {
 	calc := &convexHullCalculator{layout: geom.XY, stride: 2}
 	coords := append([]float64{}, internal.TestRing.FlatCoords()...)
@@ -0,0 +1,252 @@
+package xy
+
+import (
+	"sort"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/orientation"
+)
+
+// DelaunayTriangles returns the Delaunay triangulation of the points of mp as
+// a MultiPolygon of counterclockwise triangles with the layout and SRID of
+// mp. Repeated points are ignored. If the points are all collinear, or there
+// are fewer than three distinct points, then the result is empty.
+func DelaunayTriangles(mp *geom.MultiPoint) *geom.MultiPolygon {
+	t, _ := delaunay(mp.FlatCoords(), mp.Stride())
+	flatCoords, stride := mp.FlatCoords(), mp.Stride()
+	triangleFlatCoords := make([]float64, 0, 4*stride*len(t.triangles))
+	endss := make([][]int, 0, len(t.triangles))
+	for _, triangle := range t.triangles {
+		for _, i := range []int{triangle[0], triangle[1], triangle[2], triangle[0]} {
+			triangleFlatCoords = append(triangleFlatCoords, flatCoords[i*stride:(i+1)*stride]...)
+		}
+		endss = append(endss, []int{len(triangleFlatCoords)})
+	}
+	return geom.NewMultiPolygonFlat(mp.Layout(), triangleFlatCoords, endss).SetSRID(mp.SRID())
+}
+
+// DelaunayEdges returns the edges of the Delaunay triangulation of the points
+// of mp as a MultiLineString of two point LineStrings with the layout and
+// SRID of mp. Repeated points are ignored. If the points are all collinear
+// then the edges join consecutive points along their line.
+func DelaunayEdges(mp *geom.MultiPoint) *geom.MultiLineString {
+	flatCoords, stride := mp.FlatCoords(), mp.Stride()
+	t, vertices := delaunay(flatCoords, stride)
+	var edgeFlatCoords []float64
+	var ends []int
+	addEdge := func(a, b int) {
+		edgeFlatCoords = append(edgeFlatCoords, flatCoords[a*stride:(a+1)*stride]...)
+		edgeFlatCoords = append(edgeFlatCoords, flatCoords[b*stride:(b+1)*stride]...)
+		ends = append(ends, len(edgeFlatCoords))
+	}
+	if len(t.triangles) == 0 {
+		for i := 1; i < len(vertices); i++ {
+			addEdge(vertices[i-1], vertices[i])
+		}
+	}
+	for _, triangle := range t.triangles {
+		for j := range 3 {
+			a, b := triangle[j], triangle[(j+1)%3]
+			if _, ok := t.halfEdges[[2]int{b, a}]; a < b || !ok {
+				addEdge(a, b)
+			}
+		}
+	}
+	return geom.NewMultiLineStringFlat(mp.Layout(), edgeFlatCoords, ends).SetSRID(mp.SRID())
+}
+
+// VoronoiDiagram returns the Voronoi diagram of the points of mp clipped to
+// envelope as a MultiPolygon with the SRID of mp. The MultiPolygon contains
+// one counterclockwise cell for each distinct point of mp, in the order in
+// which the points first occur, containing the points of envelope that are
+// at least as close to that point as to any other. Cells that are entirely
+// outside envelope are empty. If envelope is nil then the bounds of mp
+// expanded by the larger of their width and height are used instead, so
+// that every cell is non-empty. Only the x and y ordinates are used, so the
+// result has layout geom.XY.
+func VoronoiDiagram(mp *geom.MultiPoint, envelope *geom.Bounds) *geom.MultiPolygon {
+	flatCoords, stride := mp.FlatCoords(), mp.Stride()
+	t, vertices := delaunay(flatCoords, stride)
+	result := geom.NewMultiPolygon(geom.XY).SetSRID(mp.SRID())
+	if len(vertices) == 0 {
+		return result
+	}
+
+	var minX, minY, maxX, maxY float64
+	if envelope == nil || envelope.IsEmpty() {
+		bounds := mp.Bounds()
+		expandBy := max(bounds.Max(0)-bounds.Min(0), bounds.Max(1)-bounds.Min(1))
+		if expandBy == 0 {
+			expandBy = 1
+		}
+		minX, minY = bounds.Min(0)-expandBy, bounds.Min(1)-expandBy
+		maxX, maxY = bounds.Max(0)+expandBy, bounds.Max(1)+expandBy
+	} else {
+		minX, minY, maxX, maxY = envelope.Min(0), envelope.Min(1), envelope.Max(0), envelope.Max(1)
+	}
+
+	// The Voronoi neighbors of each point are its Delaunay neighbors.
+	neighbors := make(map[int][]int)
+	if len(t.triangles) == 0 {
+		for i := 1; i < len(vertices); i++ {
+			neighbors[vertices[i-1]] = append(neighbors[vertices[i-1]], vertices[i])
+			neighbors[vertices[i]] = append(neighbors[vertices[i]], vertices[i-1])
+		}
+	}
+	for _, triangle := range t.triangles {
+		for j := range 3 {
+			a, b := triangle[j], triangle[(j+1)%3]
+			neighbors[a] = append(neighbors[a], b)
+			if _, ok := t.halfEdges[[2]int{b, a}]; !ok {
+				neighbors[b] = append(neighbors[b], a)
+			}
+		}
+	}
+
+	sites := append([]int(nil), vertices...)
+	sort.Ints(sites)
+	for _, site := range sites {
+		cell := []float64{minX, minY, maxX, minY, maxX, maxY, minX, maxY}
+		x, y := t.xy(site)
+		for _, neighbor := range neighbors[site] {
+			nx, ny := t.xy(neighbor)
+			cell = clipHalfPlane(cell, nx-x, ny-y, (nx*nx+ny*ny-x*x-y*y)/2)
+		}
+		if len(cell) < 6 {
+			_ = result.Push(geom.NewPolygon(geom.XY))
+			continue
+		}
+		cell = append(cell, cell[0], cell[1])
+		_ = result.Push(geom.NewPolygonFlat(geom.XY, cell, []int{len(cell)}))
+	}
+	return result
+}
+
+// clipHalfPlane returns the part of the convex polygon with flat coordinates
+// polygon, without a closing point, where a*x + b*y <= c.
+func clipHalfPlane(polygon []float64, a, b, c float64) []float64 {
+	var clipped []float64
+	n := len(polygon)
+	for i := 0; i < n; i += 2 {
+		px, py := polygon[(i+n-2)%n], polygon[(i+n-1)%n]
+		x, y := polygon[i], polygon[i+1]
+		prevValue, value := a*px+b*py-c, a*x+b*y-c
+		if prevValue < 0 && value > 0 || prevValue > 0 && value < 0 {
+			f := prevValue / (prevValue - value)
+			clipped = append(clipped, px+f*(x-px), py+f*(y-py))
+		}
+		if value <= 0 {
+			clipped = append(clipped, x, y)
+		}
+	}
+	return clipped
+}
+
+// delaunay returns the Delaunay triangulation of the distinct points of
+// flatCoords and the indices of the distinct points, sorted by x and then by
+// y. Of repeated points, the first is used. The points are added in sorted
+// order, so each new point is outside the convex hull of the previous points
+// and is joined to the edges of the hull that it can see.
+func delaunay(flatCoords []float64, stride int) (*triangulation, []int) {
+	t := newTriangulation(flatCoords, stride)
+	n := len(flatCoords) / stride
+	sorted := make([]int, n)
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		xi, yi := t.xy(sorted[i])
+		xj, yj := t.xy(sorted[j])
+		return xi < xj || xi == xj && yi < yj
+	})
+	vertices := make([]int, 0, n)
+	for _, i := range sorted {
+		if len(vertices) != 0 {
+			x, y := t.xy(i)
+			if lastX, lastY := t.xy(vertices[len(vertices)-1]); x == lastX && y == lastY {
+				continue
+			}
+		}
+		vertices = append(vertices, i)
+	}
+	if len(vertices) < 3 {
+		return t, vertices
+	}
+
+	// Start with a fan of triangles from the first point that is not
+	// collinear with the points before it.
+	k := 2
+	for k < len(vertices) && t.orientation(vertices[0], vertices[1], vertices[k]) == orientation.Collinear {
+		k++
+	}
+	if k == len(vertices) {
+		return t, vertices
+	}
+	apex := vertices[k]
+	next := make([]int, n)
+	prev := make([]int, n)
+	link := func(a, b int) {
+		next[a], prev[b] = b, a
+	}
+	if t.orientation(vertices[0], vertices[1], apex) == orientation.CounterClockwise {
+		for i := 0; i+1 < k; i++ {
+			t.addTriangle(vertices[i], vertices[i+1], apex)
+			link(vertices[i], vertices[i+1])
+		}
+		link(vertices[k-1], apex)
+		link(apex, vertices[0])
+	} else {
+		for i := 0; i+1 < k; i++ {
+			t.addTriangle(vertices[i+1], vertices[i], apex)
+			link(vertices[i+1], vertices[i])
+		}
+		link(vertices[0], apex)
+		link(apex, vertices[k-1])
+	}
+
+	last := apex
+	for _, q := range vertices[k+1:] {
+		visible := func(a, b int) bool {
+			return t.orientation(a, b, q) == orientation.Clockwise
+		}
+		// The previous point is usually on a visible edge.
+		start := last
+		if !visible(start, next[start]) && !visible(prev[start], start) {
+			for start = next[last]; start != last && !visible(start, next[start]); start = next[start] {
+			}
+		}
+		var edges [][2]int
+		right := start
+		for visible(right, next[right]) {
+			a, b := right, next[right]
+			t.addTriangle(a, q, b)
+			edges = append(edges, [2]int{a, b}, [2]int{a, q}, [2]int{q, b})
+			right = b
+		}
+		left := start
+		for visible(prev[left], left) {
+			a, b := prev[left], left
+			t.addTriangle(a, q, b)
+			edges = append(edges, [2]int{a, b}, [2]int{a, q}, [2]int{q, b})
+			left = a
+		}
+		if len(edges) == 0 {
+			continue
+		}
+		link(left, q)
+		link(q, right)
+		t.legalize(edges)
+		last = q
+	}
+	return t, vertices
+}
+
+// orientation returns the orientation of vertex c relative to the vector
+// from vertex a to vertex b, computed robustly.
+func (t *triangulation) orientation(a, b, c int) orientation.Type {
+	return OrientationIndex(
+		geom.Coord(t.flatCoords[a*t.stride:a*t.stride+2]),
+		geom.Coord(t.flatCoords[b*t.stride:b*t.stride+2]),
+		geom.Coord(t.flatCoords[c*t.stride:c*t.stride+2]),
+	)
+}
@@ -0,0 +1,26 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleDelaunayTriangles() {
+	mp := geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 4, 0, 4, 3, 0, 3, 2, 1})
+	triangles := xy.DelaunayTriangles(mp)
+	fmt.Println(triangles.NumPolygons(), triangles.Area())
+	// Output: 4 12
+}
+
+func ExampleVoronoiDiagram() {
+	depots := geom.NewMultiPointFlat(geom.XY, []float64{1, 1, 3, 1})
+	cells := xy.VoronoiDiagram(depots, geom.NewBounds(geom.XY).Set(0, 0, 4, 2))
+	for i := range cells.NumPolygons() {
+		fmt.Println(cells.Polygon(i).Area())
+	}
+	// Output:
+	// 4
+	// 4
+}
@@ -0,0 +1,211 @@
+package xy_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestDelaunayTriangles(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		mp                *geom.MultiPoint
+		expectedTriangles int
+		expectedArea      float64
+	}{
+		{
+			name: "empty",
+			mp:   geom.NewMultiPoint(geom.XY),
+		},
+		{
+			name: "collinear",
+			mp:   geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 2, 2, 1, 1, 3, 3}),
+		},
+		{
+			name: "repeated",
+			mp:   geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 0, 0, 1, 0, 1, 0}),
+		},
+		{
+			name:              "triangle",
+			mp:                geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 0, 1, 1, 0}),
+			expectedTriangles: 1,
+			expectedArea:      0.5,
+		},
+		{
+			name:              "collinear_then_apex",
+			mp:                geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 0, 1, 0, 2, 0, 3, 1, 1.5}),
+			expectedTriangles: 3,
+			expectedArea:      1.5,
+		},
+		{
+			name: "grid",
+			mp: geom.NewMultiPointFlat(geom.XY, []float64{
+				0, 0, 1, 0, 2, 0,
+				0, 1, 1, 1, 2, 1,
+				0, 2, 1, 2, 2, 2,
+			}),
+			expectedTriangles: 8,
+			expectedArea:      4,
+		},
+		{
+			name: "kite",
+			mp: geom.NewMultiPointFlat(geom.XY, []float64{
+				0, 0, 10, 0, 5, 1, 5, -1,
+			}),
+			expectedTriangles: 2,
+			expectedArea:      10,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := xy.DelaunayTriangles(tc.mp)
+			if got.NumPolygons() != tc.expectedTriangles {
+				t.Fatalf("expected %d triangles, got %d", tc.expectedTriangles, got.NumPolygons())
+			}
+			if math.Abs(got.Area()-tc.expectedArea) > 1e-9 {
+				t.Errorf("expected area %v, got %v", tc.expectedArea, got.Area())
+			}
+			flatCoords := tc.mp.FlatCoords()
+			for i := range got.NumPolygons() {
+				c := got.Polygon(i).FlatCoords()
+				for j := 0; j < len(flatCoords); j += 2 {
+					dx, dy := flatCoords[j], flatCoords[j+1]
+					adx, ady := c[0]-dx, c[1]-dy
+					bdx, bdy := c[2]-dx, c[3]-dy
+					cdx, cdy := c[4]-dx, c[5]-dy
+					det := (adx*adx+ady*ady)*(bdx*cdy-cdx*bdy) + (bdx*bdx+bdy*bdy)*(cdx*ady-adx*cdy) + (cdx*cdx+cdy*cdy)*(adx*bdy-bdx*ady)
+					if det > 1e-9 {
+						t.Errorf("point %v is inside the circumcircle of %v", flatCoords[j:j+2], c[:6])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDelaunayTrianglesLayout(t *testing.T) {
+	mp := geom.NewMultiPointFlat(geom.XYZ, []float64{0, 0, 1, 1, 0, 2, 0, 1, 3}).SetSRID(4326)
+	expected := geom.NewMultiPolygonFlat(geom.XYZ, []float64{0, 1, 3, 0, 0, 1, 1, 0, 2, 0, 1, 3}, [][]int{{12}}).SetSRID(4326)
+	if got := xy.DelaunayTriangles(mp); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected %v, got %v", expected.FlatCoords(), got.FlatCoords())
+	}
+}
+
+func TestDelaunayEdges(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		mp       *geom.MultiPoint
+		expected *geom.MultiLineString
+	}{
+		{
+			name:     "empty",
+			mp:       geom.NewMultiPoint(geom.XY),
+			expected: geom.NewMultiLineStringFlat(geom.XY, nil, nil),
+		},
+		{
+			name: "collinear",
+			mp:   geom.NewMultiPointFlat(geom.XY, []float64{2, 2, 0, 0, 1, 1}).SetSRID(3857),
+			expected: geom.NewMultiLineStringFlat(geom.XY, []float64{
+				0, 0, 1, 1,
+				1, 1, 2, 2,
+			}, []int{4, 8}).SetSRID(3857),
+		},
+		{
+			name: "kite",
+			mp:   geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 10, 0, 5, 1, 5, -1}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := xy.DelaunayEdges(tc.mp)
+			if tc.expected != nil {
+				if !reflect.DeepEqual(tc.expected, got) {
+					t.Errorf("expected %v, got %v", tc.expected.FlatCoords(), got.FlatCoords())
+				}
+				return
+			}
+			if got.NumLineStrings() != 5 {
+				t.Errorf("expected 5 edges, got %d", got.NumLineStrings())
+			}
+			for i := range got.NumLineStrings() {
+				if reflect.DeepEqual(got.LineString(i).FlatCoords(), []float64{0, 0, 10, 0}) || reflect.DeepEqual(got.LineString(i).FlatCoords(), []float64{10, 0, 0, 0}) {
+					t.Errorf("unexpected edge %v", got.LineString(i).FlatCoords())
+				}
+			}
+		})
+	}
+}
+
+func TestVoronoiDiagram(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		mp       *geom.MultiPoint
+		envelope *geom.Bounds
+		expected *geom.MultiPolygon
+	}{
+		{
+			name:     "empty",
+			mp:       geom.NewMultiPoint(geom.XY),
+			expected: geom.NewMultiPolygon(geom.XY),
+		},
+		{
+			name:     "single",
+			mp:       geom.NewMultiPointFlat(geom.XY, []float64{1, 1}).SetSRID(4326),
+			expected: geom.NewMultiPolygonFlat(geom.XY, []float64{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}, [][]int{{10}}).SetSRID(4326),
+		},
+		{
+			name:     "two",
+			mp:       geom.NewMultiPointFlat(geom.XYM, []float64{3, 1, 5, 1, 1, 6}),
+			envelope: geom.NewBounds(geom.XY).Set(0, 0, 4, 2),
+			expected: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				2, 0, 4, 0, 4, 2, 2, 2, 2, 0,
+				0, 0, 2, 0, 2, 2, 0, 2, 0, 0,
+			}, [][]int{{10}, {20}}),
+		},
+		{
+			name:     "outside_envelope",
+			mp:       geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 10, 0}),
+			envelope: geom.NewBounds(geom.XY).Set(-2, -1, 2, 1),
+			expected: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				-2, -1, 2, -1, 2, 1, -2, 1, -2, -1,
+			}, [][]int{{10}, {}}),
+		},
+		{
+			name: "square",
+			mp: geom.NewMultiPointFlat(geom.XY, []float64{
+				-1, -1, 1, -1, 1, 1, -1, 1, 0, 0,
+			}),
+			envelope: geom.NewBounds(geom.XY).Set(-2, -2, 2, 2),
+			expected: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				-2, -2, 0, -2, 0, -1, -1, 0, -2, 0, -2, -2,
+				0, -2, 2, -2, 2, 0, 1, 0, 0, -1, 0, -2,
+				2, 0, 2, 2, 0, 2, 0, 1, 1, 0, 2, 0,
+				-2, 0, -1, 0, 0, 1, 0, 2, -2, 2, -2, 0,
+				0, -1, 1, 0, 0, 1, -1, 0, 0, -1,
+			}, [][]int{{12}, {24}, {36}, {48}, {58}}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := xy.VoronoiDiagram(tc.mp, tc.envelope)
+			if got.NumPolygons() != tc.expected.NumPolygons() || got.SRID() != tc.expected.SRID() {
+				t.Fatalf("expected %v, got %v", tc.expected.FlatCoords(), got.FlatCoords())
+			}
+			// The rings may start at any vertex, so compare their areas
+			// and vertex sets.
+			for i := range got.NumPolygons() {
+				expectedPolygon, gotPolygon := tc.expected.Polygon(i), got.Polygon(i)
+				if math.Abs(expectedPolygon.Area()-gotPolygon.Area()) > 1e-9 {
+					t.Errorf("cell %d: expected %v, got %v", i, expectedPolygon.FlatCoords(), gotPolygon.FlatCoords())
+				}
+				for _, c := range expectedPolygon.Coords() {
+					for _, expectedCoord := range c {
+						if !xy.IsPointInRing(geom.XY, expectedCoord, gotPolygon.FlatCoords()) {
+							t.Errorf("cell %d: expected %v, got %v", i, expectedPolygon.FlatCoords(), gotPolygon.FlatCoords())
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package xy
+
+import (
+	"math"
+
+	"github.com/twpayne/go-geom"
+)
+
+// Densify returns ls with vertices added so that no segment is longer than
+// maxSegmentLength. Each segment is divided into the smallest number of equal
+// parts that are no longer than maxSegmentLength, and all ordinates of the
+// added vertices, including Z and M, are interpolated linearly between the
+// vertices of ls, so LineString.Interpolate gives the same results on the
+// result as on ls. The result has the layout and SRID of ls. If
+// maxSegmentLength is not positive then Densify returns a clone of ls.
+//
+// The segments are straight lines in the x and y ordinates. Use the Densify
+// functions of the geodesic package for lon/lat coordinates.
+func Densify(ls *geom.LineString, maxSegmentLength float64) *geom.LineString {
+	if !(maxSegmentLength > 0) {
+		return ls.Clone()
+	}
+	flatCoords, stride := ls.FlatCoords(), ls.Stride()
+	var result []float64
+	if len(flatCoords) != 0 {
+		result = append(result, flatCoords[:stride]...)
+	}
+	for i := stride; i < len(flatCoords); i += stride {
+		c0, c1 := flatCoords[i-stride:i], flatCoords[i:i+stride]
+		n := int(math.Ceil(math.Hypot(c1[0]-c0[0], c1[1]-c0[1]) / maxSegmentLength))
+		for k := 1; k < n; k++ {
+			f := float64(k) / float64(n)
+			for j := range stride {
+				result = append(result, c0[j]+f*(c1[j]-c0[j]))
+			}
+		}
+		result = append(result, c1...)
+	}
+	return geom.NewLineStringFlat(ls.Layout(), result).SetSRID(ls.SRID())
+}
@@ -0,0 +1,15 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleDensify() {
+	ls := geom.NewLineStringFlat(geom.XYM, []float64{0, 0, 0, 300, 400, 60})
+	densified := xy.Densify(ls, 125)
+	fmt.Println(densified.FlatCoords())
+	// Output: [0 0 0 75 100 15 150 200 30 225 300 45 300 400 60]
+}
@@ -0,0 +1,65 @@
+package xy_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestDensify(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		ls               *geom.LineString
+		maxSegmentLength float64
+		expected         *geom.LineString
+	}{
+		{
+			name:             "empty",
+			ls:               geom.NewLineString(geom.XY),
+			maxSegmentLength: 1,
+			expected:         geom.NewLineString(geom.XY),
+		},
+		{
+			name:             "short",
+			ls:               geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 0, 1, 1}),
+			maxSegmentLength: 1,
+			expected:         geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 0, 1, 1}),
+		},
+		{
+			name:             "divided",
+			ls:               geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 2.5}),
+			maxSegmentLength: 2.5,
+			expected:         geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2.5, 0, 5, 0, 7.5, 0, 10, 0, 10, 2.5}),
+		},
+		{
+			name:             "xyzm",
+			ls:               geom.NewLineStringFlat(geom.XYZM, []float64{0, 0, 100, 0, 4, 3, 200, 10}).SetSRID(3857),
+			maxSegmentLength: 3,
+			expected: geom.NewLineStringFlat(geom.XYZM, []float64{
+				0, 0, 100, 0,
+				2, 1.5, 150, 5,
+				4, 3, 200, 10,
+			}).SetSRID(3857),
+		},
+		{
+			name:             "repeated_point",
+			ls:               geom.NewLineStringFlat(geom.XY, []float64{0, 0, 0, 0, 2, 0}),
+			maxSegmentLength: 1,
+			expected:         geom.NewLineStringFlat(geom.XY, []float64{0, 0, 0, 0, 1, 0, 2, 0}),
+		},
+		{
+			name:             "zero_max_segment_length",
+			ls:               geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			maxSegmentLength: 0,
+			expected:         geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := xy.Densify(tc.ls, tc.maxSegmentLength); !reflect.DeepEqual(tc.expected, actual) {
+				t.Errorf("expected %v but got %v", tc.expected.FlatCoords(), actual.FlatCoords())
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package xy
+
+import (
+	"math"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal"
+	"github.com/twpayne/go-geom/xy/location"
+)
+
+// GeometryDistance returns the minimum distance between a and b, which may
+// be of any type. The distance is zero if a and b intersect, including when
+// one is inside a polygon of the other. Only the x and y ordinates are used.
+// If a or b is empty then GeometryDistance returns +Inf. Geometries of
+// unsupported types, including nil, are treated as empty.
+//
+// It is named GeometryDistance because Distance already returns the
+// distance between two coordinates.
+func GeometryDistance(a, b geom.T) float64 {
+	nearest := nearestPoints(a, b)
+	return nearest.distance
+}
+
+// NearestPoints returns a point of a and a point of b that are
+// GeometryDistance(a, b) apart. If a and b intersect then both points are
+// the same point of their intersection. The points have layout geom.XY. If a
+// or b is empty then NearestPoints returns nil, nil. Geometries of
+// unsupported types, including nil, are treated as empty.
+func NearestPoints(a, b geom.T) (geom.Coord, geom.Coord) {
+	nearest := nearestPoints(a, b)
+	if math.IsInf(nearest.distance, 1) {
+		return nil, nil
+	}
+	return geom.Coord{nearest.ax, nearest.ay}, geom.Coord{nearest.bx, nearest.by}
+}
+
+// distanceComponents are the components of a geometry for computing
+// distances. Points are lines with a single vertex, and the rings of
+// polygons are also lines.
+type distanceComponents struct {
+	lines    [][]float64
+	boxes    []internal.Box
+	polygons [][][]float64
+}
+
+// add adds the components of g to c.
+func (c *distanceComponents) add(g geom.T) {
+	switch g := g.(type) {
+	case *geom.Point:
+		if !g.Empty() {
+			c.addLine(g.FlatCoords()[:2])
+		}
+	case *geom.MultiPoint:
+		for i := range g.NumPoints() {
+			c.add(g.Point(i))
+		}
+	case *geom.LineString:
+		c.addLine(xyCoords(g.FlatCoords(), g.Stride()))
+	case *geom.LinearRing:
+		c.addLine(xyCoords(g.FlatCoords(), g.Stride()))
+	case *geom.MultiLineString:
+		for i := range g.NumLineStrings() {
+			c.add(g.LineString(i))
+		}
+	case *geom.Polygon:
+		if g.NumLinearRings() == 0 || g.LinearRing(0).Empty() {
+			return
+		}
+		rings := polygonRings(g)
+		for _, ring := range rings {
+			c.addLine(ring)
+		}
+		c.polygons = append(c.polygons, rings)
+	case *geom.MultiPolygon:
+		for i := range g.NumPolygons() {
+			c.add(g.Polygon(i))
+		}
+	case *geom.GeometryCollection:
+		for _, child := range g.Geoms() {
+			c.add(child)
+		}
+	}
+}
+
+func (c *distanceComponents) addLine(line []float64) {
+	if len(line) == 0 {
+		return
+	}
+	c.lines = append(c.lines, line)
+	c.boxes = append(c.boxes, ringBox(line))
+}
+
+// contains returns a vertex of other that is in the interior of a polygon
+// of c, if there is one.
+func (c *distanceComponents) contains(other *distanceComponents) (geom.Coord, bool) {
+	for _, line := range other.lines {
+		vertex := geom.Coord(line[:2])
+		for _, rings := range c.polygons {
+			if locatePointInPolygonRings(vertex, rings) == location.Interior {
+				return vertex, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// A nearestPair is a pair of points and the distance between them.
+type nearestPair struct {
+	distance       float64
+	ax, ay, bx, by float64
+}
+
+// nearestPoints returns the nearest points of a and b.
+func nearestPoints(a, b geom.T) nearestPair {
+	var ca, cb distanceComponents
+	ca.add(a)
+	cb.add(b)
+	nearest := nearestPair{distance: math.Inf(1)}
+	if len(ca.lines) == 0 || len(cb.lines) == 0 {
+		return nearest
+	}
+	if vertex, ok := cb.contains(&ca); ok {
+		return nearestPair{ax: vertex[0], ay: vertex[1], bx: vertex[0], by: vertex[1]}
+	}
+	if vertex, ok := ca.contains(&cb); ok {
+		return nearestPair{ax: vertex[0], ay: vertex[1], bx: vertex[0], by: vertex[1]}
+	}
+	for i, la := range ca.lines {
+		for j, lb := range cb.lines {
+			if boxDistance(ca.boxes[i], cb.boxes[j]) >= nearest.distance {
+				continue
+			}
+			nearest.lines(la, lb)
+			if nearest.distance == 0 {
+				return nearest
+			}
+		}
+	}
+	return nearest
+}
+
+// lines updates n with the nearest points of the lines la and lb, which are
+// xy coordinates without repeated points.
+func (n *nearestPair) lines(la, lb []float64) {
+	// A line with a single vertex is treated as a segment with zero length.
+	segments := func(line []float64) int {
+		return max(len(line)/2-1, 1)
+	}
+	segment := func(line []float64, i int) (float64, float64, float64, float64) {
+		if len(line) == 2 {
+			return line[0], line[1], line[0], line[1]
+		}
+		return line[2*i], line[2*i+1], line[2*i+2], line[2*i+3]
+	}
+	for i := range segments(la) {
+		ax0, ay0, ax1, ay1 := segment(la, i)
+		for j := range segments(lb) {
+			bx0, by0, bx1, by1 := segment(lb, j)
+			n.segments(ax0, ay0, ax1, ay1, bx0, by0, bx1, by1)
+			if n.distance == 0 {
+				return
+			}
+		}
+	}
+}
+
+// segments updates n with the nearest points of the segment from (ax0, ay0)
+// to (ax1, ay1) and the segment from (bx0, by0) to (bx1, by1).
+func (n *nearestPair) segments(ax0, ay0, ax1, ay1, bx0, by0, bx1, by1 float64) {
+	// If the segments cross then their intersection is the nearest point.
+	d0 := (bx1-bx0)*(ay0-by0) - (by1-by0)*(ax0-bx0)
+	d1 := (bx1-bx0)*(ay1-by0) - (by1-by0)*(ax1-bx0)
+	d2 := (ax1-ax0)*(by0-ay0) - (ay1-ay0)*(bx0-ax0)
+	d3 := (ax1-ax0)*(by1-ay0) - (ay1-ay0)*(bx1-ax0)
+	if ((d0 < 0 && d1 > 0) || (d0 > 0 && d1 < 0)) && ((d2 < 0 && d3 > 0) || (d2 > 0 && d3 < 0)) {
+		t := d0 / (d0 - d1)
+		x, y := ax0+t*(ax1-ax0), ay0+t*(ay1-ay0)
+		*n = nearestPair{ax: x, ay: y, bx: x, by: y}
+		return
+	}
+
+	// Otherwise one of the nearest points is an endpoint.
+	x, y := nearestOnSegment(ax0, ay0, bx0, by0, bx1, by1)
+	n.update(ax0, ay0, x, y)
+	x, y = nearestOnSegment(ax1, ay1, bx0, by0, bx1, by1)
+	n.update(ax1, ay1, x, y)
+	x, y = nearestOnSegment(bx0, by0, ax0, ay0, ax1, ay1)
+	n.update(x, y, bx0, by0)
+	x, y = nearestOnSegment(bx1, by1, ax0, ay0, ax1, ay1)
+	n.update(x, y, bx1, by1)
+}
+
+// update replaces the points of n with (ax, ay) and (bx, by) if they are
+// nearer.
+func (n *nearestPair) update(ax, ay, bx, by float64) {
+	if distance := math.Hypot(bx-ax, by-ay); distance < n.distance {
+		*n = nearestPair{distance: distance, ax: ax, ay: ay, bx: bx, by: by}
+	}
+}
+
+// nearestOnSegment returns the point of the segment from (x0, y0) to (x1,
+// y1) that is nearest to (x, y).
+func nearestOnSegment(x, y, x0, y0, x1, y1 float64) (float64, float64) {
+	dx, dy := x1-x0, y1-y0
+	length2 := dx*dx + dy*dy
+	if length2 == 0 {
+		return x0, y0
+	}
+	switch r := ((x-x0)*dx + (y-y0)*dy) / length2; {
+	case r <= 0:
+		return x0, y0
+	case r >= 1:
+		return x1, y1
+	default:
+		return x0 + r*dx, y0 + r*dy
+	}
+}
+
+// boxDistance returns the distance between the boxes a and b.
+func boxDistance(a, b internal.Box) float64 {
+	dx := max(a.MinX-b.MaxX, b.MinX-a.MaxX, 0)
+	dy := max(a.MinY-b.MaxY, b.MinY-a.MaxY, 0)
+	return math.Hypot(dx, dy)
+}
@@ -0,0 +1,16 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleNearestPoints() {
+	fix := geom.NewPointFlat(geom.XY, []float64{12, 3})
+	road := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 20, 10})
+	_, snapped := xy.NearestPoints(fix, road)
+	fmt.Println(snapped, xy.GeometryDistance(fix, road))
+	// Output: [12.5 2.5] 0.7071067811865476
+}
@@ -0,0 +1,135 @@
+package xy_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestGeometryDistance(t *testing.T) {
+	square := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10})
+	squareWithHole := geom.NewPolygonFlat(geom.XY, []float64{
+		0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+		2, 2, 2, 8, 8, 8, 8, 2, 2, 2,
+	}, []int{10, 20})
+	for _, tc := range []struct {
+		name      string
+		a, b      geom.T
+		distance  float64
+		expectedA geom.Coord
+		expectedB geom.Coord
+	}{
+		{
+			name:      "point_point",
+			a:         geom.NewPointFlat(geom.XYZ, []float64{0, 0, 5}),
+			b:         geom.NewPointFlat(geom.XY, []float64{3, 4}),
+			distance:  5,
+			expectedA: geom.Coord{0, 0},
+			expectedB: geom.Coord{3, 4},
+		},
+		{
+			name:      "point_linestring",
+			a:         geom.NewPointFlat(geom.XY, []float64{5, 3}),
+			b:         geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 20, 10}),
+			distance:  3,
+			expectedA: geom.Coord{5, 3},
+			expectedB: geom.Coord{5, 0},
+		},
+		{
+			name:      "point_on_linestring",
+			a:         geom.NewPointFlat(geom.XY, []float64{15, 5}),
+			b:         geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 20, 10}),
+			distance:  0,
+			expectedA: geom.Coord{15, 5},
+			expectedB: geom.Coord{15, 5},
+		},
+		{
+			name:      "crossing_linestrings",
+			a:         geom.NewLineStringFlat(geom.XY, []float64{0, 0, 4, 4}),
+			b:         geom.NewLineStringFlat(geom.XY, []float64{0, 4, 4, 0}),
+			distance:  0,
+			expectedA: geom.Coord{2, 2},
+			expectedB: geom.Coord{2, 2},
+		},
+		{
+			name:      "parallel_linestrings",
+			a:         geom.NewLineStringFlat(geom.XYM, []float64{0, 0, 0, 4, 0, 1}),
+			b:         geom.NewLineStringFlat(geom.XY, []float64{2, 1, 8, 2}),
+			distance:  1,
+			expectedA: geom.Coord{2, 0},
+			expectedB: geom.Coord{2, 1},
+		},
+		{
+			name:      "linestring_in_polygon",
+			a:         square,
+			b:         geom.NewLineStringFlat(geom.XY, []float64{1, 1, 2, 2}),
+			distance:  0,
+			expectedA: geom.Coord{1, 1},
+			expectedB: geom.Coord{1, 1},
+		},
+		{
+			name:      "polygon_in_hole",
+			a:         squareWithHole,
+			b:         geom.NewPolygonFlat(geom.XY, []float64{4, 4, 6, 4, 6, 6, 4, 6, 4, 4}, []int{10}),
+			distance:  2,
+			expectedA: geom.Coord{2, 4},
+			expectedB: geom.Coord{4, 4},
+		},
+		{
+			name:      "polygon_containing_polygon",
+			a:         geom.NewPolygonFlat(geom.XY, []float64{4, 4, 6, 4, 6, 6, 4, 6, 4, 4}, []int{10}),
+			b:         square,
+			distance:  0,
+			expectedA: geom.Coord{4, 4},
+			expectedB: geom.Coord{4, 4},
+		},
+		{
+			name:      "multipoint_multipolygon",
+			a:         geom.NewMultiPointFlat(geom.XY, []float64{30, 0, 15, 5}),
+			b:         geom.NewMultiPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0, 20, 0, 30, 0, 30, 10, 20, 0}, [][]int{{10}, {18}}),
+			distance:  0,
+			expectedA: geom.Coord{30, 0},
+			expectedB: geom.Coord{30, 0},
+		},
+		{
+			name: "geometrycollection",
+			a: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{-10, -10}),
+				geom.NewLineStringFlat(geom.XY, []float64{0, 12, 11, 13}),
+			),
+			b:         square,
+			distance:  2,
+			expectedA: geom.Coord{0, 12},
+			expectedB: geom.Coord{0, 10},
+		},
+		{
+			name:     "empty",
+			a:        square,
+			b:        geom.NewGeometryCollection(),
+			distance: math.Inf(1),
+		},
+		{
+			name:     "unsupported",
+			a:        square,
+			b:        nil,
+			distance: math.Inf(1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if distance := xy.GeometryDistance(tc.a, tc.b); distance != tc.distance {
+				t.Errorf("expected distance %f but got %f", tc.distance, distance)
+			}
+			gotA, gotB := xy.NearestPoints(tc.a, tc.b)
+			if !reflect.DeepEqual(gotA, tc.expectedA) || !reflect.DeepEqual(gotB, tc.expectedB) {
+				t.Errorf("expected nearest points %v and %v but got %v and %v", tc.expectedA, tc.expectedB, gotA, gotB)
+			}
+			gotB, gotA = xy.NearestPoints(tc.b, tc.a)
+			if !reflect.DeepEqual(gotA, tc.expectedA) || !reflect.DeepEqual(gotB, tc.expectedB) {
+				t.Errorf("expected reversed nearest points %v and %v but got %v and %v", tc.expectedB, tc.expectedA, gotB, gotA)
+			}
+		})
+	}
+}
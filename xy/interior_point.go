@@ -0,0 +1,205 @@
+package xy
+
+import (
+	"math"
+	"sort"
+
+	"github.com/twpayne/go-geom"
+)
+
+// InteriorPoint returns a point that is guaranteed to be in the interior of
+// g, unlike its centroid, which may be outside a concave polygon or a
+// MultiPolygon. Only the components of g with the highest dimension are
+// used, so the interior point of a GeometryCollection containing polygons
+// is inside one of the polygons.
+//
+// For polygons, the result is the midpoint of the widest interval where a
+// horizontal line through the middle of the polygons, avoiding their
+// vertices, crosses their interiors. For lines, the result is the interior
+// vertex nearest to the centroid of the lines, or the nearest endpoint if
+// there are no interior vertices. For points, the result is the point
+// nearest to their centroid.
+//
+// The result has only x and y ordinates. It returns nil if g is empty.
+func InteriorPoint(g geom.T) geom.Coord {
+	var c interiorPointComponents
+	c.add(g)
+	switch {
+	case len(c.polygons) != 0:
+		return c.areaInteriorPoint()
+	case len(c.lines) != 0:
+		return c.lineInteriorPoint()
+	case len(c.points) != 0:
+		return c.pointInteriorPoint()
+	default:
+		return nil
+	}
+}
+
+// interiorPointComponents are the non-empty components of a geometry, with
+// only their x and y ordinates, grouped by dimension.
+type interiorPointComponents struct {
+	points   []float64
+	lines    [][]float64
+	polygons [][][]float64
+}
+
+// add adds the components of g to c.
+func (c *interiorPointComponents) add(g geom.T) {
+	switch g := g.(type) {
+	case *geom.Point:
+		if !g.Empty() {
+			c.points = append(c.points, g.FlatCoords()[:2]...)
+		}
+	case *geom.MultiPoint:
+		for i := range g.NumPoints() {
+			c.add(g.Point(i))
+		}
+	case *geom.LineString:
+		if line := xyCoords(g.FlatCoords(), g.Stride()); len(line) != 0 {
+			c.lines = append(c.lines, line)
+		}
+	case *geom.LinearRing:
+		if line := xyCoords(g.FlatCoords(), g.Stride()); len(line) != 0 {
+			c.lines = append(c.lines, line)
+		}
+	case *geom.MultiLineString:
+		for i := range g.NumLineStrings() {
+			c.add(g.LineString(i))
+		}
+	case *geom.Polygon:
+		if g.NumLinearRings() != 0 && !g.LinearRing(0).Empty() {
+			c.polygons = append(c.polygons, polygonRings(g))
+		}
+	case *geom.MultiPolygon:
+		for i := range g.NumPolygons() {
+			c.add(g.Polygon(i))
+		}
+	case *geom.GeometryCollection:
+		for _, child := range g.Geoms() {
+			c.add(child)
+		}
+	}
+}
+
+// areaInteriorPoint returns the midpoint of the widest interval of the
+// interiors of the polygons of c along a horizontal scan line through the
+// middle of each polygon.
+func (c *interiorPointComponents) areaInteriorPoint() geom.Coord {
+	var interiorPoint geom.Coord
+	maxWidth := -1.0
+	for _, rings := range c.polygons {
+		shell := rings[0]
+		if maxWidth < 0 {
+			interiorPoint = geom.Coord{shell[0], shell[1]}
+			maxWidth = 0
+		}
+
+		// Choose a scan line between the vertices nearest to the middle of
+		// the polygon, so that it does not pass through any vertex.
+		minY, maxY := math.Inf(1), math.Inf(-1)
+		for i := 1; i < len(shell); i += 2 {
+			minY, maxY = min(minY, shell[i]), max(maxY, shell[i])
+		}
+		centerY := (minY + maxY) / 2
+		loY, hiY := minY, maxY
+		for _, ring := range rings {
+			for i := 1; i < len(ring); i += 2 {
+				if y := ring[i]; y <= centerY {
+					loY = max(loY, y)
+				} else {
+					hiY = min(hiY, y)
+				}
+			}
+		}
+		scanY := (loY + hiY) / 2
+
+		var crossings []float64
+		for _, ring := range rings {
+			for i := 2; i < len(ring); i += 2 {
+				x0, y0, x1, y1 := ring[i-2], ring[i-1], ring[i], ring[i+1]
+				if (y0 > scanY) != (y1 > scanY) {
+					crossings = append(crossings, x0+(scanY-y0)*(x1-x0)/(y1-y0))
+				}
+			}
+		}
+		sort.Float64s(crossings)
+		for i := 1; i < len(crossings); i += 2 {
+			if width := crossings[i] - crossings[i-1]; width > maxWidth {
+				interiorPoint = geom.Coord{(crossings[i-1] + crossings[i]) / 2, scanY}
+				maxWidth = width
+			}
+		}
+	}
+	return interiorPoint
+}
+
+// lineInteriorPoint returns the interior vertex of the lines of c that is
+// nearest to their centroid, or the nearest endpoint if there are no
+// interior vertices.
+func (c *interiorPointComponents) lineInteriorPoint() geom.Coord {
+	var sumX, sumY, sumLength float64
+	var vertices []float64
+	for _, line := range c.lines {
+		for i := 2; i < len(line); i += 2 {
+			length := math.Hypot(line[i]-line[i-2], line[i+1]-line[i-1])
+			sumX += length * (line[i-2] + line[i]) / 2
+			sumY += length * (line[i-1] + line[i+1]) / 2
+			sumLength += length
+		}
+		vertices = append(vertices, line...)
+	}
+	// Lines of zero length are treated as points.
+	if sumLength == 0 {
+		return nearestToCentroid(vertices)
+	}
+	centroid := geom.Coord{sumX / sumLength, sumY / sumLength}
+
+	var interiorVertices []float64
+	for _, line := range c.lines {
+		if len(line) > 4 {
+			interiorVertices = append(interiorVertices, line[2:len(line)-2]...)
+		}
+	}
+	if len(interiorVertices) != 0 {
+		return nearestTo(centroid, interiorVertices)
+	}
+	var endpoints []float64
+	for _, line := range c.lines {
+		endpoints = append(endpoints, line[:2]...)
+		endpoints = append(endpoints, line[len(line)-2:]...)
+	}
+	return nearestTo(centroid, endpoints)
+}
+
+// pointInteriorPoint returns the point of c that is nearest to the centroid
+// of the points of c.
+func (c *interiorPointComponents) pointInteriorPoint() geom.Coord {
+	return nearestToCentroid(c.points)
+}
+
+// nearestToCentroid returns the first of points, which have layout geom.XY,
+// that is nearest to their centroid.
+func nearestToCentroid(points []float64) geom.Coord {
+	var sumX, sumY float64
+	for i := 0; i < len(points); i += 2 {
+		sumX += points[i]
+		sumY += points[i+1]
+	}
+	n := float64(len(points) / 2)
+	return nearestTo(geom.Coord{sumX / n, sumY / n}, points)
+}
+
+// nearestTo returns the first of the points of candidates, which have layout
+// geom.XY, that is nearest to c.
+func nearestTo(c geom.Coord, candidates []float64) geom.Coord {
+	var nearest geom.Coord
+	minDistance := math.Inf(1)
+	for i := 0; i < len(candidates); i += 2 {
+		if distance := math.Hypot(candidates[i]-c[0], candidates[i+1]-c[1]); distance < minDistance {
+			nearest = geom.Coord{candidates[i], candidates[i+1]}
+			minDistance = distance
+		}
+	}
+	return nearest
+}
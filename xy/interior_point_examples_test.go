@@ -0,0 +1,22 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleInteriorPoint() {
+	// The centroid of this C-shaped polygon is outside it.
+	polygon := geom.NewPolygonFlat(geom.XY, []float64{
+		0, 0, 10, 0, 10, 2, 2, 2, 2, 8, 10, 8, 10, 10, 0, 10, 0, 0,
+	}, []int{18})
+	interiorPoint := xy.InteriorPoint(polygon)
+	fmt.Println(interiorPoint)
+	contains, _ := xy.Contains(polygon, geom.NewPointFlat(geom.XY, interiorPoint))
+	fmt.Println(contains)
+	// Output:
+	// [1 5]
+	// true
+}
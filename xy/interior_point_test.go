@@ -0,0 +1,103 @@
+package xy_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestInteriorPoint(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		g        geom.T
+		expected geom.Coord
+	}{
+		{
+			name: "empty_point",
+			g:    geom.NewPointEmpty(geom.XY),
+		},
+		{
+			name: "empty_geometry_collection",
+			g:    geom.NewGeometryCollection(),
+		},
+		{
+			name:     "point",
+			g:        geom.NewPointFlat(geom.XYZ, []float64{1, 2, 3}),
+			expected: geom.Coord{1, 2},
+		},
+		{
+			name:     "multi_point",
+			g:        geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 10, 0, 4, 1, 6, -2}),
+			expected: geom.Coord{4, 1},
+		},
+		{
+			name:     "line_string",
+			g:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 0, 2, 0, 10, 0}),
+			expected: geom.Coord{2, 0},
+		},
+		{
+			name:     "segment",
+			g:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			expected: geom.Coord{0, 0},
+		},
+		{
+			name: "multi_line_string_of_segments",
+			g: geom.NewMultiLineStringFlat(geom.XY, []float64{
+				0, 0, 1, 0,
+				9, 0, 10, 0,
+				4, 1, 6, 1,
+			}, []int{4, 8, 12}),
+			expected: geom.Coord{4, 1},
+		},
+		{
+			name:     "zero_length_line_string",
+			g:        geom.NewLineStringFlat(geom.XY, []float64{1, 1, 1, 1}),
+			expected: geom.Coord{1, 1},
+		},
+		{
+			name:     "square",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 4, 0, 4, 4, 0, 4, 0, 0}, []int{10}),
+			expected: geom.Coord{2, 2},
+		},
+		{
+			name: "c_shape",
+			g: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 10, 0, 10, 2, 2, 2, 2, 8, 10, 8, 10, 10, 0, 10, 0, 0,
+			}, []int{18}),
+			expected: geom.Coord{1, 5},
+		},
+		{
+			name: "square_with_hole",
+			g: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+				2, 2, 2, 8, 9, 8, 9, 2, 2, 2,
+			}, []int{10, 20}),
+			expected: geom.Coord{1, 5},
+		},
+		{
+			name: "multi_polygon",
+			g: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 1, 0, 1, 1, 0, 1, 0, 0,
+				5, 0, 11, 0, 11, 6, 5, 6, 5, 0,
+			}, [][]int{{10}, {20}}),
+			expected: geom.Coord{8, 3},
+		},
+		{
+			name: "geometry_collection",
+			g: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{100, 100}),
+				geom.NewLineStringFlat(geom.XY, []float64{0, 0, 50, 50, 100, 0}),
+				geom.NewPolygonFlat(geom.XY, []float64{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}, []int{10}),
+			),
+			expected: geom.Coord{1, 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := xy.InteriorPoint(tc.g); !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package planargraph
+
+import "math"
+
+// A snapper snaps computed points to nearby existing points so that
+// intersection points computed from different segments coincide exactly.
+type snapper struct {
+	tolerance float64
+	cells     map[[2]int64][][2]float64
+}
+
+func newSnapper(tolerance float64) *snapper {
+	return &snapper{
+		tolerance: tolerance,
+		cells:     make(map[[2]int64][][2]float64),
+	}
+}
+
+func (s *snapper) cell(x, y float64) [2]int64 {
+	return [2]int64{int64(math.Floor(x / s.tolerance)), int64(math.Floor(y / s.tolerance))}
+}
+
+// snap returns the closest existing point within the tolerance of (x, y), or
+// adds and returns (x, y) if there is no such point.
+func (s *snapper) snap(x, y float64) (float64, float64) {
+	cell := s.cell(x, y)
+	best, bestDistance := [2]float64{x, y}, math.Inf(1)
+	for i := cell[0] - 1; i <= cell[0]+1; i++ {
+		for j := cell[1] - 1; j <= cell[1]+1; j++ {
+			for _, p := range s.cells[[2]int64{i, j}] {
+				if d := math.Hypot(p[0]-x, p[1]-y); d <= s.tolerance && d < bestDistance {
+					best, bestDistance = p, d
+				}
+			}
+		}
+	}
+	if math.IsInf(bestDistance, 1) {
+		s.cells[cell] = append(s.cells[cell], best)
+	}
+	return best[0], best[1]
+}
+
+// A bucketIndex indexes edges by the interval that they span in one
+// dimension.
+type bucketIndex struct {
+	minValue float64
+	width    float64
+	buckets  [][]*Edge
+}
+
+func newBucketIndex(edges []*Edge, interval func(*Edge) (float64, float64)) *bucketIndex {
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for _, e := range edges {
+		lo, hi := interval(e)
+		minValue, maxValue = min(minValue, lo), max(maxValue, hi)
+	}
+	n := max(1, int(math.Sqrt(float64(len(edges)))))
+	b := &bucketIndex{
+		minValue: minValue,
+		width:    (maxValue - minValue) / float64(n),
+		buckets:  make([][]*Edge, n),
+	}
+	for _, e := range edges {
+		lo, hi := interval(e)
+		for i := b.bucket(lo); i <= b.bucket(hi); i++ {
+			b.buckets[i] = append(b.buckets[i], e)
+		}
+	}
+	return b
+}
+
+func (b *bucketIndex) bucket(value float64) int {
+	if !(b.width > 0) {
+		return 0
+	}
+	i := int((value - b.minValue) / b.width)
+	return max(0, min(i, len(b.buckets)-1))
+}
+
+// query returns all edges whose interval might contain value.
+func (b *bucketIndex) query(value float64) []*Edge {
+	return b.buckets[b.bucket(value)]
+}
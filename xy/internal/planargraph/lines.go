@@ -0,0 +1,49 @@
+package planargraph
+
+// Lines merges edges into lines. Lines are only broken at nodes where the
+// number of edges is not two, so each line meets the other lines only at its
+// endpoints. Closed loops of edges become closed lines.
+func Lines(edges []*Edge) [][]float64 {
+	incident := make(map[*Node][]*Edge)
+	for _, e := range edges {
+		incident[e.From] = append(incident[e.From], e)
+		incident[e.To] = append(incident[e.To], e)
+	}
+	used := make(map[*Edge]bool, len(edges))
+	walk := func(n *Node, e *Edge) []float64 {
+		line := []float64{n.X, n.Y}
+		for e != nil {
+			used[e] = true
+			n = e.Other(n)
+			line = append(line, n.X, n.Y)
+			if len(incident[n]) != 2 {
+				break
+			}
+			var next *Edge
+			for _, candidate := range incident[n] {
+				if !used[candidate] {
+					next = candidate
+				}
+			}
+			e = next
+		}
+		return line
+	}
+
+	var lines [][]float64
+	for _, e := range edges {
+		switch {
+		case used[e]:
+		case len(incident[e.From]) != 2:
+			lines = append(lines, walk(e.From, e))
+		case len(incident[e.To]) != 2:
+			lines = append(lines, walk(e.To, e))
+		}
+	}
+	for _, e := range edges {
+		if !used[e] {
+			lines = append(lines, walk(e.From, e))
+		}
+	}
+	return lines
+}
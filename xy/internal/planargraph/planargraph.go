@@ -0,0 +1,458 @@
+// Package planargraph builds fully noded planar graphs from the linework of
+// one or more geometries, called operands, and labels each edge with the
+// location of its sides relative to each operand.
+//
+// Areas are interpreted with the even-odd rule: a point is inside an
+// operand's area if a ray from the point crosses the operand's rings an odd
+// number of times. For valid polygons this is the same as the usual
+// interpretation, and for invalid polygons it gives a consistent and useful
+// result.
+//
+// Only x and y ordinates are used.
+package planargraph
+
+import (
+	"math"
+	"sort"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal"
+	"github.com/twpayne/go-geom/xy/lineintersection"
+	"github.com/twpayne/go-geom/xy/lineintersector"
+)
+
+// maxNodingIterations is the maximum number of noding passes. Each pass
+// after the first only finds intersections introduced by rounding.
+const maxNodingIterations = 5
+
+// snapTolerance is the tolerance, relative to the magnitude of the input
+// coordinates, within which computed intersection points are snapped to
+// existing nodes.
+const snapTolerance = 1e-12
+
+// A Node is a point where edges meet, or an isolated point.
+type Node struct {
+	X, Y float64
+	// Edges are the edges incident to the node.
+	Edges []*Edge
+	// PointCount is the number of points of each operand at the node.
+	PointCount []int
+	// LineEndCount is the number of line endpoints of each operand at the
+	// node.
+	LineEndCount []int
+}
+
+// Coord returns the coordinate of n.
+func (n *Node) Coord() geom.Coord {
+	return geom.Coord{n.X, n.Y}
+}
+
+// An Edge is a straight line between two nodes that does not intersect any
+// other edge except at its endpoints.
+type Edge struct {
+	From, To *Node
+	// RingCount is the number of ring segments of each operand that cover
+	// the edge.
+	RingCount []int
+	// LineCount is the number of line segments of each operand that cover
+	// the edge.
+	LineCount []int
+	// Left and Right are true for each operand whose area contains the
+	// region immediately to the left and right of the edge respectively.
+	Left, Right []bool
+}
+
+// Other returns the node at the other end of e from n.
+func (e *Edge) Other(n *Node) *Node {
+	if e.From == n {
+		return e.To
+	}
+	return e.From
+}
+
+// Midpoint returns the midpoint of e.
+func (e *Edge) Midpoint() (float64, float64) {
+	return (e.From.X + e.To.X) / 2, (e.From.Y + e.To.Y) / 2
+}
+
+// A Graph is a fully noded planar graph.
+type Graph struct {
+	NumOperands int
+	Nodes       []*Node
+	Edges       []*Edge
+	yIndex      *bucketIndex
+	xIndex      *bucketIndex
+}
+
+type segment struct {
+	x0, y0, x1, y1 float64
+	operand        int
+	ring           bool
+}
+
+type point struct {
+	x, y    float64
+	operand int
+}
+
+// A Builder accumulates linework and builds a Graph.
+type Builder struct {
+	numOperands int
+	segments    []segment
+	points      []point
+	lineEnds    []point
+}
+
+// NewBuilder returns a new Builder for numOperands operands.
+func NewBuilder(numOperands int) *Builder {
+	return &Builder{numOperands: numOperands}
+}
+
+// AddRing adds the ring with xy coordinates ring to operand's area.
+func (b *Builder) AddRing(operand int, ring []float64) {
+	b.addSegments(operand, ring, true)
+	if n := len(ring); n >= 4 && (ring[0] != ring[n-2] || ring[1] != ring[n-1]) {
+		b.addSegment(operand, ring[n-2], ring[n-1], ring[0], ring[1], true)
+	}
+}
+
+// AddLine adds the line with xy coordinates line to operand.
+func (b *Builder) AddLine(operand int, line []float64) {
+	n := len(line)
+	if n < 4 {
+		return
+	}
+	b.addSegments(operand, line, false)
+	b.lineEnds = append(b.lineEnds,
+		point{x: line[0], y: line[1], operand: operand},
+		point{x: line[n-2], y: line[n-1], operand: operand},
+	)
+}
+
+// AddPoint adds the point (x, y) to operand.
+func (b *Builder) AddPoint(operand int, x, y float64) {
+	b.points = append(b.points, point{x: x, y: y, operand: operand})
+}
+
+func (b *Builder) addSegments(operand int, coords []float64, ring bool) {
+	for i := 2; i+1 < len(coords); i += 2 {
+		b.addSegment(operand, coords[i-2], coords[i-1], coords[i], coords[i+1], ring)
+	}
+}
+
+func (b *Builder) addSegment(operand int, x0, y0, x1, y1 float64, ring bool) {
+	if x0 == x1 && y0 == y1 {
+		return
+	}
+	b.segments = append(b.segments, segment{x0: x0, y0: y0, x1: x1, y1: y1, operand: operand, ring: ring})
+}
+
+// Build nodes the linework and returns the labeled Graph.
+func (b *Builder) Build() *Graph {
+	magnitude := 1.0
+	for _, s := range b.segments {
+		magnitude = max(magnitude, math.Abs(s.x0), math.Abs(s.y0), math.Abs(s.x1), math.Abs(s.y1))
+	}
+	for _, p := range b.points {
+		magnitude = max(magnitude, math.Abs(p.x), math.Abs(p.y))
+	}
+	// Snap all input vertices so that vertices that differ only by rounding
+	// become the same node.
+	snapper := newSnapper(magnitude * snapTolerance)
+	segments := make([]segment, 0, len(b.segments))
+	for _, s := range b.segments {
+		s.x0, s.y0 = snapper.snap(s.x0, s.y0)
+		s.x1, s.y1 = snapper.snap(s.x1, s.y1)
+		if s.x0 != s.x1 || s.y0 != s.y1 {
+			segments = append(segments, s)
+		}
+	}
+	points := make([]point, len(b.points))
+	for i, p := range b.points {
+		p.x, p.y = snapper.snap(p.x, p.y)
+		points[i] = p
+	}
+
+	for range maxNodingIterations {
+		var changed bool
+		segments, changed = nodeSegments(segments, points, snapper)
+		if !changed {
+			break
+		}
+	}
+
+	g := &Graph{NumOperands: b.numOperands}
+	nodes := make(map[[2]float64]*Node)
+	getNode := func(x, y float64) *Node {
+		key := [2]float64{x, y}
+		if n, ok := nodes[key]; ok {
+			return n
+		}
+		n := &Node{
+			X:            x,
+			Y:            y,
+			PointCount:   make([]int, b.numOperands),
+			LineEndCount: make([]int, b.numOperands),
+		}
+		nodes[key] = n
+		g.Nodes = append(g.Nodes, n)
+		return n
+	}
+	type edgeKey struct{ from, to *Node }
+	edges := make(map[edgeKey]*Edge)
+	for _, s := range segments {
+		from, to := getNode(s.x0, s.y0), getNode(s.x1, s.y1)
+		if from == to {
+			continue
+		}
+		if lessXY(to.X, to.Y, from.X, from.Y) {
+			from, to = to, from
+		}
+		e, ok := edges[edgeKey{from, to}]
+		if !ok {
+			e = &Edge{
+				From:      from,
+				To:        to,
+				RingCount: make([]int, b.numOperands),
+				LineCount: make([]int, b.numOperands),
+				Left:      make([]bool, b.numOperands),
+				Right:     make([]bool, b.numOperands),
+			}
+			edges[edgeKey{from, to}] = e
+			g.Edges = append(g.Edges, e)
+			from.Edges = append(from.Edges, e)
+			to.Edges = append(to.Edges, e)
+		}
+		if s.ring {
+			e.RingCount[s.operand]++
+		} else {
+			e.LineCount[s.operand]++
+		}
+	}
+	for _, p := range points {
+		getNode(p.x, p.y).PointCount[p.operand]++
+	}
+	for _, p := range b.lineEnds {
+		getNode(snapper.snap(p.x, p.y)).LineEndCount[p.operand]++
+	}
+
+	g.label()
+	return g
+}
+
+// nodeSegments splits segments at all their mutual intersections and at
+// points that lie on them. It returns the split segments and whether any
+// segment was split.
+func nodeSegments(segments []segment, points []point, snapper *snapper) ([]segment, bool) {
+	tolerance := snapper.tolerance
+	boxes := make([]internal.Box, len(segments), len(segments)+len(points))
+	for i, s := range segments {
+		box := internal.SegmentBox(s.x0, s.y0, s.x1, s.y1)
+		boxes[i] = internal.Box{
+			MinX: box.MinX - tolerance,
+			MinY: box.MinY - tolerance,
+			MaxX: box.MaxX + tolerance,
+			MaxY: box.MaxY + tolerance,
+		}
+	}
+	for _, p := range points {
+		boxes = append(boxes, internal.Box{MinX: p.x, MinY: p.y, MaxX: p.x, MaxY: p.y})
+	}
+
+	splits := make([][][2]float64, len(segments))
+	addSplit := func(i int, c geom.Coord) {
+		x, y := snapper.snap(c[0], c[1])
+		s := segments[i]
+		if (x == s.x0 && y == s.y0) || (x == s.x1 && y == s.y1) {
+			return
+		}
+		splits[i] = append(splits[i], [2]float64{x, y})
+	}
+
+	strategy := lineintersector.RobustLineIntersector{}
+	internal.ForEachOverlappingPair(boxes, func(i, j int) bool {
+		switch {
+		case i >= len(segments):
+			return true
+		case j >= len(segments):
+			p := points[j-len(segments)]
+			if segments[i].near(p.x, p.y, tolerance) {
+				addSplit(i, geom.Coord{p.x, p.y})
+			}
+			return true
+		}
+		si, sj := segments[i], segments[j]
+		result := lineintersector.LineIntersectsLine(strategy,
+			geom.Coord{si.x0, si.y0}, geom.Coord{si.x1, si.y1},
+			geom.Coord{sj.x0, sj.y0}, geom.Coord{sj.x1, sj.y1})
+		switch result.Type() {
+		case lineintersection.PointIntersection:
+			c := result.Intersection()[0]
+			addSplit(i, c)
+			addSplit(j, c)
+		case lineintersection.CollinearIntersection:
+			for _, c := range result.Intersection() {
+				addSplit(i, c)
+				addSplit(j, c)
+			}
+		}
+		// Split segments at the endpoints of other segments that are within
+		// the tolerance of them, so that nearly collinear segments share
+		// nodes and edges rather than leaving slivers that are too thin to
+		// label reliably.
+		for _, c := range [][2]float64{{sj.x0, sj.y0}, {sj.x1, sj.y1}} {
+			if si.near(c[0], c[1], tolerance) {
+				addSplit(i, geom.Coord{c[0], c[1]})
+			}
+		}
+		for _, c := range [][2]float64{{si.x0, si.y0}, {si.x1, si.y1}} {
+			if sj.near(c[0], c[1], tolerance) {
+				addSplit(j, geom.Coord{c[0], c[1]})
+			}
+		}
+		return true
+	})
+
+	changed := false
+	result := make([]segment, 0, len(segments))
+	for i, s := range segments {
+		if len(splits[i]) == 0 {
+			result = append(result, s)
+			continue
+		}
+		changed = true
+		dx, dy := s.x1-s.x0, s.y1-s.y0
+		sort.Slice(splits[i], func(a, b int) bool {
+			pa, pb := splits[i][a], splits[i][b]
+			return (pa[0]-s.x0)*dx+(pa[1]-s.y0)*dy < (pb[0]-s.x0)*dx+(pb[1]-s.y0)*dy
+		})
+		x0, y0 := s.x0, s.y0
+		for _, c := range splits[i] {
+			if c[0] == x0 && c[1] == y0 {
+				continue
+			}
+			result = append(result, segment{x0: x0, y0: y0, x1: c[0], y1: c[1], operand: s.operand, ring: s.ring})
+			x0, y0 = c[0], c[1]
+		}
+		if x0 != s.x1 || y0 != s.y1 {
+			result = append(result, segment{x0: x0, y0: y0, x1: s.x1, y1: s.y1, operand: s.operand, ring: s.ring})
+		}
+	}
+	return result, changed
+}
+
+// near returns true if the point (x, y) is within tolerance of s.
+func (s segment) near(x, y, tolerance float64) bool {
+	dx, dy := s.x1-s.x0, s.y1-s.y0
+	t := ((x-s.x0)*dx + (y-s.y0)*dy) / (dx*dx + dy*dy)
+	t = max(0, min(t, 1))
+	return math.Hypot(s.x0+t*dx-x, s.y0+t*dy-y) <= tolerance
+}
+
+// label computes the Left and Right labels of every edge.
+func (g *Graph) label() {
+	g.yIndex = newBucketIndex(g.Edges, func(e *Edge) (float64, float64) {
+		return min(e.From.Y, e.To.Y), max(e.From.Y, e.To.Y)
+	})
+	g.xIndex = newBucketIndex(g.Edges, func(e *Edge) (float64, float64) {
+		return min(e.From.X, e.To.X), max(e.From.X, e.To.X)
+	})
+	for _, e := range g.Edges {
+		dx, dy := e.To.X-e.From.X, e.To.Y-e.From.Y
+		mx, my := e.Midpoint()
+		// Cast a ray from the midpoint of e away from e, either horizontally
+		// or vertically, and count the rings crossed. This gives the parity
+		// of the side of e that the ray leaves from. The parity of the other
+		// side also depends on the number of rings that cover e.
+		var counts []int
+		var leftIsPositive bool
+		if math.Abs(dy) >= math.Abs(dx) {
+			counts = g.crossingsX(mx, my, e)
+			leftIsPositive = dy < 0
+		} else {
+			counts = g.crossingsY(mx, my, e)
+			leftIsPositive = dx > 0
+		}
+		for op := range g.NumOperands {
+			positive := counts[op]%2 == 1
+			negative := positive != (e.RingCount[op]%2 == 1)
+			if leftIsPositive {
+				e.Left[op], e.Right[op] = positive, negative
+			} else {
+				e.Left[op], e.Right[op] = negative, positive
+			}
+		}
+	}
+}
+
+// Inside returns, for each operand, whether the point (x, y) is inside the
+// operand's area. The point must not lie on any edge.
+func (g *Graph) Inside(x, y float64) []bool {
+	counts := g.crossingsX(x, y, nil)
+	inside := make([]bool, g.NumOperands)
+	for op, count := range counts {
+		inside[op] = count%2 == 1
+	}
+	return inside
+}
+
+// crossingsX returns the number of ring crossings of each operand along the
+// ray from (x, y) in the positive x direction, ignoring exclude.
+func (g *Graph) crossingsX(x, y float64, exclude *Edge) []int {
+	counts := make([]int, g.NumOperands)
+	for _, e := range g.yIndex.query(y) {
+		if e == exclude || !hasRings(e) {
+			continue
+		}
+		lower, upper := e.From, e.To
+		if lower.Y > upper.Y {
+			lower, upper = upper, lower
+		}
+		if !(lower.Y <= y && y < upper.Y) {
+			continue
+		}
+		if (upper.X-lower.X)*(y-lower.Y)-(upper.Y-lower.Y)*(x-lower.X) > 0 {
+			for op, count := range e.RingCount {
+				counts[op] += count
+			}
+		}
+	}
+	return counts
+}
+
+// crossingsY returns the number of ring crossings of each operand along the
+// ray from (x, y) in the positive y direction, ignoring exclude.
+func (g *Graph) crossingsY(x, y float64, exclude *Edge) []int {
+	counts := make([]int, g.NumOperands)
+	for _, e := range g.xIndex.query(x) {
+		if e == exclude || !hasRings(e) {
+			continue
+		}
+		left, right := e.From, e.To
+		if left.X > right.X {
+			left, right = right, left
+		}
+		if !(left.X <= x && x < right.X) {
+			continue
+		}
+		if (right.X-left.X)*(y-left.Y)-(right.Y-left.Y)*(x-left.X) < 0 {
+			for op, count := range e.RingCount {
+				counts[op] += count
+			}
+		}
+	}
+	return counts
+}
+
+func hasRings(e *Edge) bool {
+	for _, count := range e.RingCount {
+		if count != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func lessXY(x0, y0, x1, y1 float64) bool {
+	return x0 < x1 || (x0 == x1 && y0 < y1)
+}
@@ -0,0 +1,139 @@
+package planargraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAreaRings(t *testing.T) {
+	square1 := []float64{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}
+	square2 := []float64{1, 1, 3, 1, 3, 3, 1, 3, 1, 1}
+	or := func(inside []bool) bool { return inside[0] || inside[1] }
+	and := func(inside []bool) bool { return inside[0] && inside[1] }
+	first := func(inside []bool) bool { return inside[0] }
+	for _, tc := range []struct {
+		name     string
+		rings    [][][]float64
+		inArea   func([]bool) bool
+		expected [][][]float64
+	}{
+		{
+			name:   "union",
+			rings:  [][][]float64{{square1}, {square2}},
+			inArea: or,
+			expected: [][][]float64{
+				{{0, 0, 2, 0, 2, 1, 3, 1, 3, 3, 1, 3, 1, 2, 0, 2, 0, 0}},
+			},
+		},
+		{
+			name:   "intersection",
+			rings:  [][][]float64{{square1}, {square2}},
+			inArea: and,
+			expected: [][][]float64{
+				{{1, 1, 2, 1, 2, 2, 1, 2, 1, 1}},
+			},
+		},
+		{
+			name:   "bow_tie",
+			rings:  [][][]float64{{{0, 0, 2, 2, 2, 0, 0, 2, 0, 0}}},
+			inArea: first,
+			expected: [][][]float64{
+				{{0, 0, 1, 1, 0, 2, 0, 0}},
+				{{1, 1, 2, 0, 2, 2, 1, 1}},
+			},
+		},
+		{
+			name: "hole_touching_shell",
+			rings: [][][]float64{{
+				{0, 0, 4, 0, 4, 4, 0, 4, 0, 0},
+				{2, 0, 1, 1, 3, 1, 2, 0},
+			}},
+			inArea: first,
+			expected: [][][]float64{
+				{
+					{0, 0, 2, 0, 4, 0, 4, 4, 0, 4, 0, 0},
+					{1, 1, 3, 1, 2, 0, 1, 1},
+				},
+			},
+		},
+		{
+			name: "squares_touching_at_point",
+			rings: [][][]float64{
+				{{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}},
+				{{1, 1, 2, 1, 2, 2, 1, 2, 1, 1}},
+			},
+			inArea: or,
+			expected: [][][]float64{
+				{{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}},
+				{{1, 1, 2, 1, 2, 2, 1, 2, 1, 1}},
+			},
+		},
+		{
+			name: "hole",
+			rings: [][][]float64{
+				{{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}},
+				{{2, 2, 4, 2, 4, 4, 2, 4, 2, 2}},
+			},
+			inArea: func(inside []bool) bool { return inside[0] && !inside[1] },
+			expected: [][][]float64{
+				{
+					{0, 0, 10, 0, 10, 10, 0, 10, 0, 0},
+					{2, 2, 2, 4, 4, 4, 4, 2, 2, 2},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewBuilder(len(tc.rings))
+			for operand, rings := range tc.rings {
+				for _, ring := range rings {
+					b.AddRing(operand, ring)
+				}
+			}
+			g := b.Build()
+			got := Polygons(g.AreaRings(tc.inArea))
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildLinesAndPoints(t *testing.T) {
+	b := NewBuilder(2)
+	b.AddLine(0, []float64{0, 0, 2, 2})
+	b.AddLine(1, []float64{0, 2, 2, 0})
+	b.AddPoint(1, 1, 1)
+	b.AddPoint(1, 5, 5)
+	g := b.Build()
+	if len(g.Nodes) != 6 {
+		t.Errorf("expected 6 nodes but got %d", len(g.Nodes))
+	}
+	if len(g.Edges) != 4 {
+		t.Errorf("expected 4 edges but got %d", len(g.Edges))
+	}
+	for _, n := range g.Nodes {
+		if n.X == 1 && n.Y == 1 {
+			if len(n.Edges) != 4 || n.PointCount[1] != 1 {
+				t.Errorf("unexpected node %+v", n)
+			}
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	b := NewBuilder(1)
+	b.AddLine(0, []float64{0, 0, 1, 0, 2, 0})
+	b.AddLine(0, []float64{1, 0, 1, 1})
+	b.AddLine(0, []float64{5, 5, 6, 5, 6, 6, 5, 5})
+	got := Lines(b.Build().Edges)
+	expected := [][]float64{
+		{0, 0, 1, 0},
+		{1, 0, 2, 0},
+		{1, 0, 1, 1},
+		{5, 5, 6, 5, 6, 6, 5, 5},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v but got %v", expected, got)
+	}
+}
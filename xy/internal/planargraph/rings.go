@@ -0,0 +1,212 @@
+package planargraph
+
+import (
+	"math"
+	"sort"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal/raycrossing"
+	"github.com/twpayne/go-geom/xy/location"
+)
+
+// A halfEdge is an edge traversed in one direction.
+type halfEdge struct {
+	edge     *Edge
+	from, to *Node
+	angle    float64
+	used     bool
+}
+
+// AreaRings returns the boundary rings of the area formed by the regions
+// for which inArea returns true. inArea is called with, for each operand,
+// whether the region is inside the operand's area. Each ring is closed and
+// simple, and has the area on its left, so shells are counter-clockwise and
+// holes are clockwise.
+func (g *Graph) AreaRings(inArea func(inside []bool) bool) [][]float64 {
+	out := make(map[*Node][]*halfEdge)
+	var halfEdges []*halfEdge
+	for _, e := range g.Edges {
+		left, right := inArea(e.Left), inArea(e.Right)
+		if left == right {
+			continue
+		}
+		from, to := e.From, e.To
+		if right {
+			from, to = to, from
+		}
+		h := &halfEdge{
+			edge:  e,
+			from:  from,
+			to:    to,
+			angle: math.Atan2(to.Y-from.Y, to.X-from.X),
+		}
+		out[from] = append(out[from], h)
+		halfEdges = append(halfEdges, h)
+	}
+
+	var rings [][]float64
+	for _, start := range halfEdges {
+		if start.used {
+			continue
+		}
+		var walk []*Node
+		for h := start; h != nil && !h.used; h = nextHalfEdge(out[h.to], h) {
+			h.used = true
+			walk = append(walk, h.from)
+		}
+		rings = append(rings, splitWalk(walk)...)
+	}
+	return rings
+}
+
+// nextHalfEdge returns the half edge in candidates that is the first
+// clockwise from the reverse of h. This is the next half edge around the
+// region to the left of h.
+func nextHalfEdge(candidates []*halfEdge, h *halfEdge) *halfEdge {
+	reverse := math.Atan2(h.from.Y-h.to.Y, h.from.X-h.to.X)
+	var next *halfEdge
+	bestTurn := math.Inf(1)
+	for _, candidate := range candidates {
+		turn := reverse - candidate.angle
+		for turn <= 0 {
+			turn += 2 * math.Pi
+		}
+		if turn < bestTurn {
+			next, bestTurn = candidate, turn
+		}
+	}
+	return next
+}
+
+// splitWalk splits the closed walk through nodes into simple rings at nodes
+// that it visits more than once.
+func splitWalk(walk []*Node) [][]float64 {
+	var rings [][]float64
+	var stack []*Node
+	position := make(map[*Node]int)
+	for _, n := range append(walk, walk[0]) {
+		if i, ok := position[n]; ok {
+			loop := append(stack[i:], n)
+			for _, m := range stack[i+1:] {
+				delete(position, m)
+			}
+			stack = stack[:i+1]
+			if len(loop) >= 4 {
+				rings = append(rings, ringCoords(loop))
+			}
+			continue
+		}
+		position[n] = len(stack)
+		stack = append(stack, n)
+	}
+	return rings
+}
+
+// ringCoords returns the xy coordinates of the closed ring of nodes, starting
+// at the lowest node.
+func ringCoords(nodes []*Node) []float64 {
+	nodes = nodes[:len(nodes)-1]
+	first := 0
+	for i, n := range nodes {
+		if lessXY(n.X, n.Y, nodes[first].X, nodes[first].Y) {
+			first = i
+		}
+	}
+	coords := make([]float64, 0, 2*len(nodes)+2)
+	for i := range nodes {
+		n := nodes[(first+i)%len(nodes)]
+		coords = append(coords, n.X, n.Y)
+	}
+	return append(coords, nodes[first].X, nodes[first].Y)
+}
+
+// Polygons assembles rings, as returned by AreaRings, into polygons. Each
+// polygon is a counter-clockwise shell followed by the clockwise holes that
+// it contains. Polygons are ordered by their lowest point.
+func Polygons(rings [][]float64) [][][]float64 {
+	type shell struct {
+		ring []float64
+		area float64
+		box  [4]float64
+	}
+	var shells []*shell
+	var holes [][]float64
+	for _, ring := range rings {
+		if area := signedArea(ring); area > 0 {
+			shells = append(shells, &shell{ring: ring, area: area, box: ringBox(ring)})
+		} else if area < 0 {
+			holes = append(holes, ring)
+		}
+	}
+	sort.SliceStable(shells, func(i, j int) bool {
+		return lessXY(shells[i].ring[0], shells[i].ring[1], shells[j].ring[0], shells[j].ring[1])
+	})
+
+	polygons := make([][][]float64, len(shells))
+	for i, s := range shells {
+		polygons[i] = [][]float64{s.ring}
+	}
+	for _, hole := range holes {
+		box := ringBox(hole)
+		best := -1
+		for i, s := range shells {
+			if box[0] < s.box[0] || box[1] < s.box[1] || box[2] > s.box[2] || box[3] > s.box[3] {
+				continue
+			}
+			if best >= 0 && s.area >= shells[best].area {
+				continue
+			}
+			if ringInsideRing(hole, s.ring) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			polygons[best] = append(polygons[best], hole)
+		}
+	}
+	return polygons
+}
+
+// ringInsideRing returns true if inner, which does not cross outer, lies
+// inside outer.
+func ringInsideRing(inner, outer []float64) bool {
+	for i := 0; i+1 < len(inner); i += 2 {
+		switch raycrossing.LocatePointInRing(geom.XY, geom.Coord(inner[i:i+2]), outer) {
+		case location.Interior:
+			return true
+		case location.Exterior:
+			return false
+		}
+	}
+	// All vertices are on outer, so test the segment midpoints.
+	for i := 2; i+1 < len(inner); i += 2 {
+		c := geom.Coord{(inner[i-2] + inner[i]) / 2, (inner[i-1] + inner[i+1]) / 2}
+		switch raycrossing.LocatePointInRing(geom.XY, c, outer) {
+		case location.Interior:
+			return true
+		case location.Exterior:
+			return false
+		}
+	}
+	return false
+}
+
+// signedArea returns the signed area of ring, which is positive if ring is
+// counter-clockwise.
+func signedArea(ring []float64) float64 {
+	sum := 0.0
+	for i := 2; i+1 < len(ring); i += 2 {
+		sum += (ring[i-2] - ring[0]) * (ring[i+1] - ring[1])
+		sum -= (ring[i] - ring[0]) * (ring[i-1] - ring[1])
+	}
+	return sum / 2
+}
+
+func ringBox(ring []float64) [4]float64 {
+	box := [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for i := 0; i+1 < len(ring); i += 2 {
+		box[0], box[1] = min(box[0], ring[i]), min(box[1], ring[i+1])
+		box[2], box[3] = max(box[2], ring[i]), max(box[3], ring[i+1])
+	}
+	return box
+}
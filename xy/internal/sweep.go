@@ -0,0 +1,69 @@
+package internal
+
+import "sort"
+
+// Box is an axis-aligned two-dimensional bounding box.
+type Box struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// SegmentBox returns the bounding box of the segment from (x0, y0) to (x1, y1).
+func SegmentBox(x0, y0, x1, y1 float64) Box {
+	return Box{
+		MinX: min(x0, x1),
+		MinY: min(y0, y1),
+		MaxX: max(x0, x1),
+		MaxY: max(y0, y1),
+	}
+}
+
+// Overlaps returns true if b and other have at least one point in common.
+func (b Box) Overlaps(other Box) bool {
+	return b.MinX <= other.MaxX && other.MinX <= b.MaxX && b.MinY <= other.MaxY && other.MinY <= b.MaxY
+}
+
+// Union returns the smallest box that contains both b and other.
+func (b Box) Union(other Box) Box {
+	return Box{
+		MinX: min(b.MinX, other.MinX),
+		MinY: min(b.MinY, other.MinY),
+		MaxX: max(b.MaxX, other.MaxX),
+		MaxY: max(b.MaxY, other.MaxY),
+	}
+}
+
+// ForEachOverlappingPair calls f for every pair of indexes i < j in boxes
+// whose boxes overlap. A sweep line along the x axis is used so that pairs
+// whose x ranges are disjoint are never compared. If f returns false then
+// the iteration stops and ForEachOverlappingPair returns false.
+func ForEachOverlappingPair(boxes []Box, f func(i, j int) bool) bool {
+	order := make([]int, len(boxes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return boxes[order[a]].MinX < boxes[order[b]].MinX
+	})
+	for a, i := range order {
+		bi := boxes[i]
+		for _, j := range order[a+1:] {
+			bj := boxes[j]
+			if bj.MinX > bi.MaxX {
+				break
+			}
+			if bi.MinY > bj.MaxY || bj.MinY > bi.MaxY {
+				continue
+			}
+			var ok bool
+			if i < j {
+				ok = f(i, j)
+			} else {
+				ok = f(j, i)
+			}
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestForEachOverlappingPair(t *testing.T) {
+	boxes := []Box{
+		SegmentBox(0, 0, 2, 2),
+		SegmentBox(3, 3, 1, 1),
+		SegmentBox(5, 0, 6, 1),
+		SegmentBox(2, 5, 2, 6),
+		SegmentBox(6, 1, 7, 0),
+	}
+	var got [][2]int
+	ForEachOverlappingPair(boxes, func(i, j int) bool {
+		got = append(got, [2]int{i, j})
+		return true
+	})
+	sort.Slice(got, func(a, b int) bool {
+		if got[a][0] != got[b][0] {
+			return got[a][0] < got[b][0]
+		}
+		return got[a][1] < got[b][1]
+	})
+	expected := [][2]int{{0, 1}, {2, 4}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v but got %v", expected, got)
+	}
+
+	calls := 0
+	if ForEachOverlappingPair(boxes, func(i, j int) bool {
+		calls++
+		return false
+	}) {
+		t.Error("expected iteration to be stopped")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call but got %d", calls)
+	}
+}
+
+func TestBoxUnion(t *testing.T) {
+	got := SegmentBox(0, 0, 1, 1).Union(SegmentBox(3, -1, 2, 0))
+	expected := Box{MinX: 0, MinY: -1, MaxX: 3, MaxY: 1}
+	if got != expected {
+		t.Errorf("expected %v but got %v", expected, got)
+	}
+}
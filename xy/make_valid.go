@@ -0,0 +1,204 @@
+package xy
+
+import (
+	"errors"
+	"math"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal/planargraph"
+)
+
+// MakeValid returns a valid geometry that covers the same points as g, as
+// far as possible. If g is already valid then it is returned unchanged. If
+// g, or a geometry in g, is nil or of an unsupported type then MakeValid
+// returns a geom.ErrUnsupportedType error.
+//
+// Invalid coordinates and repeated points are removed. LineStrings that
+// collapse to a single point become Points. Rings are closed and rings that
+// collapse to fewer than three distinct points are removed. The remaining
+// rings of each Polygon are interpreted with the even-odd rule: a point is
+// inside the Polygon if it is inside an odd number of its rings. This splits
+// self-intersecting rings, such as bow ties, into separate Polygons, ignores
+// the orientation of rings, and assigns holes to the shells that contain
+// them. The Polygons of a MultiPolygon are then merged where they overlap.
+//
+// Polygonal results are a *geom.Polygon, a *geom.MultiPolygon if there is
+// more than one Polygon, or an empty *geom.Polygon if all rings collapse.
+// Only the x and y ordinates of polygonal results are computed, so they have
+// layout geom.XY.
+func MakeValid(g geom.T) (geom.T, error) {
+	var validationErr *ValidationError
+	if err := Validate(g); err == nil {
+		return g, nil
+	} else if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+	stride := g.Stride()
+	switch g := g.(type) {
+	case *geom.Point:
+		return geom.NewPointEmpty(g.Layout()).SetSRID(g.SRID()), nil
+	case *geom.MultiPoint:
+		return geom.NewMultiPointFlat(g.Layout(), finiteCoords(g.FlatCoords(), stride)).SetSRID(g.SRID()), nil
+	case *geom.LineString:
+		return makeValidLineString(g.Layout(), g.FlatCoords(), g.SRID()), nil
+	case *geom.MultiLineString:
+		lines := geom.NewMultiLineString(g.Layout()).SetSRID(g.SRID())
+		points := geom.NewMultiPoint(g.Layout()).SetSRID(g.SRID())
+		for i := range g.NumLineStrings() {
+			switch valid := makeValidLineString(g.Layout(), g.LineString(i).FlatCoords(), g.SRID()).(type) {
+			case *geom.LineString:
+				if !valid.Empty() {
+					_ = lines.Push(valid)
+				}
+			case *geom.Point:
+				_ = points.Push(valid)
+			}
+		}
+		if points.Empty() {
+			return lines, nil
+		}
+		if lines.Empty() {
+			return points, nil
+		}
+		return geom.NewGeometryCollection().MustPush(lines, points).SetSRID(g.SRID()), nil
+	case *geom.LinearRing:
+		flatCoords := cleanRing(g.FlatCoords(), stride)
+		if ring := geom.NewLinearRingFlat(g.Layout(), flatCoords); len(flatCoords) != 0 && Validate(ring) == nil {
+			return ring.SetSRID(g.SRID()), nil
+		}
+		return makeValidLineString(g.Layout(), g.FlatCoords(), g.SRID()), nil
+	case *geom.Polygon:
+		b := planargraph.NewBuilder(1)
+		addCleanRings(b, 0, g)
+		return makeValidArea(b, g.SRID()), nil
+	case *geom.MultiPolygon:
+		numPolygons := g.NumPolygons()
+		b := planargraph.NewBuilder(numPolygons)
+		for i := range numPolygons {
+			addCleanRings(b, i, g.Polygon(i))
+		}
+		return makeValidArea(b, g.SRID()), nil
+	case *geom.GeometryCollection:
+		result := geom.NewGeometryCollection().SetSRID(g.SRID())
+		for _, child := range g.Geoms() {
+			valid, err := MakeValid(child)
+			if err != nil {
+				return nil, err
+			}
+			if err := result.Push(valid); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	default:
+		return nil, geom.ErrUnsupportedType{Value: g}
+	}
+}
+
+// makeValidLineString returns a *geom.LineString of flatCoords with invalid
+// coordinates and repeated points removed, or a *geom.Point if only one
+// distinct point remains.
+func makeValidLineString(layout geom.Layout, flatCoords []float64, srid int) geom.T {
+	stride := layout.Stride()
+	flatCoords = removeRepeatedPoints(finiteCoords(flatCoords, stride), stride)
+	if len(flatCoords) == stride {
+		return geom.NewPointFlat(layout, flatCoords).SetSRID(srid)
+	}
+	return geom.NewLineStringFlat(layout, flatCoords).SetSRID(srid)
+}
+
+// addCleanRings adds the rings of p to operand of b, after removing invalid
+// coordinates and repeated points and closing them. Rings with fewer than
+// three distinct points are skipped.
+func addCleanRings(b *planargraph.Builder, operand int, p *geom.Polygon) {
+	for i := range p.NumLinearRings() {
+		if ring := cleanRing(p.LinearRing(i).FlatCoords(), p.Stride()); ring != nil {
+			b.AddRing(operand, xyCoords(ring, p.Stride()))
+		}
+	}
+}
+
+// cleanRing returns the closed ring of flatCoords without invalid
+// coordinates and repeated points, or nil if it has fewer than three
+// distinct points.
+func cleanRing(flatCoords []float64, stride int) []float64 {
+	ring := removeRepeatedPoints(finiteCoords(flatCoords, stride), stride)
+	n := len(ring)
+	if n > stride && ring[0] == ring[n-stride] && ring[1] == ring[n-stride+1] {
+		ring, n = ring[:n-stride], n-stride
+	}
+	if n < 3*stride {
+		return nil
+	}
+	return append(ring, ring[:stride]...)
+}
+
+func makeValidArea(b *planargraph.Builder, srid int) geom.T {
+	g := b.Build()
+	return polygonal(planargraph.Polygons(g.AreaRings(insideAny)), srid)
+}
+
+// insideAny returns true if any of inside is true.
+func insideAny(inside []bool) bool {
+	for _, in := range inside {
+		if in {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonal returns polygons as a *geom.Polygon if there is exactly one
+// polygon, a *geom.MultiPolygon if there are more, or an empty *geom.Polygon
+// if there are none.
+func polygonal(polygons [][][]float64, srid int) geom.T {
+	switch len(polygons) {
+	case 0:
+		return geom.NewPolygon(geom.XY).SetSRID(srid)
+	case 1:
+		return polygonFromRings(polygons[0]).SetSRID(srid)
+	default:
+		mp := geom.NewMultiPolygon(geom.XY).SetSRID(srid)
+		for _, rings := range polygons {
+			_ = mp.Push(polygonFromRings(rings))
+		}
+		return mp
+	}
+}
+
+func polygonFromRings(rings [][]float64) *geom.Polygon {
+	var flatCoords []float64
+	ends := make([]int, 0, len(rings))
+	for _, ring := range rings {
+		flatCoords = append(flatCoords, ring...)
+		ends = append(ends, len(flatCoords))
+	}
+	return geom.NewPolygonFlat(geom.XY, flatCoords, ends)
+}
+
+// finiteCoords returns the coordinates of flatCoords whose x and y ordinates
+// are finite.
+func finiteCoords(flatCoords []float64, stride int) []float64 {
+	result := make([]float64, 0, len(flatCoords))
+	for i := 0; i < len(flatCoords); i += stride {
+		x, y := flatCoords[i], flatCoords[i+1]
+		if math.IsNaN(x) || math.IsInf(x, 0) || math.IsNaN(y) || math.IsInf(y, 0) {
+			continue
+		}
+		result = append(result, flatCoords[i:i+stride]...)
+	}
+	return result
+}
+
+// removeRepeatedPoints returns flatCoords without coordinates whose x and y
+// ordinates are equal to those of the previous coordinate.
+func removeRepeatedPoints(flatCoords []float64, stride int) []float64 {
+	result := make([]float64, 0, len(flatCoords))
+	for i := 0; i < len(flatCoords); i += stride {
+		if n := len(result); n > 0 && result[n-stride] == flatCoords[i] && result[n-stride+1] == flatCoords[i+1] {
+			continue
+		}
+		result = append(result, flatCoords[i:i+stride]...)
+	}
+	return result
+}
@@ -0,0 +1,23 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleMakeValid() {
+	bowTie := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 10, 10, 0, 0, 10, 0, 0}, []int{10})
+	valid, err := xy.MakeValid(bowTie)
+	if err != nil {
+		panic(err)
+	}
+	multiPolygon := valid.(*geom.MultiPolygon)
+	for i := range multiPolygon.NumPolygons() {
+		fmt.Println(multiPolygon.Polygon(i).FlatCoords())
+	}
+	// Output:
+	// [0 0 5 5 0 10 0 0]
+	// [5 5 10 0 10 10 5 5]
+}
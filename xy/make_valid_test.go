@@ -0,0 +1,146 @@
+package xy_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestMakeValid(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		g        geom.T
+		expected geom.T
+	}{
+		{
+			name:     "valid",
+			g:        geom.NewPolygonFlat(geom.XYZ, []float64{0, 0, 1, 1, 0, 2, 1, 1, 3, 0, 0, 1}, []int{12}),
+			expected: geom.NewPolygonFlat(geom.XYZ, []float64{0, 0, 1, 1, 0, 2, 1, 1, 3, 0, 0, 1}, []int{12}),
+		},
+		{
+			name:     "invalid_point",
+			g:        geom.NewPointFlat(geom.XY, []float64{math.NaN(), 1}).SetSRID(4326),
+			expected: geom.NewPointEmpty(geom.XY).SetSRID(4326),
+		},
+		{
+			name:     "multipoint",
+			g:        geom.NewMultiPointFlat(geom.XY, []float64{0, 0, math.Inf(1), 1, 2, 2}),
+			expected: geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 2, 2}),
+		},
+		{
+			name:     "collapsed_linestring",
+			g:        geom.NewLineStringFlat(geom.XYM, []float64{1, 1, 0, 1, 1, 1}),
+			expected: geom.NewPointFlat(geom.XYM, []float64{1, 1, 0}),
+		},
+		{
+			name: "multilinestring",
+			g:    geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 1, 1, 1, 1, 2, 2, 2, 2}, []int{6, 10}),
+			expected: geom.NewGeometryCollection().MustPush(
+				geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 1, 1}, []int{4}),
+				geom.NewMultiPointFlat(geom.XY, []float64{2, 2}),
+			),
+		},
+		{
+			name:     "unclosed_linearring",
+			g:        geom.NewLinearRingFlat(geom.XY, []float64{0, 0, 1, 0, 1, 1}),
+			expected: geom.NewLinearRingFlat(geom.XY, []float64{0, 0, 1, 0, 1, 1, 0, 0}),
+		},
+		{
+			name:     "unclosed_polygon",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10}, []int{8}).SetSRID(3857),
+			expected: geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}).SetSRID(3857),
+		},
+		{
+			name: "bow_tie",
+			g:    geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 10, 10, 0, 0, 10, 0, 0}, []int{10}),
+			expected: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 5, 5, 0, 10, 0, 0,
+				5, 5, 10, 0, 10, 10, 5, 5,
+			}, [][]int{{8}, {16}}),
+		},
+		{
+			name: "repeated_and_collapsed_points",
+			g: geom.NewPolygonFlat(geom.XYZ, []float64{
+				0, 0, 0, 0, 0, 1, 10, 0, 2, 10, 10, 3, 10, 10, 4, 0, 10, 5, 0, 0, 6,
+				2, 2, 0, 3, 3, 0, 2, 2, 0,
+			}, []int{21, 30}),
+			expected: geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}),
+		},
+		{
+			name: "reversed_rings_and_hole_outside_shell",
+			g: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 0, 10, 10, 10, 10, 0, 0, 0,
+				2, 2, 4, 2, 4, 4, 2, 2,
+				20, 20, 22, 20, 22, 22, 20, 20,
+			}, []int{10, 18, 26}),
+			expected: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+				2, 2, 4, 4, 4, 2, 2, 2,
+				20, 20, 22, 20, 22, 22, 20, 20,
+			}, [][]int{{10, 18}, {26}}),
+		},
+		{
+			name: "overlapping_multipolygon",
+			g: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 2, 0, 2, 2, 0, 2, 0, 0,
+				1, 1, 3, 1, 3, 3, 1, 3, 1, 1,
+			}, [][]int{{10}, {20}}),
+			expected: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 2, 0, 2, 1, 3, 1, 3, 3, 1, 3, 1, 2, 0, 2, 0, 0,
+			}, []int{18}),
+		},
+		{
+			name: "nested_shells",
+			g: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+				2, 2, 4, 2, 4, 4, 2, 2,
+			}, [][]int{{10}, {18}}),
+			expected: geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}),
+		},
+		{
+			name:     "collapsed_polygon",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 1, 1, 0, 0}, []int{6}),
+			expected: geom.NewPolygon(geom.XY),
+		},
+		{
+			name: "geometrycollection",
+			g: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{1, 2}),
+				geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10}, []int{8}),
+			),
+			expected: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{1, 2}),
+				geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}),
+			),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := xy.MakeValid(tc.g)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v but got %v", tc.expected, got)
+			}
+			if err := xy.Validate(got); err != nil {
+				t.Errorf("expected a valid result but got %v", err)
+			}
+		})
+	}
+}
+
+func TestMakeValidUnsupportedType(t *testing.T) {
+	var errUnsupportedType geom.ErrUnsupportedType
+	for _, g := range []geom.T{
+		nil,
+		geom.NewGeometryCollection().MustPush(nil),
+	} {
+		if _, err := xy.MakeValid(g); !errors.As(err, &errUnsupportedType) {
+			t.Errorf("expected geom.ErrUnsupportedType, got %v", err)
+		}
+	}
+}
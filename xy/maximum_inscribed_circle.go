@@ -0,0 +1,163 @@
+package xy
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/twpayne/go-geom"
+)
+
+// MaximumInscribedCircle returns the center and radius of the largest circle
+// that fits inside g, which must be a *geom.Polygon or *geom.MultiPolygon.
+// The center is the pole of inaccessibility of g, the point inside g that is
+// furthest from its boundary, and is a better place for a label than the
+// centroid, which may be outside a concave polygon.
+//
+// The center is found by repeatedly subdividing square cells that cover g,
+// discarding cells that cannot contain a point further from the boundary
+// than the best found so far, until the radius is within tolerance of the
+// largest possible. If tolerance is not positive then one thousandth of the
+// larger of the width and height of g is used. It returns a nil center if g
+// is empty.
+func MaximumInscribedCircle(g geom.T, tolerance float64) (center geom.Coord, radius float64, err error) {
+	var rings [][]float64
+	switch g := g.(type) {
+	case *geom.Polygon:
+		rings = appendPolygonRings(rings, g)
+	case *geom.MultiPolygon:
+		for i := range g.NumPolygons() {
+			rings = appendPolygonRings(rings, g.Polygon(i))
+		}
+	default:
+		return nil, 0, geom.ErrUnsupportedType{Value: g}
+	}
+
+	bounds := geom.NewBounds(geom.XY)
+	for _, ring := range rings {
+		bounds.Extend(geom.NewLineStringFlat(geom.XY, ring))
+	}
+	if bounds.IsEmpty() {
+		return nil, 0, nil
+	}
+	minX, minY, maxX, maxY := bounds.Min(0), bounds.Min(1), bounds.Max(0), bounds.Max(1)
+	width, height := maxX-minX, maxY-minY
+	if tolerance <= 0 {
+		tolerance = max(width, height) / 1000
+	}
+	cellSize := min(width, height)
+	if cellSize == 0 {
+		return geom.Coord{rings[0][0], rings[0][1]}, 0, nil
+	}
+
+	newCell := func(x, y, halfSize float64) inscribedCircleCell {
+		distance := signedDistanceToRings(x, y, rings)
+		return inscribedCircleCell{
+			x:         x,
+			y:         y,
+			halfSize:  halfSize,
+			distance:  distance,
+			potential: distance + halfSize*math.Sqrt2,
+		}
+	}
+
+	// Start with the best of the centroid and the center of the bounds.
+	best := newCell(minX+width/2, minY+height/2, 0)
+	var centroid geom.Coord
+	if p, ok := g.(*geom.Polygon); ok {
+		centroid = PolygonsCentroid(p)
+	} else {
+		centroid = MultiPolygonCentroid(g.(*geom.MultiPolygon))
+	}
+	if len(centroid) >= 2 && !math.IsNaN(centroid[0]) && !math.IsNaN(centroid[1]) {
+		if cell := newCell(centroid[0], centroid[1], 0); cell.distance > best.distance {
+			best = cell
+		}
+	}
+
+	var queue inscribedCircleQueue
+	for x := minX; x < maxX; x += cellSize {
+		for y := minY; y < maxY; y += cellSize {
+			heap.Push(&queue, newCell(x+cellSize/2, y+cellSize/2, cellSize/2))
+		}
+	}
+	for queue.Len() > 0 {
+		cell := heap.Pop(&queue).(inscribedCircleCell)
+		if cell.distance > best.distance {
+			best = cell
+		}
+		if cell.potential-best.distance <= tolerance {
+			// The cells are in order of decreasing potential, so no other
+			// cell can contain a better point.
+			break
+		}
+		halfSize := cell.halfSize / 2
+		for _, d := range [][2]float64{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+			heap.Push(&queue, newCell(cell.x+d[0]*halfSize, cell.y+d[1]*halfSize, halfSize))
+		}
+	}
+	return geom.Coord{best.x, best.y}, max(best.distance, 0), nil
+}
+
+// appendPolygonRings appends the x and y ordinates of the rings of p to
+// rings.
+func appendPolygonRings(rings [][]float64, p *geom.Polygon) [][]float64 {
+	for i := range p.NumLinearRings() {
+		ring := p.LinearRing(i)
+		if ring.NumCoords() != 0 {
+			rings = append(rings, xyCoords(ring.FlatCoords(), ring.Stride()))
+		}
+	}
+	return rings
+}
+
+// signedDistanceToRings returns the distance from (x, y) to the nearest of
+// rings, which is positive if (x, y) is inside an odd number of them and
+// negative otherwise.
+func signedDistanceToRings(x, y float64, rings [][]float64) float64 {
+	inside := false
+	minDistance := math.Inf(1)
+	for _, ring := range rings {
+		for i := 2; i < len(ring); i += 2 {
+			x0, y0, x1, y1 := ring[i-2], ring[i-1], ring[i], ring[i+1]
+			if (y0 > y) != (y1 > y) && x < x0+(y-y0)*(x1-x0)/(y1-y0) {
+				inside = !inside
+			}
+			nx, ny := nearestOnSegment(x, y, x0, y0, x1, y1)
+			minDistance = min(minDistance, math.Hypot(nx-x, ny-y))
+		}
+	}
+	if !inside {
+		return -minDistance
+	}
+	return minDistance
+}
+
+// An inscribedCircleCell is a square cell with center (x, y) that is
+// searched for the center of the maximum inscribed circle. distance is the
+// signed distance from its center to the boundary and potential is the
+// largest distance from the boundary of any point in the cell.
+type inscribedCircleCell struct {
+	x, y      float64
+	halfSize  float64
+	distance  float64
+	potential float64
+}
+
+// An inscribedCircleQueue is a priority queue of cells with the largest
+// potential first.
+type inscribedCircleQueue []inscribedCircleCell
+
+func (q inscribedCircleQueue) Len() int { return len(q) }
+
+func (q inscribedCircleQueue) Less(i, j int) bool { return q[i].potential > q[j].potential }
+
+func (q inscribedCircleQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *inscribedCircleQueue) Push(x any) { *q = append(*q, x.(inscribedCircleCell)) }
+
+func (q *inscribedCircleQueue) Pop() any {
+	old := *q
+	cell := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return cell
+}
@@ -0,0 +1,22 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleMaximumInscribedCircle() {
+	// The centroid of this U-shaped polygon is outside it, but the pole of
+	// inaccessibility is a good place for a label.
+	polygon := geom.NewPolygonFlat(geom.XY, []float64{
+		0, 0, 10, 0, 10, 10, 8, 10, 8, 2, 2, 2, 2, 10, 0, 10, 0, 0,
+	}, []int{18})
+	center, radius, err := xy.MaximumInscribedCircle(polygon, 0.01)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%.1f %.1f %.1f\n", center[0], center[1], radius)
+	// Output: 8.8 1.2 1.2
+}
@@ -0,0 +1,97 @@
+package xy_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestMaximumInscribedCircle(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		g              geom.T
+		tolerance      float64
+		expectedCenter geom.Coord
+		expectedRadius float64
+	}{
+		{
+			name: "empty",
+			g:    geom.NewPolygon(geom.XY),
+		},
+		{
+			name:           "square",
+			g:              geom.NewPolygonFlat(geom.XY, []float64{0, 0, 4, 0, 4, 4, 0, 4, 0, 0}, []int{10}),
+			tolerance:      1e-6,
+			expectedCenter: geom.Coord{2, 2},
+			expectedRadius: 2,
+		},
+		{
+			name:           "rectangle",
+			g:              geom.NewPolygonFlat(geom.XYZ, []float64{0, 0, 1, 0, 2, 1, 10, 2, 1, 10, 0, 1, 0, 0, 1}, []int{15}),
+			expectedRadius: 1,
+		},
+		{
+			name: "l_shape",
+			g: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 10, 0, 10, 2, 2, 2, 2, 10, 0, 10, 0, 0,
+			}, []int{14}),
+			tolerance:      1e-6,
+			expectedRadius: 2 * math.Sqrt2 / (1 + math.Sqrt2),
+		},
+		{
+			name: "square_with_hole",
+			g: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+				4, 4, 4, 6, 6, 6, 6, 4, 4, 4,
+			}, []int{10, 20}),
+			tolerance:      1e-6,
+			expectedRadius: 4 * math.Sqrt2 / (1 + math.Sqrt2),
+		},
+		{
+			name: "multi_polygon",
+			g: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 1, 0, 1, 1, 0, 1, 0, 0,
+				5, 0, 11, 0, 11, 6, 5, 6, 5, 0,
+			}, [][]int{{10}, {20}}),
+			expectedCenter: geom.Coord{8, 3},
+			expectedRadius: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			center, radius, err := xy.MaximumInscribedCircle(tc.g, tc.tolerance)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tc.expectedRadius == 0 {
+				if center != nil || radius != 0 {
+					t.Errorf("expected nil, 0, got %v, %v", center, radius)
+				}
+				return
+			}
+			tolerance := tc.tolerance
+			if tolerance == 0 {
+				tolerance = 0.011
+			}
+			if radius > tc.expectedRadius+1e-9 || radius < tc.expectedRadius-tolerance {
+				t.Errorf("expected radius %v, got %v", tc.expectedRadius, radius)
+			}
+			if tc.expectedCenter != nil && xy.Distance(center, tc.expectedCenter) > 2*tolerance {
+				t.Errorf("expected center %v, got %v", tc.expectedCenter, center)
+			}
+			if contains, err := xy.Contains(tc.g, geom.NewPointFlat(geom.XY, center)); err != nil || !contains {
+				t.Errorf("expected %v to be inside", center)
+			}
+		})
+	}
+}
+
+func TestMaximumInscribedCircleUnsupportedType(t *testing.T) {
+	g := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 1})
+	var errUnsupportedType geom.ErrUnsupportedType
+	if _, _, err := xy.MaximumInscribedCircle(g, 0); !errors.As(err, &errUnsupportedType) {
+		t.Errorf("expected geom.ErrUnsupportedType, got %v", err)
+	}
+}
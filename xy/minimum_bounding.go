@@ -0,0 +1,179 @@
+package xy
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/twpayne/go-geom"
+)
+
+// MinimumRotatedRectangle returns the rectangle of minimum area, at any
+// orientation, that contains g, also known as its minimum oriented bounding
+// box. It is found with rotating calipers around the convex hull of g, as
+// one side of the rectangle always lies along an edge of the hull.
+//
+// The result is a counterclockwise *geom.Polygon with layout geom.XY and the
+// SRID of g. If the points of g are all collinear then the result is the
+// *geom.LineString between the extreme points, if they are all the same
+// point then it is a *geom.Point, and if g is empty then it is nil. If g, or
+// a geometry in g, is nil or of an unsupported type then it returns a
+// geom.ErrUnsupportedType error.
+func MinimumRotatedRectangle(g geom.T) (geom.T, error) {
+	if err := checkSupported(g); err != nil {
+		return nil, err
+	}
+	hull := counterclockwiseHull(appendXYCoords(nil, g))
+	switch n := len(hull) / 2; {
+	case n == 0:
+		return nil, nil
+	case n == 1:
+		return geom.NewPointFlat(geom.XY, hull).SetSRID(g.SRID()), nil
+	case n == 2:
+		return geom.NewLineStringFlat(geom.XY, hull).SetSRID(g.SRID()), nil
+	}
+
+	n := len(hull) / 2
+	point := func(i int) (float64, float64) {
+		i %= n
+		return hull[2*i], hull[2*i+1]
+	}
+	// For each edge of the hull, the rectangle is bounded by the vertices
+	// that are furthest along the edge, furthest back along it, and furthest
+	// from it. These only ever advance as the edge advances around the hull.
+	right, far, left := 1, 1, 1
+	minArea := math.Inf(1)
+	var rectangle []float64
+	for i := range n {
+		x0, y0 := point(i)
+		x1, y1 := point(i + 1)
+		length := math.Hypot(x1-x0, y1-y0)
+		ux, uy := (x1-x0)/length, (y1-y0)/length
+		along := func(j int) float64 {
+			x, y := point(j)
+			return (x-x0)*ux + (y-y0)*uy
+		}
+		across := func(j int) float64 {
+			x, y := point(j)
+			return (y-y0)*ux - (x-x0)*uy
+		}
+		right = max(right, i+1)
+		for along(right+1) > along(right) {
+			right++
+		}
+		far = max(far, right)
+		for across(far+1) > across(far) {
+			far++
+		}
+		left = max(left, far)
+		for along(left+1) < along(left) {
+			left++
+		}
+		minU, maxU, maxV := along(left), along(right), across(far)
+		if area := (maxU - minU) * maxV; area < minArea {
+			minArea = area
+			rectangle = rectangle[:0]
+			for _, uv := range [][2]float64{{minU, 0}, {maxU, 0}, {maxU, maxV}, {minU, maxV}, {minU, 0}} {
+				rectangle = append(rectangle, x0+uv[0]*ux-uv[1]*uy, y0+uv[0]*uy+uv[1]*ux)
+			}
+		}
+	}
+	return geom.NewPolygonFlat(geom.XY, rectangle, []int{len(rectangle)}).SetSRID(g.SRID()), nil
+}
+
+// MinimumBoundingCircle returns the center and radius of the smallest circle
+// that contains g. It returns a nil center if g is empty. If g, or a geometry
+// in g, is nil or of an unsupported type then it returns a
+// geom.ErrUnsupportedType error.
+func MinimumBoundingCircle(g geom.T) (center geom.Coord, radius float64, err error) {
+	if err := checkSupported(g); err != nil {
+		return nil, 0, err
+	}
+	points := counterclockwiseHull(appendXYCoords(nil, g))
+	n := len(points) / 2
+	if n == 0 {
+		return nil, 0, nil
+	}
+
+	// Welzl's algorithm, iteratively, with the points in a random order so
+	// that the expected running time is linear.
+	order := rand.New(rand.NewPCG(1, 2)).Perm(n)
+	point := func(i int) (float64, float64) {
+		return points[2*order[i]], points[2*order[i]+1]
+	}
+	cx, cy := point(0)
+	r := 0.0
+	outside := func(x, y float64) bool {
+		return math.Hypot(x-cx, y-cy) > r*(1+1e-12)
+	}
+	for i := 1; i < n; i++ {
+		xi, yi := point(i)
+		if !outside(xi, yi) {
+			continue
+		}
+		cx, cy, r = xi, yi, 0
+		for j := range i {
+			xj, yj := point(j)
+			if !outside(xj, yj) {
+				continue
+			}
+			cx, cy = (xi+xj)/2, (yi+yj)/2
+			r = math.Hypot(xi-xj, yi-yj) / 2
+			for k := range j {
+				xk, yk := point(k)
+				if !outside(xk, yk) {
+					continue
+				}
+				cx, cy, r = circumcircle(xi, yi, xj, yj, xk, yk)
+			}
+		}
+	}
+	return geom.Coord{cx, cy}, r, nil
+}
+
+// circumcircle returns the center and radius of the circle through (ax, ay),
+// (bx, by), and (cx, cy). If the points are collinear then it returns the
+// circle with the two furthest apart points as its diameter.
+func circumcircle(ax, ay, bx, by, cx, cy float64) (float64, float64, float64) {
+	bx, by, cx, cy = bx-ax, by-ay, cx-ax, cy-ay
+	d := 2 * (bx*cy - by*cx)
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	if d == 0 {
+		bc2 := (cx-bx)*(cx-bx) + (cy-by)*(cy-by)
+		switch {
+		case b2 >= c2 && b2 >= bc2:
+			return ax + bx/2, ay + by/2, math.Sqrt(b2) / 2
+		case c2 >= bc2:
+			return ax + cx/2, ay + cy/2, math.Sqrt(c2) / 2
+		default:
+			return ax + (bx+cx)/2, ay + (by+cy)/2, math.Sqrt(bc2) / 2
+		}
+	}
+	ux := (cy*b2 - by*c2) / d
+	uy := (bx*c2 - cx*b2) / d
+	return ax + ux, ay + uy, math.Hypot(ux, uy)
+}
+
+// counterclockwiseHull returns the distinct vertices of the convex hull of
+// the points flatCoords, which have layout geom.XY, in counterclockwise
+// order without a closing point.
+func counterclockwiseHull(flatCoords []float64) []float64 {
+	switch hull := ConvexHullFlat(geom.XY, flatCoords).(type) {
+	case *geom.Point:
+		return hull.FlatCoords()
+	case *geom.LineString:
+		return hull.FlatCoords()
+	case *geom.Polygon:
+		ring := hull.FlatCoords()
+		ring = ring[:len(ring)-2]
+		if SignedArea(geom.XY, hull.FlatCoords()) > 0 {
+			reversed := make([]float64, 0, len(ring))
+			for i := len(ring) - 2; i >= 0; i -= 2 {
+				reversed = append(reversed, ring[i], ring[i+1])
+			}
+			ring = reversed
+		}
+		return ring
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,29 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleMinimumRotatedRectangle() {
+	// A building footprint rotated by 45 degrees.
+	footprint := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 2, 2, 1, 3, -1, 1, 0, 0}, []int{10})
+	rectangle, err := xy.MinimumRotatedRectangle(footprint)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%.1f\n", rectangle.(*geom.Polygon).Area())
+	// Output: 4.0
+}
+
+func ExampleMinimumBoundingCircle() {
+	mp := geom.NewMultiPointFlat(geom.XY, []float64{-2, 0, 2, 0, 0, 1})
+	center, radius, err := xy.MinimumBoundingCircle(mp)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(center, radius)
+	// Output: [0 0] 2
+}
@@ -0,0 +1,192 @@
+package xy_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestMinimumRotatedRectangle(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		g            geom.T
+		expected     geom.T
+		expectedArea float64
+	}{
+		{
+			name: "empty",
+			g:    geom.NewMultiPoint(geom.XY),
+		},
+		{
+			name:     "point",
+			g:        geom.NewMultiPointFlat(geom.XYZ, []float64{1, 2, 3, 1, 2, 4}).SetSRID(4326),
+			expected: geom.NewPointFlat(geom.XY, []float64{1, 2}).SetSRID(4326),
+		},
+		{
+			name:     "collinear",
+			g:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2, 2, 1, 1}),
+			expected: geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2, 2}),
+		},
+		{
+			name:         "axis_aligned",
+			g:            geom.NewPolygonFlat(geom.XY, []float64{0, 0, 4, 0, 4, 2, 0, 2, 0, 0}, []int{10}),
+			expectedArea: 8,
+		},
+		{
+			name:         "diamond",
+			g:            geom.NewPolygonFlat(geom.XY, []float64{0, 0, 2, 2, 1, 3, -1, 1, 0, 0}, []int{10}),
+			expectedArea: 4,
+		},
+		{
+			name:         "triangle",
+			g:            geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 4, 0, 1, 3}),
+			expectedArea: 12,
+		},
+		{
+			name: "rotated_rectangle_with_interior_points",
+			g: geom.NewMultiPointFlat(geom.XY, []float64{
+				0, 0, 3, 4, -4, 3, -1, 7, 0, 1, -1, 4, 1, 3,
+			}),
+			expectedArea: 25,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := xy.MinimumRotatedRectangle(tc.g)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tc.expectedArea == 0 {
+				if !reflect.DeepEqual(tc.expected, got) {
+					t.Errorf("expected %v, got %v", tc.expected, got)
+				}
+				return
+			}
+			polygon, ok := got.(*geom.Polygon)
+			if !ok {
+				t.Fatalf("expected a *geom.Polygon, got %T", got)
+			}
+			if polygon.NumCoords() != 5 {
+				t.Errorf("expected 5 coordinates, got %v", polygon.FlatCoords())
+			}
+			if area := -xy.SignedArea(geom.XY, polygon.FlatCoords()); math.Abs(area-tc.expectedArea) > 1e-9 {
+				t.Errorf("expected counterclockwise area %v, got %v", tc.expectedArea, area)
+			}
+			coords := polygon.Coords()[0]
+			for i := range 4 {
+				a, b, c := coords[i], coords[i+1], coords[(i+2)%4]
+				if dot := (b[0]-a[0])*(c[0]-b[0]) + (b[1]-a[1])*(c[1]-b[1]); math.Abs(dot) > 1e-9 {
+					t.Errorf("expected a right angle at %v, got %v", b, polygon.FlatCoords())
+				}
+			}
+			flatCoords := tc.g.FlatCoords()
+			for i := 0; i < len(flatCoords); i += tc.g.Stride() {
+				c := geom.Coord(flatCoords[i : i+2])
+				if !xy.IsPointInRing(geom.XY, c, polygon.FlatCoords()) && !xy.IsOnLine(geom.XY, c, polygon.FlatCoords()) {
+					// Allow for rounding of points on the boundary.
+					if xy.DistanceFromPointToLineString(geom.XY, c, polygon.FlatCoords()) > 1e-9 {
+						t.Errorf("%v is outside %v", c, polygon.FlatCoords())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMinimumBoundingCircle(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		g              geom.T
+		expectedCenter geom.Coord
+		expectedRadius float64
+	}{
+		{
+			name: "empty",
+			g:    geom.NewMultiPoint(geom.XY),
+		},
+		{
+			name:           "point",
+			g:              geom.NewPointFlat(geom.XY, []float64{1, 2}),
+			expectedCenter: geom.Coord{1, 2},
+		},
+		{
+			name:           "collinear",
+			g:              geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 1, 4, 4}),
+			expectedCenter: geom.Coord{2, 2},
+			expectedRadius: 2 * math.Sqrt2,
+		},
+		{
+			name:           "obtuse_triangle",
+			g:              geom.NewMultiPointFlat(geom.XY, []float64{-2, 0, 2, 0, 0, 1}),
+			expectedCenter: geom.Coord{0, 0},
+			expectedRadius: 2,
+		},
+		{
+			name:           "acute_triangle",
+			g:              geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 6, 0, 3, 4}),
+			expectedCenter: geom.Coord{3, 0.875},
+			expectedRadius: 3.125,
+		},
+		{
+			name: "square_with_interior_points",
+			g: geom.NewMultiPointFlat(geom.XYZ, []float64{
+				1, 1, 0, -1, 1, 0, 0, 0, 0, -1, -1, 0, 0.5, 0.5, 0, 1, -1, 0,
+			}),
+			expectedCenter: geom.Coord{0, 0},
+			expectedRadius: math.Sqrt2,
+		},
+		{
+			name: "geometry_collection",
+			g: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{0, 10}),
+				geom.NewLineStringFlat(geom.XY, []float64{0, 0, 0, 2}),
+			),
+			expectedCenter: geom.Coord{0, 5},
+			expectedRadius: 5,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			center, radius, err := xy.MinimumBoundingCircle(tc.g)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tc.expectedCenter == nil {
+				if center != nil || radius != 0 {
+					t.Errorf("expected nil, 0, got %v, %v", center, radius)
+				}
+				return
+			}
+			if xy.Distance(center, tc.expectedCenter) > 1e-9 || math.Abs(radius-tc.expectedRadius) > 1e-9 {
+				t.Errorf("expected %v, %v, got %v, %v", tc.expectedCenter, tc.expectedRadius, center, radius)
+			}
+		})
+	}
+}
+
+func TestMinimumBoundingUnsupportedType(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		g    geom.T
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "geometry_collection_with_nil",
+			g:    geom.NewGeometryCollection().MustPush(nil),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var errUnsupportedType geom.ErrUnsupportedType
+			if _, err := xy.MinimumRotatedRectangle(tc.g); !errors.As(err, &errUnsupportedType) {
+				t.Errorf("expected geom.ErrUnsupportedType from MinimumRotatedRectangle, got %v", err)
+			}
+			if _, _, err := xy.MinimumBoundingCircle(tc.g); !errors.As(err, &errUnsupportedType) {
+				t.Errorf("expected geom.ErrUnsupportedType from MinimumBoundingCircle, got %v", err)
+			}
+		})
+	}
+}
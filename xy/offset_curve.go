@@ -0,0 +1,157 @@
+package xy
+
+import (
+	"math"
+
+	"github.com/twpayne/go-geom"
+)
+
+// OffsetCurve returns the line parallel to ls at distance to its left, or to
+// its right if distance is negative. joinStyle is the style of the corners
+// on the outside of each turn. opts set the number of segments per quarter
+// circle of JoinRound and the mitre limit of JoinMitre, as for Buffer, with
+// the same defaults. Cap and join style options are ignored. On the inside
+// of each turn the offset segments are joined where they meet.
+//
+// Each vertex of the result has the ordinates other than x and y, such as Z
+// and M, of the vertex of ls that it is offset from, so they are
+// interpolated along the result as they are along ls. Closed lines give
+// closed offset curves. The result is not cleaned, so it may intersect
+// itself where distance is greater than the radius of curvature of ls, for
+// example by looping back on the inside of a narrow notch.
+// The result has the layout and SRID of ls.
+func OffsetCurve(ls *geom.LineString, distance float64, joinStyle JoinStyle, opts ...BufferOption) *geom.LineString {
+	if distance == 0 {
+		return ls.Clone()
+	}
+	o := offsetCurveBuilder{
+		flatCoords: ls.FlatCoords(),
+		stride:     ls.Stride(),
+		distance:   distance,
+		params: bufferParams{
+			quadrantSegments: defaultQuadrantSegments,
+			mitreLimit:       defaultMitreLimit,
+		},
+	}
+	for _, opt := range opts {
+		opt(&o.params)
+	}
+	o.params.joinStyle = joinStyle
+
+	// Remove repeated points, which have no direction.
+	var vertices []int
+	for i := 0; i < len(o.flatCoords); i += o.stride {
+		if n := len(vertices); n == 0 || o.flatCoords[i] != o.flatCoords[vertices[n-1]] || o.flatCoords[i+1] != o.flatCoords[vertices[n-1]+1] {
+			vertices = append(vertices, i)
+		}
+	}
+	n := len(vertices)
+	if n < 2 {
+		return geom.NewLineString(ls.Layout()).SetSRID(ls.SRID())
+	}
+
+	closed := n >= 4 && o.flatCoords[vertices[0]] == o.flatCoords[vertices[n-1]] && o.flatCoords[vertices[0]+1] == o.flatCoords[vertices[n-1]+1]
+	if closed {
+		o.join(vertices[n-2], vertices[0], vertices[1])
+	} else {
+		x0, y0, x1, y1 := o.xy(vertices[0], vertices[1])
+		nx, ny := leftNormal(x0, y0, x1, y1, distance)
+		o.point(vertices[0], x0+nx, y0+ny)
+	}
+	for i := 1; i < n-1; i++ {
+		o.join(vertices[i-1], vertices[i], vertices[i+1])
+	}
+	if closed {
+		o.result = append(o.result, o.result[:o.stride]...)
+	} else {
+		x0, y0, x1, y1 := o.xy(vertices[n-2], vertices[n-1])
+		nx, ny := leftNormal(x0, y0, x1, y1, distance)
+		o.point(vertices[n-1], x1+nx, y1+ny)
+	}
+	return geom.NewLineStringFlat(ls.Layout(), o.result).SetSRID(ls.SRID())
+}
+
+// An offsetCurveBuilder builds an offset curve from the vertices of a line.
+// Vertices are identified by their index in flatCoords.
+type offsetCurveBuilder struct {
+	flatCoords []float64
+	stride     int
+	distance   float64
+	params     bufferParams
+	result     []float64
+}
+
+// xy returns the x and y ordinates of the vertices i and j.
+func (o *offsetCurveBuilder) xy(i, j int) (float64, float64, float64, float64) {
+	return o.flatCoords[i], o.flatCoords[i+1], o.flatCoords[j], o.flatCoords[j+1]
+}
+
+// point appends the point (x, y) with the other ordinates of vertex i to the
+// result.
+func (o *offsetCurveBuilder) point(i int, x, y float64) {
+	o.result = append(o.result, x, y)
+	o.result = append(o.result, o.flatCoords[i+2:i+o.stride]...)
+}
+
+// join appends the points of the offset curve at vertex i1, between the
+// segments from vertex i0 and to vertex i2, to the result.
+func (o *offsetCurveBuilder) join(i0, i1, i2 int) {
+	x0, y0, x1, y1 := o.xy(i0, i1)
+	x2, y2 := o.flatCoords[i2], o.flatCoords[i2+1]
+	d := o.distance
+	nx0, ny0 := leftNormal(x0, y0, x1, y1, d)
+	nx1, ny1 := leftNormal(x1, y1, x2, y2, d)
+	cross := (x1-x0)*(y2-y1) - (y1-y0)*(x2-x1)
+	switch {
+	case cross == 0 && (x1-x0)*(x2-x1)+(y1-y0)*(y2-y1) > 0:
+		o.point(i1, x1+nx0, y1+ny0)
+	case cross == 0:
+		// The line reverses, so the offset curve goes around the end of
+		// the line, clockwise on the left and counterclockwise on the right.
+		if o.params.joinStyle == JoinRound {
+			o.arc(i1, nx0, ny0, -math.Copysign(math.Pi, d))
+			return
+		}
+		o.point(i1, x1+nx0, y1+ny0)
+		o.point(i1, x1+nx1, y1+ny1)
+	case (cross > 0) == (d > 0):
+		// The vertex is on the inside of the turn. Join the offset segments
+		// where they meet, if they do.
+		mx, my, _ := mitre(nx0, ny0, nx1, ny1, d, math.Inf(1))
+		t0 := ((x1+mx-x0-nx0)*(x1-x0) + (y1+my-y0-ny0)*(y1-y0)) / ((x1-x0)*(x1-x0) + (y1-y0)*(y1-y0))
+		t1 := ((x1+mx-x1-nx1)*(x2-x1) + (y1+my-y1-ny1)*(y2-y1)) / ((x2-x1)*(x2-x1) + (y2-y1)*(y2-y1))
+		if t0 >= 0 && t1 <= 1 {
+			o.point(i1, x1+mx, y1+my)
+			return
+		}
+		o.point(i1, x1+nx0, y1+ny0)
+		o.point(i1, x1+nx1, y1+ny1)
+	default:
+		switch o.params.joinStyle {
+		case JoinRound:
+			o.arc(i1, nx0, ny0, Normalize(math.Atan2(ny1, nx1)-math.Atan2(ny0, nx0)))
+			return
+		case JoinMitre:
+			if mx, my, ok := mitre(nx0, ny0, nx1, ny1, d, o.params.mitreLimit); ok {
+				o.point(i1, x1+mx, y1+my)
+				return
+			}
+		}
+		o.point(i1, x1+nx0, y1+ny0)
+		o.point(i1, x1+nx1, y1+ny1)
+	}
+}
+
+// arc appends the points of the circular arc around vertex i that starts at
+// the offset (nx, ny) and turns through sweep radians to the result.
+func (o *offsetCurveBuilder) arc(i int, nx, ny, sweep float64) {
+	x, y := o.flatCoords[i], o.flatCoords[i+1]
+	radius := math.Abs(o.distance)
+	angle0 := math.Atan2(ny, nx)
+	n := int(math.Ceil(math.Abs(sweep)*float64(2*o.params.quadrantSegments)/math.Pi - 1e-9))
+	o.point(i, x+nx, y+ny)
+	for j := 1; j <= n; j++ {
+		angle := angle0 + sweep*float64(j)/float64(n)
+		o.point(i, x+radius*math.Cos(angle), y+radius*math.Sin(angle))
+	}
+}
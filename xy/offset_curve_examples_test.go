@@ -0,0 +1,15 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleOffsetCurve() {
+	centerline := geom.NewLineStringFlat(geom.XYZ, []float64{0, 0, 100, 10, 0, 110, 10, 10, 120})
+	marking := xy.OffsetCurve(centerline, -2, xy.JoinMitre)
+	fmt.Println(marking.FlatCoords())
+	// Output: [0 -2 100 12 -2 110 12 10 120]
+}
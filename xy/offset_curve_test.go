@@ -0,0 +1,194 @@
+package xy_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestOffsetCurve(t *testing.T) {
+	corner := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10})
+	for _, tc := range []struct {
+		name      string
+		ls        *geom.LineString
+		distance  float64
+		joinStyle xy.JoinStyle
+		opts      []xy.BufferOption
+		expected  *geom.LineString
+	}{
+		{
+			name:     "left",
+			ls:       geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}).SetSRID(3857),
+			distance: 2,
+			expected: geom.NewLineStringFlat(geom.XY, []float64{0, 2, 10, 2}).SetSRID(3857),
+		},
+		{
+			name:     "right",
+			ls:       geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			distance: -2,
+			expected: geom.NewLineStringFlat(geom.XY, []float64{0, -2, 10, -2}),
+		},
+		{
+			name:     "zero_distance",
+			ls:       corner,
+			distance: 0,
+			expected: corner,
+		},
+		{
+			name:     "xyzm",
+			ls:       geom.NewLineStringFlat(geom.XYZM, []float64{0, 0, 1, 5, 10, 0, 3, 7}),
+			distance: 1,
+			expected: geom.NewLineStringFlat(geom.XYZM, []float64{0, 1, 1, 5, 10, 1, 3, 7}),
+		},
+		{
+			name:     "repeated_points",
+			ls:       geom.NewLineStringFlat(geom.XYZ, []float64{0, 0, 1, 0, 0, 2, 10, 0, 3, 10, 0, 4}),
+			distance: 1,
+			expected: geom.NewLineStringFlat(geom.XYZ, []float64{0, 1, 1, 10, 1, 3}),
+		},
+		{
+			name:     "single_point",
+			ls:       geom.NewLineStringFlat(geom.XY, []float64{1, 1, 1, 1}),
+			distance: 1,
+			expected: geom.NewLineString(geom.XY),
+		},
+		{
+			name:     "empty",
+			ls:       geom.NewLineString(geom.XYM),
+			distance: 1,
+			expected: geom.NewLineString(geom.XYM),
+		},
+		{
+			name:      "inside_corner",
+			ls:        corner,
+			distance:  1,
+			joinStyle: xy.JoinBevel,
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{0, 1, 9, 1, 9, 10}),
+		},
+		{
+			name:      "outside_corner_mitre",
+			ls:        corner,
+			distance:  -1,
+			joinStyle: xy.JoinMitre,
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{0, -1, 11, -1, 11, 10}),
+		},
+		{
+			name:      "outside_corner_bevel",
+			ls:        corner,
+			distance:  -1,
+			joinStyle: xy.JoinBevel,
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{0, -1, 10, -1, 11, 0, 11, 10}),
+		},
+		{
+			name:      "sharp_corner_mitre",
+			ls:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 100, 0, 0, 1}),
+			distance:  -1,
+			joinStyle: xy.JoinMitre,
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{0, -1, 100, -1, 100.0099995000375, 0.9999500037496877, 0.009999500037496877, 1.9999500037496878}),
+		},
+		{
+			name:      "closed",
+			ls:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}),
+			distance:  1,
+			joinStyle: xy.JoinMitre,
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{1, 1, 9, 1, 9, 9, 1, 9, 1, 1}),
+		},
+		{
+			name:      "closed_outside",
+			ls:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}),
+			distance:  -1,
+			joinStyle: xy.JoinMitre,
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{-1, -1, 11, -1, 11, 11, -1, 11, -1, -1}),
+		},
+		{
+			name:      "mitre_limit",
+			ls:        corner,
+			distance:  -1,
+			joinStyle: xy.JoinMitre,
+			opts:      []xy.BufferOption{xy.BufferOptionMitreLimit(1)},
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{0, -1, 10, -1, 11, 0, 11, 10}),
+		},
+		{
+			name:      "quadrant_segments",
+			ls:        corner,
+			distance:  -1,
+			joinStyle: xy.JoinRound,
+			opts:      []xy.BufferOption{xy.BufferOptionQuadrantSegments(1)},
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{0, -1, 10, -1, 11, 0, 11, 10}),
+		},
+		{
+			// The offset curve is not cleaned, so it loops back on itself
+			// inside a notch that is narrower than twice the distance.
+			name:      "notch_loop",
+			ls:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 2, 12, 2, 12, 0, 20, 0}),
+			distance:  -2,
+			joinStyle: xy.JoinMitre,
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{0, -2, 12, -2, 12, 0, 10, 0, 10, -2, 20, -2}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := xy.OffsetCurve(tc.ls, tc.distance, tc.joinStyle, tc.opts...)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestOffsetCurveRound(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		ls             *geom.LineString
+		distance       float64
+		center         geom.Coord
+		expectedPoints int
+		expectedEnd    geom.Coord
+	}{
+		{
+			name:           "outside_corner",
+			ls:             geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10}),
+			distance:       -1,
+			center:         geom.Coord{10, 0},
+			expectedPoints: 11,
+			expectedEnd:    geom.Coord{11, 10},
+		},
+		{
+			name:           "reversal_left",
+			ls:             geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 0, 0}),
+			distance:       1,
+			center:         geom.Coord{10, 0},
+			expectedPoints: 19,
+			expectedEnd:    geom.Coord{0, -1},
+		},
+		{
+			name:           "reversal_right",
+			ls:             geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 0, 0}),
+			distance:       -1,
+			center:         geom.Coord{10, 0},
+			expectedPoints: 19,
+			expectedEnd:    geom.Coord{0, 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := xy.OffsetCurve(tc.ls, tc.distance, xy.JoinRound)
+			if got.NumCoords() != tc.expectedPoints {
+				t.Fatalf("expected %d points but got %d", tc.expectedPoints, got.NumCoords())
+			}
+			for i := 1; i < got.NumCoords()-1; i++ {
+				c := got.Coord(i)
+				if r := math.Hypot(c.X()-tc.center.X(), c.Y()-tc.center.Y()); math.Abs(r-math.Abs(tc.distance)) > 1e-9 {
+					t.Errorf("expected point %d to be %f from the vertex but got %f", i, math.Abs(tc.distance), r)
+				}
+				if c.X() < tc.center.X()-1e-9 {
+					t.Errorf("expected point %d to be beyond the vertex but got %v", i, c)
+				}
+			}
+			if end := got.Coord(got.NumCoords() - 1); !end.Equal(geom.XY, tc.expectedEnd) {
+				t.Errorf("expected end %v but got %v", tc.expectedEnd, end)
+			}
+		})
+	}
+}
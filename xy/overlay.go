@@ -0,0 +1,118 @@
+package xy
+
+import (
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal/planargraph"
+)
+
+// The overlay operations in this file accept *geom.Polygons and
+// *geom.MultiPolygons and return a geom.ErrUnsupportedType error for other
+// types. Their inputs should be valid; invalid inputs are interpreted with
+// the even-odd rule, see MakeValid. Results are valid and are computed from
+// the x and y ordinates only, so they have layout geom.XY and the SRID of a.
+// Polygonal results are a *geom.Polygon, a *geom.MultiPolygon if there is
+// more than one Polygon, or an empty *geom.Polygon.
+
+// Intersection returns the points that are in both a and b. Where a and b
+// touch, the intersection includes lines and points that are not part of
+// any area, in which case the result is a *geom.GeometryCollection
+// containing the polygonal result, if it is not empty, followed by a
+// *geom.MultiLineString and a *geom.MultiPoint, if they are not empty.
+func Intersection(a, b geom.T) (geom.T, error) {
+	return overlay(a, b, func(inA, inB bool) bool { return inA && inB }, true)
+}
+
+// Union returns the points that are in a or b, or both.
+func Union(a, b geom.T) (geom.T, error) {
+	return overlay(a, b, func(inA, inB bool) bool { return inA || inB }, false)
+}
+
+// Difference returns the points that are in a but not in b.
+func Difference(a, b geom.T) (geom.T, error) {
+	return overlay(a, b, func(inA, inB bool) bool { return inA && !inB }, false)
+}
+
+// SymDifference returns the points that are in either a or b, but not in
+// both.
+func SymDifference(a, b geom.T) (geom.T, error) {
+	return overlay(a, b, func(inA, inB bool) bool { return inA != inB }, false)
+}
+
+// overlay returns the area of the regions for which op returns true. If
+// degenerate is true then the lines and points where the closures of a and
+// b meet outside the area are also returned.
+func overlay(a, b geom.T, op func(inA, inB bool) bool, degenerate bool) (geom.T, error) {
+	builder := planargraph.NewBuilder(2)
+	for operand, g := range [2]geom.T{a, b} {
+		switch g := g.(type) {
+		case *geom.Polygon, *geom.MultiPolygon:
+			if err := addToBuilder(builder, operand, g); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, geom.ErrUnsupportedType{Value: g}
+		}
+	}
+	graph := builder.Build()
+	inArea := func(inside []bool) bool {
+		return op(inside[0], inside[1])
+	}
+	area := polygonal(planargraph.Polygons(graph.AreaRings(inArea)), a.SRID())
+	if !degenerate {
+		return area, nil
+	}
+
+	// An edge or node is in the closure of an operand's area if any region
+	// around it is inside the area.
+	inClosures := func(edges []*planargraph.Edge) bool {
+		for operand := range 2 {
+			inClosure := false
+			for _, e := range edges {
+				inClosure = inClosure || e.Left[operand] || e.Right[operand]
+			}
+			if !inClosure {
+				return false
+			}
+		}
+		return true
+	}
+	isLine := make(map[*planargraph.Edge]bool)
+	var lineEdges []*planargraph.Edge
+	for _, e := range graph.Edges {
+		if !inArea(e.Left) && !inArea(e.Right) && inClosures([]*planargraph.Edge{e}) {
+			isLine[e] = true
+			lineEdges = append(lineEdges, e)
+		}
+	}
+	var points []float64
+nodes:
+	for _, n := range graph.Nodes {
+		for _, e := range n.Edges {
+			if isLine[e] || inArea(e.Left) || inArea(e.Right) {
+				continue nodes
+			}
+		}
+		if inClosures(n.Edges) {
+			points = append(points, n.X, n.Y)
+		}
+	}
+	if len(lineEdges) == 0 && len(points) == 0 {
+		return area, nil
+	}
+
+	result := geom.NewGeometryCollection().SetSRID(a.SRID())
+	if !area.Empty() {
+		_ = result.Push(area)
+	}
+	if len(lineEdges) != 0 {
+		mls := geom.NewMultiLineString(geom.XY).SetSRID(a.SRID())
+		for _, line := range planargraph.Lines(lineEdges) {
+			_ = mls.Push(geom.NewLineStringFlat(geom.XY, line))
+		}
+		_ = result.Push(mls)
+	}
+	if len(points) != 0 {
+		_ = result.Push(geom.NewMultiPointFlat(geom.XY, points).SetSRID(a.SRID()))
+	}
+	return result, nil
+}
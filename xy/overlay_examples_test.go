@@ -0,0 +1,19 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleIntersection() {
+	zone := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10})
+	boundary := geom.NewPolygonFlat(geom.XY, []float64{5, -5, 15, -5, 15, 5, 5, 5, 5, -5}, []int{10})
+	clipped, err := xy.Intersection(zone, boundary)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(clipped.FlatCoords())
+	// Output: [5 0 10 0 10 5 5 5 5 0]
+}
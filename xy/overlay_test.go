@@ -0,0 +1,135 @@
+package xy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestOverlay(t *testing.T) {
+	square1 := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}, []int{10}).SetSRID(4326)
+	square2 := geom.NewPolygonFlat(geom.XYZ, []float64{1, 1, 0, 3, 1, 0, 3, 3, 0, 1, 3, 0, 1, 1, 0}, []int{15})
+	for _, tc := range []struct {
+		name     string
+		f        func(geom.T, geom.T) (geom.T, error)
+		a, b     geom.T
+		expected geom.T
+	}{
+		{
+			name:     "intersection",
+			f:        xy.Intersection,
+			a:        square1,
+			b:        square2,
+			expected: geom.NewPolygonFlat(geom.XY, []float64{1, 1, 2, 1, 2, 2, 1, 2, 1, 1}, []int{10}).SetSRID(4326),
+		},
+		{
+			name:     "union",
+			f:        xy.Union,
+			a:        square1,
+			b:        square2,
+			expected: geom.NewPolygonFlat(geom.XY, []float64{0, 0, 2, 0, 2, 1, 3, 1, 3, 3, 1, 3, 1, 2, 0, 2, 0, 0}, []int{18}).SetSRID(4326),
+		},
+		{
+			name:     "difference",
+			f:        xy.Difference,
+			a:        square1,
+			b:        square2,
+			expected: geom.NewPolygonFlat(geom.XY, []float64{0, 0, 2, 0, 2, 1, 1, 1, 1, 2, 0, 2, 0, 0}, []int{14}).SetSRID(4326),
+		},
+		{
+			name: "symdifference",
+			f:    xy.SymDifference,
+			a:    square1,
+			b:    square2,
+			expected: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 2, 0, 2, 1, 1, 1, 1, 2, 0, 2, 0, 0,
+				1, 2, 2, 2, 2, 1, 3, 1, 3, 3, 1, 3, 1, 2,
+			}, [][]int{{14}, {28}}).SetSRID(4326),
+		},
+		{
+			name: "difference_hole",
+			f:    xy.Difference,
+			a:    geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}),
+			b:    square2,
+			expected: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+				1, 1, 1, 3, 3, 3, 3, 1, 1, 1,
+			}, []int{10, 20}),
+		},
+		{
+			name:     "difference_empty",
+			f:        xy.Difference,
+			a:        square2,
+			b:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}),
+			expected: geom.NewPolygon(geom.XY),
+		},
+		{
+			name: "union_multipolygon",
+			f:    xy.Union,
+			a: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 1, 0, 1, 1, 0, 1, 0, 0,
+				5, 5, 6, 5, 6, 6, 5, 6, 5, 5,
+			}, [][]int{{10}, {20}}),
+			b: geom.NewPolygonFlat(geom.XY, []float64{1, 0, 2, 0, 2, 1, 1, 1, 1, 0}, []int{10}),
+			expected: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 1, 0, 2, 0, 2, 1, 1, 1, 0, 1, 0, 0,
+				5, 5, 6, 5, 6, 6, 5, 6, 5, 5,
+			}, [][]int{{14}, {24}}),
+		},
+		{
+			name: "intersection_touching_edge",
+			f:    xy.Intersection,
+			a:    square1,
+			b:    geom.NewPolygonFlat(geom.XY, []float64{2, 0, 4, 0, 4, 2, 2, 2, 2, 0}, []int{10}),
+			expected: geom.NewGeometryCollection().MustPush(
+				geom.NewMultiLineStringFlat(geom.XY, []float64{2, 0, 2, 2}, []int{4}).SetSRID(4326),
+			).SetSRID(4326),
+		},
+		{
+			name: "intersection_touching_point",
+			f:    xy.Intersection,
+			a:    square1,
+			b:    geom.NewPolygonFlat(geom.XY, []float64{2, 2, 4, 2, 4, 4, 2, 2}, []int{8}),
+			expected: geom.NewGeometryCollection().MustPush(
+				geom.NewMultiPointFlat(geom.XY, []float64{2, 2}).SetSRID(4326),
+			).SetSRID(4326),
+		},
+		{
+			name: "intersection_area_and_line",
+			f:    xy.Intersection,
+			a:    square1,
+			b: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				1, 1, 3, 1, 3, 3, 1, 3, 1, 1,
+				-2, 0, 0, 0, 0, 1, -2, 1, -2, 0,
+			}, [][]int{{10}, {20}}),
+			expected: geom.NewGeometryCollection().MustPush(
+				geom.NewPolygonFlat(geom.XY, []float64{1, 1, 2, 1, 2, 2, 1, 2, 1, 1}, []int{10}).SetSRID(4326),
+				geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 0, 1}, []int{4}).SetSRID(4326),
+			).SetSRID(4326),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.f(tc.a, tc.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v but got %v", tc.expected, got)
+			}
+			if err := xy.Validate(got); err != nil {
+				t.Errorf("expected a valid result but got %v", err)
+			}
+		})
+	}
+}
+
+func TestOverlayUnsupportedType(t *testing.T) {
+	_, err := xy.Union(geom.NewPointFlat(geom.XY, []float64{0, 0}), geom.NewPolygon(geom.XY))
+	var unsupportedTypeErr geom.ErrUnsupportedType
+	if !errors.As(err, &unsupportedTypeErr) {
+		t.Errorf("expected ErrUnsupportedType but got %v", err)
+	}
+}
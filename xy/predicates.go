@@ -0,0 +1,196 @@
+package xy
+
+import (
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/location"
+)
+
+// The predicates in this file follow the OGC Simple Features definitions.
+// They consider only the x and y ordinates, and accept any pair of
+// geometries, including GeometryCollections, whose elements are unioned.
+// They return a geom.ErrUnsupportedType error if either geometry is of an
+// unsupported type. Geometries whose bounds do not overlap are handled
+// without computing their intersection matrix.
+
+// Intersects returns true if a and b have at least one point in common.
+func Intersects(a, b geom.T) (bool, error) {
+	disjoint, err := Disjoint(a, b)
+	if err != nil {
+		return false, err
+	}
+	return !disjoint, nil
+}
+
+// Disjoint returns true if a and b have no points in common.
+func Disjoint(a, b geom.T) (bool, error) {
+	if err := checkSupported(a, b); err != nil {
+		return false, err
+	}
+	if !boundsOverlap(a, b) {
+		return true, nil
+	}
+	im, err := Relate(a, b)
+	if err != nil {
+		return false, err
+	}
+	return im.isFalse(location.Interior, location.Interior) &&
+		im.isFalse(location.Interior, location.Boundary) &&
+		im.isFalse(location.Boundary, location.Interior) &&
+		im.isFalse(location.Boundary, location.Boundary), nil
+}
+
+// Contains returns true if no point of b lies in the exterior of a and at
+// least one point of the interior of b lies in the interior of a.
+func Contains(a, b geom.T) (bool, error) {
+	if err := checkSupported(a, b); err != nil {
+		return false, err
+	}
+	if !boundsContain(a, b) {
+		return false, nil
+	}
+	im, err := Relate(a, b)
+	if err != nil {
+		return false, err
+	}
+	return !im.isFalse(location.Interior, location.Interior) &&
+		im.isFalse(location.Exterior, location.Interior) &&
+		im.isFalse(location.Exterior, location.Boundary), nil
+}
+
+// Within returns true if a lies within b, that is if b contains a.
+func Within(a, b geom.T) (bool, error) {
+	return Contains(b, a)
+}
+
+// Covers returns true if no point of b lies in the exterior of a. Unlike
+// Contains, Covers is true when b lies entirely in the boundary of a.
+func Covers(a, b geom.T) (bool, error) {
+	if err := checkSupported(a, b); err != nil {
+		return false, err
+	}
+	if !boundsContain(a, b) {
+		return false, nil
+	}
+	im, err := Relate(a, b)
+	if err != nil {
+		return false, err
+	}
+	return im.isFalse(location.Exterior, location.Interior) &&
+		im.isFalse(location.Exterior, location.Boundary), nil
+}
+
+// CoveredBy returns true if a is covered by b, that is if b covers a.
+func CoveredBy(a, b geom.T) (bool, error) {
+	return Covers(b, a)
+}
+
+// Touches returns true if a and b have at least one point in common but
+// their interiors do not intersect.
+func Touches(a, b geom.T) (bool, error) {
+	if err := checkSupported(a, b); err != nil {
+		return false, err
+	}
+	if !boundsOverlap(a, b) {
+		return false, nil
+	}
+	im, err := Relate(a, b)
+	if err != nil {
+		return false, err
+	}
+	return im.isFalse(location.Interior, location.Interior) &&
+		(!im.isFalse(location.Interior, location.Boundary) ||
+			!im.isFalse(location.Boundary, location.Interior) ||
+			!im.isFalse(location.Boundary, location.Boundary)), nil
+}
+
+// Crosses returns true if the interiors of a and b intersect in a geometry
+// of lower dimension than the higher of their dimensions, and neither
+// contains the other. Crosses is only true when a and b are points and
+// lines, points and areas, lines and areas, or two lines.
+func Crosses(a, b geom.T) (bool, error) {
+	if err := checkSupported(a, b); err != nil {
+		return false, err
+	}
+	if !boundsOverlap(a, b) {
+		return false, nil
+	}
+	im, err := Relate(a, b)
+	if err != nil {
+		return false, err
+	}
+	dimA, dimB := dimension(a), dimension(b)
+	switch {
+	case dimA == 1 && dimB == 1:
+		return im[location.Interior][location.Interior] == 0, nil
+	case dimA >= 0 && dimA < dimB:
+		return !im.isFalse(location.Interior, location.Interior) &&
+			!im.isFalse(location.Interior, location.Exterior), nil
+	case dimB >= 0 && dimB < dimA:
+		return !im.isFalse(location.Interior, location.Interior) &&
+			!im.isFalse(location.Exterior, location.Interior), nil
+	default:
+		return false, nil
+	}
+}
+
+// Overlaps returns true if a and b have the same dimension, their interiors
+// intersect in a geometry of that dimension, and neither contains the other.
+func Overlaps(a, b geom.T) (bool, error) {
+	if err := checkSupported(a, b); err != nil {
+		return false, err
+	}
+	if !boundsOverlap(a, b) {
+		return false, nil
+	}
+	im, err := Relate(a, b)
+	if err != nil {
+		return false, err
+	}
+	dimA, dimB := dimension(a), dimension(b)
+	if dimA != dimB || dimA < 0 {
+		return false, nil
+	}
+	return im[location.Interior][location.Interior] == dimA &&
+		!im.isFalse(location.Interior, location.Exterior) &&
+		!im.isFalse(location.Exterior, location.Interior), nil
+}
+
+// boundsOverlap returns false if a or b is empty or if their bounds do not
+// overlap in x and y.
+func boundsOverlap(a, b geom.T) bool {
+	if a.Empty() || b.Empty() {
+		return false
+	}
+	return a.Bounds().Overlaps(geom.XY, b.Bounds())
+}
+
+// boundsContain returns false if a or b is empty or if the bounds of a do
+// not contain the bounds of b in x and y.
+func boundsContain(a, b geom.T) bool {
+	if a.Empty() || b.Empty() {
+		return false
+	}
+	boundsA, boundsB := a.Bounds(), b.Bounds()
+	for i := range 2 {
+		if boundsB.Min(i) < boundsA.Min(i) || boundsB.Max(i) > boundsA.Max(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkSupported returns an error if any of gs is of an unsupported type.
+func checkSupported(gs ...geom.T) error {
+	for _, g := range gs {
+		switch g := g.(type) {
+		case *geom.Point, *geom.MultiPoint, *geom.LineString, *geom.LinearRing, *geom.MultiLineString, *geom.Polygon, *geom.MultiPolygon:
+		case *geom.GeometryCollection:
+			if err := checkSupported(g.Geoms()...); err != nil {
+				return err
+			}
+		default:
+			return geom.ErrUnsupportedType{Value: g}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleContains() {
+	multiPolygon := geom.NewMultiPolygonFlat(geom.XY, []float64{
+		0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+		20, 0, 30, 0, 30, 10, 20, 10, 20, 0,
+	}, [][]int{{10}, {20}})
+	for _, point := range []*geom.Point{
+		geom.NewPointFlat(geom.XY, []float64{25, 5}),
+		geom.NewPointFlat(geom.XY, []float64{15, 5}),
+		geom.NewPointFlat(geom.XY, []float64{10, 5}),
+	} {
+		contains, err := xy.Contains(multiPolygon, point)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(point.Coords(), contains)
+	}
+	// Output:
+	// [25 5] true
+	// [15 5] false
+	// [10 5] false
+}
+
+func ExampleCrosses() {
+	polygon := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10})
+	line := geom.NewLineStringFlat(geom.XY, []float64{-5, 5, 5, 5})
+	crosses, err := xy.Crosses(line, polygon)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(crosses)
+	// Output: true
+}
@@ -0,0 +1,237 @@
+package xy_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestPredicates(t *testing.T) {
+	square := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10})
+	squareWithHole := geom.NewPolygonFlat(geom.XY, []float64{
+		0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+		4, 4, 4, 6, 6, 6, 6, 4, 4, 4,
+	}, []int{10, 20})
+	for _, tc := range []struct {
+		name       string
+		a, b       geom.T
+		intersects bool
+		contains   bool
+		within     bool
+		covers     bool
+		touches    bool
+		crosses    bool
+		overlaps   bool
+	}{
+		{
+			name:       "point_in_polygon",
+			a:          square,
+			b:          geom.NewPointFlat(geom.XY, []float64{5, 5}),
+			intersects: true,
+			contains:   true,
+			covers:     true,
+		},
+		{
+			name:       "point_on_polygon_boundary",
+			a:          square,
+			b:          geom.NewPointFlat(geom.XY, []float64{10, 5}),
+			intersects: true,
+			covers:     true,
+			touches:    true,
+		},
+		{
+			name: "point_in_hole",
+			a:    squareWithHole,
+			b:    geom.NewPointFlat(geom.XYZ, []float64{5, 5, 1}),
+		},
+		{
+			name: "point_outside_bounds",
+			a:    square,
+			b:    geom.NewPointFlat(geom.XY, []float64{20, 20}),
+		},
+		{
+			name:       "point_in_multipolygon",
+			a:          geom.NewMultiPolygonFlat(geom.XY, []float64{0, 0, 1, 0, 1, 1, 0, 0, 5, 5, 6, 5, 6, 6, 5, 5}, [][]int{{8}, {16}}),
+			b:          geom.NewPointFlat(geom.XY, []float64{5.8, 5.2}),
+			intersects: true,
+			contains:   true,
+			covers:     true,
+		},
+		{
+			name:       "polygon_within_polygon",
+			a:          geom.NewPolygonFlat(geom.XY, []float64{1, 1, 2, 1, 2, 2, 1, 1}, []int{8}),
+			b:          square,
+			intersects: true,
+			within:     true,
+		},
+		{
+			name:       "equal_polygons",
+			a:          square,
+			b:          geom.NewPolygonFlat(geom.XY, []float64{0, 0, 0, 10, 10, 10, 10, 0, 0, 0}, []int{10}),
+			intersects: true,
+			contains:   true,
+			within:     true,
+			covers:     true,
+		},
+		{
+			name:       "overlapping_polygons",
+			a:          square,
+			b:          geom.NewPolygonFlat(geom.XY, []float64{5, 5, 15, 5, 15, 15, 5, 15, 5, 5}, []int{10}),
+			intersects: true,
+			overlaps:   true,
+		},
+		{
+			name:       "touching_polygons",
+			a:          square,
+			b:          geom.NewPolygonFlat(geom.XY, []float64{10, 0, 20, 0, 20, 10, 10, 10, 10, 0}, []int{10}),
+			intersects: true,
+			touches:    true,
+		},
+		{
+			name:       "polygon_filling_hole",
+			a:          squareWithHole,
+			b:          geom.NewPolygonFlat(geom.XY, []float64{4, 4, 6, 4, 6, 6, 4, 6, 4, 4}, []int{10}),
+			intersects: true,
+			touches:    true,
+		},
+		{
+			name:       "line_crosses_polygon",
+			a:          geom.NewLineStringFlat(geom.XY, []float64{-5, 5, 15, 5}),
+			b:          square,
+			intersects: true,
+			crosses:    true,
+		},
+		{
+			name:       "line_in_polygon",
+			a:          geom.NewLineStringFlat(geom.XY, []float64{1, 1, 9, 9}),
+			b:          square,
+			intersects: true,
+			within:     true,
+		},
+		{
+			name:       "line_on_polygon_boundary",
+			a:          square,
+			b:          geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			intersects: true,
+			covers:     true,
+			touches:    true,
+		},
+		{
+			name:       "crossing_lines",
+			a:          geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2, 2}),
+			b:          geom.NewLineStringFlat(geom.XY, []float64{0, 2, 2, 0}),
+			intersects: true,
+			crosses:    true,
+		},
+		{
+			name:       "touching_lines",
+			a:          geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 1}),
+			b:          geom.NewLineStringFlat(geom.XY, []float64{1, 1, 2, 0}),
+			intersects: true,
+			touches:    true,
+		},
+		{
+			name:       "overlapping_lines",
+			a:          geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2, 0}),
+			b:          geom.NewLineStringFlat(geom.XY, []float64{1, 0, 3, 0}),
+			intersects: true,
+			overlaps:   true,
+		},
+		{
+			name: "parallel_lines",
+			a:    geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2, 0}),
+			b:    geom.NewLineStringFlat(geom.XY, []float64{0, 1, 2, 1}),
+		},
+		{
+			name:       "closed_line_contains_start_point",
+			a:          geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 0, 1, 1, 0, 0}),
+			b:          geom.NewPointFlat(geom.XY, []float64{0, 0}),
+			intersects: true,
+			contains:   true,
+			covers:     true,
+		},
+		{
+			name:       "multipoint_crosses_line",
+			a:          geom.NewMultiPointFlat(geom.XY, []float64{1, 0, 1, 1}),
+			b:          geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2, 0}),
+			intersects: true,
+			crosses:    true,
+		},
+		{
+			name:       "overlapping_multipoints",
+			a:          geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 1, 1}),
+			b:          geom.NewMultiPointFlat(geom.XY, []float64{1, 1, 2, 2}),
+			intersects: true,
+			overlaps:   true,
+		},
+		{
+			name: "geometrycollection",
+			a: geom.NewGeometryCollection().MustPush(
+				geom.NewPolygonFlat(geom.XY, []float64{0, 0, 5, 0, 5, 10, 0, 10, 0, 0}, []int{10}),
+				geom.NewPolygonFlat(geom.XY, []float64{5, 0, 10, 0, 10, 10, 5, 10, 5, 0}, []int{10}),
+			),
+			b:          geom.NewLineStringFlat(geom.XY, []float64{1, 5, 9, 5}),
+			intersects: true,
+			contains:   true,
+			covers:     true,
+		},
+		{
+			name: "empty",
+			a:    square,
+			b:    geom.NewGeometryCollection(),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, predicate := range []struct {
+				name     string
+				f        func(geom.T, geom.T) (bool, error)
+				expected bool
+			}{
+				{name: "Intersects", f: xy.Intersects, expected: tc.intersects},
+				{name: "Disjoint", f: xy.Disjoint, expected: !tc.intersects},
+				{name: "Contains", f: xy.Contains, expected: tc.contains},
+				{name: "Within", f: xy.Within, expected: tc.within},
+				{name: "Covers", f: xy.Covers, expected: tc.covers},
+				{name: "CoveredBy", f: xy.CoveredBy, expected: tc.within},
+				{name: "Touches", f: xy.Touches, expected: tc.touches},
+				{name: "Crosses", f: xy.Crosses, expected: tc.crosses},
+				{name: "Overlaps", f: xy.Overlaps, expected: tc.overlaps},
+			} {
+				got, err := predicate.f(tc.a, tc.b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != predicate.expected {
+					t.Errorf("expected %s to return %t but got %t", predicate.name, predicate.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPredicatesUnsupportedType(t *testing.T) {
+	point := geom.NewPointFlat(geom.XY, []float64{0, 0})
+	var errUnsupportedType geom.ErrUnsupportedType
+	for _, predicate := range []struct {
+		name string
+		f    func(geom.T, geom.T) (bool, error)
+	}{
+		{name: "Intersects", f: xy.Intersects},
+		{name: "Disjoint", f: xy.Disjoint},
+		{name: "Contains", f: xy.Contains},
+		{name: "Within", f: xy.Within},
+		{name: "Covers", f: xy.Covers},
+		{name: "CoveredBy", f: xy.CoveredBy},
+		{name: "Touches", f: xy.Touches},
+		{name: "Crosses", f: xy.Crosses},
+		{name: "Overlaps", f: xy.Overlaps},
+	} {
+		for _, operands := range [][2]geom.T{{point, nil}, {nil, point}, {nil, nil}} {
+			if _, err := predicate.f(operands[0], operands[1]); !errors.As(err, &errUnsupportedType) {
+				t.Errorf("%s(%v, %v): expected geom.ErrUnsupportedType, got %v", predicate.name, operands[0], operands[1], err)
+			}
+		}
+	}
+}
@@ -0,0 +1,470 @@
+package xy
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/rtree"
+	"github.com/twpayne/go-geom/xy/internal/robustdeterminate"
+	"github.com/twpayne/go-geom/xy/location"
+)
+
+// A PreparedGeometry is a geometry with cached indexes of its segments and
+// rings, so that testing many geometries against it with its Contains,
+// Covers, and Intersects methods is fast. Testing a point takes time
+// logarithmic in the number of segments of the prepared geometry, rather
+// than linear. A PreparedGeometry is safe for concurrent use.
+type PreparedGeometry struct {
+	g            geom.T
+	bounds       *geom.Bounds
+	err          error
+	polygonal    bool
+	components   distanceComponents
+	segments     *rtree.RTree[[4]float64]
+	polygons     [][]*edgeIntervalTree
+	polygonIndex *rtree.RTree[int]
+}
+
+// Prepare returns g prepared for repeated predicate tests. g is not copied
+// and must not be modified while the result is in use. If g is of an
+// unsupported type then the methods of the result return a
+// geom.ErrUnsupportedType error.
+func Prepare(g geom.T) *PreparedGeometry {
+	p := &PreparedGeometry{
+		g:   g,
+		err: checkSupported(g),
+	}
+	if p.err != nil {
+		return p
+	}
+	if !g.Empty() {
+		p.bounds = g.Bounds()
+	}
+	switch g.(type) {
+	case *geom.Polygon, *geom.MultiPolygon:
+		p.polygonal = true
+	}
+	p.components.add(g)
+
+	var segments []rtree.Item[[4]float64]
+	for _, line := range p.components.lines {
+		if len(line) == 2 {
+			segments = append(segments, rtree.Item[[4]float64]{
+				Bounds: geom.NewBounds(geom.XY).Set(line[0], line[1], line[0], line[1]),
+				Value:  [4]float64{line[0], line[1], line[0], line[1]},
+			})
+		}
+		for i := 2; i < len(line); i += 2 {
+			x0, y0, x1, y1 := line[i-2], line[i-1], line[i], line[i+1]
+			segments = append(segments, rtree.Item[[4]float64]{
+				Bounds: geom.NewBounds(geom.XY).Set(min(x0, x1), min(y0, y1), max(x0, x1), max(y0, y1)),
+				Value:  [4]float64{x0, y0, x1, y1},
+			})
+		}
+	}
+	p.segments = rtree.New(segments)
+
+	polygons := make([]rtree.Item[int], 0, len(p.components.polygons))
+	for i, rings := range p.components.polygons {
+		trees := make([]*edgeIntervalTree, 0, len(rings))
+		for _, ring := range rings {
+			trees = append(trees, newEdgeIntervalTree(ring))
+		}
+		p.polygons = append(p.polygons, trees)
+		box := ringBox(rings[0])
+		polygons = append(polygons, rtree.Item[int]{
+			Bounds: geom.NewBounds(geom.XY).Set(box.MinX, box.MinY, box.MaxX, box.MaxY),
+			Value:  i,
+		})
+	}
+	p.polygonIndex = rtree.New(polygons)
+	return p
+}
+
+// Geometry returns the geometry that p was prepared from.
+func (p *PreparedGeometry) Geometry() geom.T {
+	return p.g
+}
+
+// Intersects returns true if p and g have at least one point in common, as
+// for Intersects.
+func (p *PreparedGeometry) Intersects(g geom.T) (bool, error) {
+	if err := p.checkSupported(g); err != nil {
+		return false, err
+	}
+	if p.bounds == nil || g.Empty() || !p.bounds.Overlaps(geom.XY, g.Bounds()) {
+		return false, nil
+	}
+
+	var c distanceComponents
+	c.add(g)
+	for _, line := range c.lines {
+		if p.locate(line[0], line[1]) != location.Exterior {
+			return true, nil
+		}
+	}
+	if p.intersectsSegments(&c) {
+		return true, nil
+	}
+	// The boundaries do not intersect, so g can only intersect p if it
+	// contains a component of p.
+	return p.componentInPolygons(c.polygons), nil
+}
+
+// Contains returns true if no point of g lies in the exterior of p and at
+// least one point of the interior of g lies in the interior of p, as for
+// Contains.
+func (p *PreparedGeometry) Contains(g geom.T) (bool, error) {
+	if err := p.checkSupported(g); err != nil {
+		return false, err
+	}
+	if !p.boundsContain(g) {
+		return false, nil
+	}
+	if !p.polygonal {
+		return Contains(p.g, g)
+	}
+
+	var c distanceComponents
+	c.add(g)
+	switch g.(type) {
+	case *geom.Point, *geom.MultiPoint:
+		interior := false
+		for _, line := range c.lines {
+			switch p.locate(line[0], line[1]) {
+			case location.Exterior:
+				return false, nil
+			case location.Interior:
+				interior = true
+			}
+		}
+		return interior, nil
+	}
+	if result, ok := p.coversInterior(&c); ok {
+		return result, nil
+	}
+	return Contains(p.g, g)
+}
+
+// Covers returns true if no point of g lies in the exterior of p, as for
+// Covers.
+func (p *PreparedGeometry) Covers(g geom.T) (bool, error) {
+	if err := p.checkSupported(g); err != nil {
+		return false, err
+	}
+	if !p.boundsContain(g) {
+		return false, nil
+	}
+
+	var c distanceComponents
+	c.add(g)
+	switch g.(type) {
+	case *geom.Point, *geom.MultiPoint:
+		for _, line := range c.lines {
+			if p.locate(line[0], line[1]) == location.Exterior {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+	if p.polygonal {
+		if result, ok := p.coversInterior(&c); ok {
+			return result, nil
+		}
+	}
+	return Covers(p.g, g)
+}
+
+func (p *PreparedGeometry) checkSupported(g geom.T) error {
+	if p.err != nil {
+		return p.err
+	}
+	return checkSupported(g)
+}
+
+// boundsContain returns false if p or g is empty or if the bounds of p do
+// not contain the bounds of g in x and y.
+func (p *PreparedGeometry) boundsContain(g geom.T) bool {
+	if p.bounds == nil || g.Empty() {
+		return false
+	}
+	bounds := g.Bounds()
+	for i := range 2 {
+		if bounds.Min(i) < p.bounds.Min(i) || bounds.Max(i) > p.bounds.Max(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// coversInterior determines the result of Contains or Covers for a
+// polygonal p and the components c when it is simple to do so. It returns
+// false if a vertex of c is in the exterior of p, and true if c lies
+// entirely in the interior of p. Otherwise, c touches the boundary of p,
+// and it returns false for ok.
+func (p *PreparedGeometry) coversInterior(c *distanceComponents) (result, ok bool) {
+	onBoundary := false
+	for _, line := range c.lines {
+		for i := 0; i < len(line); i += 2 {
+			switch p.locate(line[i], line[i+1]) {
+			case location.Exterior:
+				return false, true
+			case location.Boundary:
+				onBoundary = true
+			}
+		}
+	}
+	if onBoundary || p.intersectsSegments(c) {
+		return false, false
+	}
+	// The vertices of c are all in the interior of p and do not cross its
+	// boundary, but a polygon of c might contain a hole of p.
+	if p.componentInPolygons(c.polygons) {
+		return false, false
+	}
+	return true, true
+}
+
+// componentInPolygons returns whether any component of p has a vertex
+// inside or on the boundary of any of polygons.
+func (p *PreparedGeometry) componentInPolygons(polygons [][][]float64) bool {
+	for _, rings := range polygons {
+		box := ringBox(rings[0])
+		for _, line := range p.components.lines {
+			x, y := line[0], line[1]
+			if x < box.MinX || x > box.MaxX || y < box.MinY || y > box.MaxY {
+				continue
+			}
+			if locatePointInPolygonRings(geom.Coord{x, y}, rings) != location.Exterior {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// locate returns the location of (x, y) relative to p. Points on a line or
+// at a point of p are on its boundary.
+func (p *PreparedGeometry) locate(x, y float64) location.Type {
+	loc := location.Exterior
+	bounds := geom.NewBounds(geom.XY).Set(x, y, x, y)
+	p.polygonIndex.Visit(bounds, func(_ *geom.Bounds, i int) bool {
+		switch locatePointInEdgeIntervalTrees(x, y, p.polygons[i]) {
+		case location.Interior:
+			loc = location.Interior
+			return false
+		case location.Boundary:
+			loc = location.Boundary
+		}
+		return true
+	})
+	if loc != location.Exterior || p.polygonal {
+		return loc
+	}
+	p.segments.Visit(bounds, func(_ *geom.Bounds, s [4]float64) bool {
+		if segmentsIntersect(x, y, x, y, s[0], s[1], s[2], s[3]) {
+			loc = location.Boundary
+			return false
+		}
+		return true
+	})
+	return loc
+}
+
+// intersectsSegments returns whether any segment of c intersects any segment
+// of p.
+func (p *PreparedGeometry) intersectsSegments(c *distanceComponents) bool {
+	for _, line := range c.lines {
+		for i := 2; i < len(line); i += 2 {
+			x0, y0, x1, y1 := line[i-2], line[i-1], line[i], line[i+1]
+			bounds := geom.NewBounds(geom.XY).Set(min(x0, x1), min(y0, y1), max(x0, x1), max(y0, y1))
+			if !p.segments.Visit(bounds, func(_ *geom.Bounds, s [4]float64) bool {
+				return !segmentsIntersect(x0, y0, x1, y1, s[0], s[1], s[2], s[3])
+			}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentsIntersect returns whether the segment from (ax, ay) to (bx, by)
+// and the segment from (cx, cy) to (dx, dy) have any point in common. Either
+// segment may have zero length.
+func segmentsIntersect(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	o1 := robustdeterminate.SignOfDet2x2(bx-ax, by-ay, cx-ax, cy-ay)
+	o2 := robustdeterminate.SignOfDet2x2(bx-ax, by-ay, dx-ax, dy-ay)
+	o3 := robustdeterminate.SignOfDet2x2(dx-cx, dy-cy, ax-cx, ay-cy)
+	o4 := robustdeterminate.SignOfDet2x2(dx-cx, dy-cy, bx-cx, by-cy)
+	if o1 == robustdeterminate.Zero && o2 == robustdeterminate.Zero &&
+		o3 == robustdeterminate.Zero && o4 == robustdeterminate.Zero {
+		// The segments are collinear, so they intersect if their bounds do.
+		return max(ax, bx) >= min(cx, dx) && max(cx, dx) >= min(ax, bx) &&
+			max(ay, by) >= min(cy, dy) && max(cy, dy) >= min(ay, by)
+	}
+	return o1*o2 <= 0 && o3*o4 <= 0
+}
+
+// An edgeIntervalTree is a centered interval tree of the edges of a ring,
+// indexed by their extent in y, for finding the edges that span a given y
+// in logarithmic time. Edges are identified by the index of their first
+// vertex.
+type edgeIntervalTree struct {
+	ring        []float64
+	center      float64
+	byMinY      []int
+	byMaxY      []int
+	left, right *edgeIntervalTree
+}
+
+// newEdgeIntervalTree returns a new edgeIntervalTree of the edges of ring,
+// which has layout geom.XY.
+func newEdgeIntervalTree(ring []float64) *edgeIntervalTree {
+	edges := make([]int, 0, len(ring)/2)
+	for i := 0; i+3 < len(ring); i += 2 {
+		edges = append(edges, i)
+	}
+	return newEdgeIntervalTreeNode(ring, edges)
+}
+
+func newEdgeIntervalTreeNode(ring []float64, edges []int) *edgeIntervalTree {
+	if len(edges) == 0 {
+		return nil
+	}
+	minY := func(i int) float64 { return min(ring[i+1], ring[i+3]) }
+	maxY := func(i int) float64 { return max(ring[i+1], ring[i+3]) }
+
+	// Center the node on the median of the midpoints of the edges, so that
+	// at least one edge spans it and the subtrees are balanced.
+	midpoints := make([]float64, 0, len(edges))
+	for _, i := range edges {
+		midpoints = append(midpoints, minY(i)/2+maxY(i)/2)
+	}
+	slices.Sort(midpoints)
+	t := &edgeIntervalTree{
+		ring:   ring,
+		center: midpoints[len(midpoints)/2],
+	}
+	var left, right []int
+	for _, i := range edges {
+		switch {
+		case maxY(i) < t.center:
+			left = append(left, i)
+		case minY(i) > t.center:
+			right = append(right, i)
+		default:
+			t.byMinY = append(t.byMinY, i)
+		}
+	}
+	t.byMaxY = slices.Clone(t.byMinY)
+	slices.SortFunc(t.byMinY, func(i, j int) int {
+		return cmp.Compare(minY(i), minY(j))
+	})
+	slices.SortFunc(t.byMaxY, func(i, j int) int {
+		return cmp.Compare(maxY(j), maxY(i))
+	})
+	t.left = newEdgeIntervalTreeNode(ring, left)
+	t.right = newEdgeIntervalTreeNode(ring, right)
+	return t
+}
+
+// forEachEdge calls f with each edge of t whose extent in y includes y,
+// until f returns false.
+func (t *edgeIntervalTree) forEachEdge(y float64, f func(i int) bool) {
+	for t != nil {
+		switch {
+		case y < t.center:
+			for _, i := range t.byMinY {
+				if min(t.ring[i+1], t.ring[i+3]) > y {
+					break
+				}
+				if !f(i) {
+					return
+				}
+			}
+			t = t.left
+		case y > t.center:
+			for _, i := range t.byMaxY {
+				if max(t.ring[i+1], t.ring[i+3]) < y {
+					break
+				}
+				if !f(i) {
+					return
+				}
+			}
+			t = t.right
+		default:
+			for _, i := range t.byMinY {
+				if !f(i) {
+					return
+				}
+			}
+			return
+		}
+	}
+}
+
+// locate returns the location of (x, y) relative to the ring of t, using
+// the same rules as LocatePointInRing.
+func (t *edgeIntervalTree) locate(x, y float64) location.Type {
+	crossings := 0
+	onBoundary := false
+	t.forEachEdge(y, func(i int) bool {
+		x0, y0, x1, y1 := t.ring[i], t.ring[i+1], t.ring[i+2], t.ring[i+3]
+		switch {
+		case x0 < x && x1 < x:
+			return true
+		case x0 == x && y0 == y || x1 == x && y1 == y:
+			onBoundary = true
+			return false
+		case y0 == y && y1 == y:
+			// The edge is horizontal and, as it is not entirely to the
+			// left, the point is on it if it is not entirely to the right.
+			onBoundary = min(x0, x1) <= x
+			return !onBoundary
+		case (y0 > y) == (y1 > y):
+			// The edge only touches the ray at an endpoint that is counted
+			// by the adjacent edge.
+			return true
+		}
+		sign := robustdeterminate.SignOfDet2x2(x1-x, y1-y, x0-x, y0-y)
+		if sign == robustdeterminate.Zero {
+			onBoundary = true
+			return false
+		}
+		if y0 < y1 {
+			sign = -sign
+		}
+		if sign > 0 {
+			crossings++
+		}
+		return true
+	})
+	switch {
+	case onBoundary:
+		return location.Boundary
+	case crossings%2 == 1:
+		return location.Interior
+	default:
+		return location.Exterior
+	}
+}
+
+// locatePointInEdgeIntervalTrees returns the location of (x, y) relative to
+// the polygon whose rings are indexed by rings.
+func locatePointInEdgeIntervalTrees(x, y float64, rings []*edgeIntervalTree) location.Type {
+	loc := rings[0].locate(x, y)
+	if loc != location.Interior {
+		return loc
+	}
+	for _, hole := range rings[1:] {
+		switch hole.locate(x, y) {
+		case location.Interior:
+			return location.Exterior
+		case location.Boundary:
+			return location.Boundary
+		}
+	}
+	return location.Interior
+}
@@ -0,0 +1,29 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExamplePrepare() {
+	area := geom.NewPolygonFlat(geom.XY, []float64{
+		0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+		4, 4, 4, 6, 6, 6, 6, 4, 4, 4,
+	}, []int{10, 20})
+	prepared := xy.Prepare(area)
+	for _, point := range []*geom.Point{
+		geom.NewPointFlat(geom.XY, []float64{1, 1}),
+		geom.NewPointFlat(geom.XY, []float64{5, 5}),
+		geom.NewPointFlat(geom.XY, []float64{10, 5}),
+	} {
+		contains, _ := prepared.Contains(point)
+		covers, _ := prepared.Covers(point)
+		fmt.Println(point.FlatCoords(), contains, covers)
+	}
+	// Output:
+	// [1 1] true true
+	// [5 5] false false
+	// [10 5] false true
+}
@@ -0,0 +1,173 @@
+package xy_test
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+	"github.com/twpayne/go-geom/xy/location"
+)
+
+func TestPreparedGeometry(t *testing.T) {
+	squareWithHole := geom.NewPolygonFlat(geom.XY, []float64{
+		0, 0, 10, 0, 10, 10, 0, 10, 0, 0,
+		4, 4, 4, 6, 6, 6, 6, 4, 4, 4,
+	}, []int{10, 20})
+	multiPolygon := geom.NewMultiPolygonFlat(geom.XY, []float64{
+		0, 0, 3, 0, 3, 3, 0, 3, 0, 0,
+		3, 3, 6, 3, 6, 6, 3, 6, 3, 3,
+	}, [][]int{{10}, {20}})
+	lineString := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 5, 5, 10, 0})
+	multiPoint := geom.NewMultiPointFlat(geom.XY, []float64{1, 1, 2, 2, 5, 5})
+	collection := geom.NewGeometryCollection().MustPush(
+		geom.NewPolygonFlat(geom.XY, []float64{0, 0, 4, 0, 4, 4, 0, 4, 0, 0}, []int{10}),
+		geom.NewLineStringFlat(geom.XY, []float64{4, 2, 8, 2}),
+		geom.NewPointFlat(geom.XY, []float64{9, 9}),
+	)
+
+	others := []geom.T{
+		geom.NewPointFlat(geom.XY, []float64{1, 1}),
+		geom.NewPointFlat(geom.XY, []float64{5, 5}),
+		geom.NewPointFlat(geom.XY, []float64{10, 5}),
+		geom.NewPointFlat(geom.XY, []float64{3, 3}),
+		geom.NewPointFlat(geom.XY, []float64{9, 9}),
+		geom.NewPointFlat(geom.XY, []float64{20, 20}),
+		geom.NewPointFlat(geom.XY, []float64{6, 2}),
+		geom.NewMultiPointFlat(geom.XY, []float64{1, 1, 10, 5}),
+		geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 10, 10}),
+		geom.NewMultiPointFlat(geom.XY, []float64{1, 1, 5, 5}),
+		geom.NewLineStringFlat(geom.XY, []float64{1, 1, 2, 2}),
+		geom.NewLineStringFlat(geom.XY, []float64{1, 1, 9, 1}),
+		geom.NewLineStringFlat(geom.XY, []float64{1, 1, 5, 5}),
+		geom.NewLineStringFlat(geom.XY, []float64{1, 5, 9, 5}),
+		geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+		geom.NewLineStringFlat(geom.XY, []float64{-1, 5, 11, 5}),
+		geom.NewLineStringFlat(geom.XY, []float64{4.5, 4.5, 5.5, 5.5}),
+		geom.NewLineStringFlat(geom.XY, []float64{5, 2, 7, 2}),
+		geom.NewLineStringFlat(geom.XY, []float64{1, 9, 2, 9}),
+		geom.NewPolygonFlat(geom.XY, []float64{1, 1, 2, 1, 2, 2, 1, 2, 1, 1}, []int{10}),
+		geom.NewPolygonFlat(geom.XY, []float64{1, 1, 9, 1, 9, 9, 1, 9, 1, 1}, []int{10}),
+		geom.NewPolygonFlat(geom.XY, []float64{4.5, 4.5, 5.5, 4.5, 5.5, 5.5, 4.5, 5.5, 4.5, 4.5}, []int{10}),
+		geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}),
+		geom.NewPolygonFlat(geom.XY, []float64{-1, -1, 11, -1, 11, 11, -1, 11, -1, -1}, []int{10}),
+		geom.NewPolygonFlat(geom.XY, []float64{1, 1, 4, 1, 4, 4, 1, 4, 1, 1}, []int{10}),
+		geom.NewPolygonFlat(geom.XY, []float64{20, 20, 21, 20, 21, 21, 20, 20}, []int{8}),
+		geom.NewMultiPolygonFlat(geom.XY, []float64{
+			1, 1, 2, 1, 2, 2, 1, 1,
+			8, 8, 9, 8, 9, 9, 8, 8,
+		}, [][]int{{8}, {16}}),
+		geom.NewGeometryCollection().MustPush(
+			geom.NewPointFlat(geom.XY, []float64{1, 1}),
+			geom.NewLineStringFlat(geom.XY, []float64{2, 2, 3, 2.5}),
+		),
+		geom.NewPointEmpty(geom.XY),
+	}
+
+	for _, tc := range []struct {
+		name string
+		g    geom.T
+	}{
+		{name: "polygon_with_hole", g: squareWithHole},
+		{name: "multi_polygon", g: multiPolygon},
+		{name: "line_string", g: lineString},
+		{name: "multi_point", g: multiPoint},
+		{name: "geometry_collection", g: collection},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			prepared := xy.Prepare(tc.g)
+			if prepared.Geometry() != tc.g {
+				t.Errorf("expected %v, got %v", tc.g, prepared.Geometry())
+			}
+			for i, other := range others {
+				for _, predicate := range []struct {
+					name     string
+					prepared func(geom.T) (bool, error)
+					expected func(geom.T, geom.T) (bool, error)
+				}{
+					{name: "Intersects", prepared: prepared.Intersects, expected: xy.Intersects},
+					{name: "Contains", prepared: prepared.Contains, expected: xy.Contains},
+					{name: "Covers", prepared: prepared.Covers, expected: xy.Covers},
+				} {
+					expected, err := predicate.expected(tc.g, other)
+					if err != nil {
+						t.Fatalf("%d: %s: expected no error, got %v", i, predicate.name, err)
+					}
+					got, err := predicate.prepared(other)
+					if err != nil {
+						t.Fatalf("%d: %s: expected no error, got %v", i, predicate.name, err)
+					}
+					if got != expected {
+						t.Errorf("%d: %s: expected %t, got %t", i, predicate.name, expected, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPreparedGeometryManyPoints(t *testing.T) {
+	// A star with many spikes and a hole, so that many points are near the
+	// boundary.
+	var shell, hole []float64
+	for i := range 1000 {
+		angle := 2 * math.Pi * float64(i) / 1000
+		radius := 10.0
+		if i%2 == 1 {
+			radius = 9
+		}
+		shell = append(shell, radius*math.Cos(angle), radius*math.Sin(angle))
+		hole = append(hole, 5*math.Cos(-angle), 5*math.Sin(-angle))
+	}
+	shell = append(shell, shell[0], shell[1])
+	hole = append(hole, hole[0], hole[1])
+	flatCoords := slices.Concat(shell, hole)
+	polygon := geom.NewPolygonFlat(geom.XY, flatCoords, []int{len(shell), len(flatCoords)})
+	prepared := xy.Prepare(polygon)
+
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range 10000 {
+		var point *geom.Point
+		if i%10 == 0 {
+			// Choose a vertex.
+			j := 2 * r.IntN(len(flatCoords)/2)
+			point = geom.NewPointFlat(geom.XY, flatCoords[j:j+2])
+		} else {
+			point = geom.NewPointFlat(geom.XY, []float64{22*r.Float64() - 11, 22*r.Float64() - 11})
+		}
+		loc := xy.LocatePointInRing(geom.XY, point.Coords(), shell)
+		if holeLoc := xy.LocatePointInRing(geom.XY, point.Coords(), hole); holeLoc == location.Boundary {
+			loc = location.Boundary
+		} else if holeLoc == location.Interior {
+			loc = location.Exterior
+		}
+		if got, _ := prepared.Covers(point); got != (loc != location.Exterior) {
+			t.Fatalf("%v: expected Covers %t, got %t", point.FlatCoords(), loc != location.Exterior, got)
+		}
+		if got, _ := prepared.Contains(point); got != (loc == location.Interior) {
+			t.Fatalf("%v: expected Contains %t, got %t", point.FlatCoords(), loc == location.Interior, got)
+		}
+	}
+}
+
+func TestPreparedGeometryUnsupportedType(t *testing.T) {
+	point := geom.NewPointFlat(geom.XY, []float64{0, 0})
+	var errUnsupportedType geom.ErrUnsupportedType
+	prepared := xy.Prepare(nil)
+	if _, err := prepared.Intersects(point); !errors.As(err, &errUnsupportedType) {
+		t.Errorf("expected geom.ErrUnsupportedType, got %v", err)
+	}
+	prepared = xy.Prepare(point)
+	for _, f := range []func(geom.T) (bool, error){
+		prepared.Intersects,
+		prepared.Contains,
+		prepared.Covers,
+	} {
+		if _, err := f(nil); !errors.As(err, &errUnsupportedType) {
+			t.Errorf("expected geom.ErrUnsupportedType, got %v", err)
+		}
+	}
+}
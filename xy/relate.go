@@ -0,0 +1,260 @@
+package xy
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal/planargraph"
+	"github.com/twpayne/go-geom/xy/location"
+)
+
+// dimFalse is the dimension of an empty intersection.
+const dimFalse = -1
+
+// An IntersectionMatrix is a DE-9IM intersection matrix. Each entry is the
+// dimension of the intersection of a location of the first geometry with a
+// location of the second geometry, indexed by location.Interior,
+// location.Boundary, and location.Exterior. The dimension is 0 for points, 1
+// for lines, 2 for areas, and -1 if the intersection is empty.
+type IntersectionMatrix [3][3]int
+
+// An ErrInvalidPattern is returned when a DE-9IM pattern is invalid.
+type ErrInvalidPattern string
+
+func (e ErrInvalidPattern) Error() string {
+	return fmt.Sprintf("xy: invalid DE-9IM pattern %q", string(e))
+}
+
+// Relate returns the DE-9IM intersection matrix of a and b. Only the x and y
+// ordinates are considered. The elements of GeometryCollections are
+// unioned, and the boundary of lines is determined with the mod-2 rule, so
+// the endpoints of closed lines are in their interior.
+func Relate(a, b geom.T) (IntersectionMatrix, error) {
+	var im IntersectionMatrix
+	for i := range im {
+		for j := range im[i] {
+			im[i][j] = dimFalse
+		}
+	}
+	im[location.Exterior][location.Exterior] = 2
+
+	builder := planargraph.NewBuilder(2)
+	for operand, g := range [2]geom.T{a, b} {
+		if err := addToBuilder(builder, operand, g); err != nil {
+			return im, err
+		}
+	}
+	graph := builder.Build()
+
+	sideLocation := func(inside bool) location.Type {
+		if inside {
+			return location.Interior
+		}
+		return location.Exterior
+	}
+	for _, e := range graph.Edges {
+		im.update(edgeLocation(e, 0), edgeLocation(e, 1), 1)
+		im.update(sideLocation(e.Left[0]), sideLocation(e.Left[1]), 2)
+		im.update(sideLocation(e.Right[0]), sideLocation(e.Right[1]), 2)
+	}
+	for _, n := range graph.Nodes {
+		var inside []bool
+		if len(n.Edges) == 0 {
+			inside = graph.Inside(n.X, n.Y)
+		}
+		im.update(nodeLocation(n, 0, inside), nodeLocation(n, 1, inside), 0)
+	}
+	return im, nil
+}
+
+// Get returns the dimension of the intersection of location a of the first
+// geometry with location b of the second geometry.
+func (im IntersectionMatrix) Get(a, b location.Type) int {
+	return im[a][b]
+}
+
+// Matches returns true if im matches pattern. pattern is a string of nine
+// symbols, one for each entry in row-major order, where 'T' matches any
+// non-empty intersection, 'F' matches an empty intersection, '0', '1', and
+// '2' match intersections of that dimension, and '*' matches anything.
+// Symbols are case insensitive.
+func (im IntersectionMatrix) Matches(pattern string) (bool, error) {
+	if len(pattern) != 9 {
+		return false, ErrInvalidPattern(pattern)
+	}
+	matches := true
+	for i := range 9 {
+		dim := im[i/3][i%3]
+		switch pattern[i] {
+		case 'T', 't':
+			matches = matches && dim != dimFalse
+		case 'F', 'f':
+			matches = matches && dim == dimFalse
+		case '0', '1', '2':
+			matches = matches && dim == int(pattern[i]-'0')
+		case '*':
+		default:
+			return false, ErrInvalidPattern(pattern)
+		}
+	}
+	return matches, nil
+}
+
+// String returns im as a string of nine symbols in row-major order, for
+// example "212101212", where 'F' denotes an empty intersection.
+func (im IntersectionMatrix) String() string {
+	var sb strings.Builder
+	for i := range im {
+		for _, dim := range im[i] {
+			if dim == dimFalse {
+				sb.WriteByte('F')
+			} else {
+				sb.WriteByte(byte('0' + dim))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Transpose returns the intersection matrix with the geometries swapped.
+func (im IntersectionMatrix) Transpose() IntersectionMatrix {
+	var result IntersectionMatrix
+	for i := range im {
+		for j := range im[i] {
+			result[j][i] = im[i][j]
+		}
+	}
+	return result
+}
+
+func (im *IntersectionMatrix) update(a, b location.Type, dim int) {
+	im[a][b] = max(im[a][b], dim)
+}
+
+func (im *IntersectionMatrix) isFalse(a, b location.Type) bool {
+	return im[a][b] == dimFalse
+}
+
+// addToBuilder adds the linework of g to operand of b.
+func addToBuilder(b *planargraph.Builder, operand int, g geom.T) error {
+	stride := g.Stride()
+	switch g := g.(type) {
+	case *geom.Point:
+		if !g.Empty() {
+			b.AddPoint(operand, g.X(), g.Y())
+		}
+	case *geom.MultiPoint:
+		flatCoords := g.FlatCoords()
+		for i := 0; i < len(flatCoords); i += stride {
+			if !math.IsNaN(flatCoords[i]) {
+				b.AddPoint(operand, flatCoords[i], flatCoords[i+1])
+			}
+		}
+	case *geom.LineString:
+		addLineToBuilder(b, operand, xyCoords(g.FlatCoords(), stride))
+	case *geom.LinearRing:
+		addLineToBuilder(b, operand, xyCoords(g.FlatCoords(), stride))
+	case *geom.MultiLineString:
+		for i := range g.NumLineStrings() {
+			addLineToBuilder(b, operand, xyCoords(g.LineString(i).FlatCoords(), stride))
+		}
+	case *geom.Polygon:
+		for i := range g.NumLinearRings() {
+			b.AddRing(operand, xyCoords(g.LinearRing(i).FlatCoords(), stride))
+		}
+	case *geom.MultiPolygon:
+		for i := range g.NumPolygons() {
+			if err := addToBuilder(b, operand, g.Polygon(i)); err != nil {
+				return err
+			}
+		}
+	case *geom.GeometryCollection:
+		for _, child := range g.Geoms() {
+			if err := addToBuilder(b, operand, child); err != nil {
+				return err
+			}
+		}
+	default:
+		return geom.ErrUnsupportedType{Value: g}
+	}
+	return nil
+}
+
+// addLineToBuilder adds line to operand of b, treating lines that consist of
+// a single point as points.
+func addLineToBuilder(b *planargraph.Builder, operand int, line []float64) {
+	switch {
+	case len(line) == 2:
+		b.AddPoint(operand, line[0], line[1])
+	case len(line) > 2:
+		b.AddLine(operand, line)
+	}
+}
+
+// edgeLocation returns the location of the interior of e relative to
+// operand.
+func edgeLocation(e *planargraph.Edge, operand int) location.Type {
+	switch {
+	case e.Left[operand] && e.Right[operand]:
+		return location.Interior
+	case e.Left[operand] != e.Right[operand]:
+		return location.Boundary
+	case e.LineCount[operand] > 0:
+		return location.Interior
+	default:
+		return location.Exterior
+	}
+}
+
+// nodeLocation returns the location of n relative to operand. inside, if
+// not nil, is whether n, which must not have any edges, is inside each
+// operand's area.
+func nodeLocation(n *planargraph.Node, operand int, inside []bool) location.Type {
+	var areaInside, areaOutside, onLine bool
+	for _, e := range n.Edges {
+		areaInside = areaInside || e.Left[operand] || e.Right[operand]
+		areaOutside = areaOutside || !e.Left[operand] || !e.Right[operand]
+		onLine = onLine || e.LineCount[operand] > 0
+	}
+	if inside != nil {
+		areaInside, areaOutside = inside[operand], !inside[operand]
+	}
+	switch {
+	case areaInside && !areaOutside:
+		return location.Interior
+	case areaInside && areaOutside:
+		return location.Boundary
+	case n.LineEndCount[operand]%2 == 1:
+		return location.Boundary
+	case onLine || n.PointCount[operand] > 0:
+		return location.Interior
+	default:
+		return location.Exterior
+	}
+}
+
+// dimension returns the topological dimension of g: 0 for points, 1 for
+// lines, 2 for areas, and -1 for empty geometries.
+func dimension(g geom.T) int {
+	if g.Empty() {
+		return dimFalse
+	}
+	switch g := g.(type) {
+	case *geom.Point, *geom.MultiPoint:
+		return 0
+	case *geom.LineString, *geom.LinearRing, *geom.MultiLineString:
+		return 1
+	case *geom.Polygon, *geom.MultiPolygon:
+		return 2
+	case *geom.GeometryCollection:
+		dim := dimFalse
+		for _, child := range g.Geoms() {
+			dim = max(dim, dimension(child))
+		}
+		return dim
+	default:
+		return dimFalse
+	}
+}
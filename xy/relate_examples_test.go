@@ -0,0 +1,23 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleRelate() {
+	a := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10})
+	b := geom.NewPolygonFlat(geom.XY, []float64{5, 5, 15, 5, 15, 15, 5, 15, 5, 5}, []int{10})
+	im, err := xy.Relate(a, b)
+	if err != nil {
+		panic(err)
+	}
+	overlaps, err := im.Matches("T*T***T**")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(im, overlaps)
+	// Output: 212101212 true
+}
@@ -0,0 +1,152 @@
+package xy_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+	"github.com/twpayne/go-geom/xy/location"
+)
+
+func TestRelate(t *testing.T) {
+	square := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10})
+	for _, tc := range []struct {
+		name     string
+		a, b     geom.T
+		expected string
+	}{
+		{
+			name:     "point_in_polygon",
+			a:        square,
+			b:        geom.NewPointFlat(geom.XY, []float64{5, 5}),
+			expected: "0F2FF1FF2",
+		},
+		{
+			name:     "point_on_polygon_boundary",
+			a:        square,
+			b:        geom.NewPointFlat(geom.XY, []float64{0, 5}),
+			expected: "FF20F1FF2",
+		},
+		{
+			name:     "equal_points",
+			a:        geom.NewPointFlat(geom.XY, []float64{1, 2}),
+			b:        geom.NewPointFlat(geom.XYZ, []float64{1, 2, 3}),
+			expected: "0FFFFFFF2",
+		},
+		{
+			name:     "disjoint_points",
+			a:        geom.NewPointFlat(geom.XY, []float64{1, 2}),
+			b:        geom.NewPointFlat(geom.XY, []float64{3, 4}),
+			expected: "FF0FFF0F2",
+		},
+		{
+			name:     "crossing_lines",
+			a:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2, 2}),
+			b:        geom.NewLineStringFlat(geom.XY, []float64{0, 2, 2, 0}),
+			expected: "0F1FF0102",
+		},
+		{
+			name:     "line_crosses_polygon",
+			a:        geom.NewLineStringFlat(geom.XY, []float64{-5, 5, 15, 5}),
+			b:        square,
+			expected: "101FF0212",
+		},
+		{
+			name:     "line_in_polygon_touching_boundary",
+			a:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 5, 5}),
+			b:        square,
+			expected: "1FF00F212",
+		},
+		{
+			name:     "overlapping_polygons",
+			a:        square,
+			b:        geom.NewPolygonFlat(geom.XY, []float64{5, 5, 15, 5, 15, 15, 5, 15, 5, 5}, []int{10}),
+			expected: "212101212",
+		},
+		{
+			name:     "touching_polygons",
+			a:        square,
+			b:        geom.NewPolygonFlat(geom.XY, []float64{10, 0, 20, 0, 20, 10, 10, 10, 10, 0}, []int{10}),
+			expected: "FF2F11212",
+		},
+		{
+			name:     "polygons_touching_at_point",
+			a:        square,
+			b:        geom.NewPolygonFlat(geom.XY, []float64{10, 10, 20, 10, 20, 20, 10, 10}, []int{8}),
+			expected: "FF2F01212",
+		},
+		{
+			name:     "equal_polygons",
+			a:        square,
+			b:        square,
+			expected: "2FFF1FFF2",
+		},
+		{
+			name:     "empty",
+			a:        square,
+			b:        geom.NewPolygon(geom.XY),
+			expected: "FF2FF1FF2",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			im, err := xy.Relate(tc.a, tc.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := im.String(); got != tc.expected {
+				t.Errorf("expected %s but got %s", tc.expected, got)
+			}
+			transposed, err := xy.Relate(tc.b, tc.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if transposed != im.Transpose() {
+				t.Errorf("expected %s but got %s", im.Transpose(), transposed)
+			}
+		})
+	}
+}
+
+func TestIntersectionMatrixMatches(t *testing.T) {
+	im, err := xy.Relate(
+		geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}),
+		geom.NewLineStringFlat(geom.XY, []float64{1, 1, 2, 2}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := im.Get(location.Interior, location.Boundary); got != 0 {
+		t.Errorf("expected 0 but got %d", got)
+	}
+	for _, tc := range []struct {
+		pattern  string
+		expected bool
+	}{
+		{pattern: "*********", expected: true},
+		{pattern: "T*****FF*", expected: true},
+		{pattern: "t*****ff*", expected: true},
+		{pattern: "102FF1FF2", expected: true},
+		{pattern: "1*F**F***", expected: false},
+		{pattern: "2********", expected: false},
+		{pattern: "F********", expected: false},
+	} {
+		t.Run(tc.pattern, func(t *testing.T) {
+			got, err := im.Matches(tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %t but got %t", tc.expected, got)
+			}
+		})
+	}
+	for _, pattern := range []string{"", "T*****FF", "T*****FF**", "T*****FX*"} {
+		t.Run(pattern, func(t *testing.T) {
+			var invalidPatternErr xy.ErrInvalidPattern
+			if _, err := im.Matches(pattern); !errors.As(err, &invalidPatternErr) {
+				t.Errorf("expected ErrInvalidPattern but got %v", err)
+			}
+		})
+	}
+}
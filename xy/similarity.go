@@ -0,0 +1,151 @@
+package xy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/twpayne/go-geom"
+)
+
+// An ErrInvalidDensifyFraction is returned when a densify fraction is not in
+// the range [0, 1].
+type ErrInvalidDensifyFraction float64
+
+func (e ErrInvalidDensifyFraction) Error() string {
+	return fmt.Sprintf("xy: invalid densify fraction %v", float64(e))
+}
+
+// The similarity measures in this file accept *geom.LineStrings and
+// *geom.MultiLineStrings and return a geom.ErrUnsupportedType error for
+// other types. They are computed from the x and y ordinates only. If either
+// geometry is empty then the result is +Inf.
+
+// HausdorffDistance returns the discrete Hausdorff distance between a and b,
+// the greatest distance from a vertex of one to the nearest point of the
+// other. If densifyFraction is not zero then each segment is divided into
+// approximately 1/densifyFraction equal parts whose endpoints are also used
+// as vertices, which gives a closer approximation to the exact Hausdorff
+// distance. densifyFraction must be in the range [0, 1].
+func HausdorffDistance(a, b geom.T, densifyFraction float64) (float64, error) {
+	if !(densifyFraction >= 0 && densifyFraction <= 1) {
+		return 0, ErrInvalidDensifyFraction(densifyFraction)
+	}
+	linesA, err := similarityLines(a)
+	if err != nil {
+		return 0, err
+	}
+	linesB, err := similarityLines(b)
+	if err != nil {
+		return 0, err
+	}
+	if len(linesA) == 0 || len(linesB) == 0 {
+		return math.Inf(1), nil
+	}
+	segments := 1
+	if densifyFraction != 0 {
+		segments = max(int(math.Round(1/densifyFraction)), 1)
+	}
+	return max(directedHausdorffDistance(linesA, linesB, segments), directedHausdorffDistance(linesB, linesA, segments)), nil
+}
+
+// FrechetDistance returns the discrete Fréchet distance between a and b,
+// the shortest leash that connects a walk along the vertices of a to a walk
+// along the vertices of b, where both walks only move forwards. The lines of
+// a *geom.MultiLineString are walked in order.
+func FrechetDistance(a, b geom.T) (float64, error) {
+	linesA, err := similarityLines(a)
+	if err != nil {
+		return 0, err
+	}
+	linesB, err := similarityLines(b)
+	if err != nil {
+		return 0, err
+	}
+	var coordsA, coordsB []float64
+	for _, line := range linesA {
+		coordsA = append(coordsA, line...)
+	}
+	for _, line := range linesB {
+		coordsB = append(coordsB, line...)
+	}
+	if len(coordsA) == 0 || len(coordsB) == 0 {
+		return math.Inf(1), nil
+	}
+
+	// prev[j] and curr[j] are the leash lengths needed to reach vertex j of
+	// b at the previous and current vertices of a.
+	n := len(coordsB) / 2
+	prev := make([]float64, n)
+	curr := make([]float64, n)
+	for i := 0; i < len(coordsA); i += 2 {
+		for j := range n {
+			d := math.Hypot(coordsB[2*j]-coordsA[i], coordsB[2*j+1]-coordsA[i+1])
+			switch {
+			case i == 0 && j == 0:
+				curr[j] = d
+			case i == 0:
+				curr[j] = max(d, curr[j-1])
+			case j == 0:
+				curr[j] = max(d, prev[j])
+			default:
+				curr[j] = max(d, min(prev[j], prev[j-1], curr[j-1]))
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[n-1], nil
+}
+
+// directedHausdorffDistance returns the greatest distance from a vertex of
+// linesA, with each segment divided into segments parts, to the nearest
+// point of linesB.
+func directedHausdorffDistance(linesA, linesB [][]float64, segments int) float64 {
+	var distance float64
+	for _, line := range linesA {
+		for i := 0; i < len(line); i += 2 {
+			distance = max(distance, distanceToLines(line[i], line[i+1], linesB))
+			if i+2 == len(line) {
+				break
+			}
+			x0, y0, x1, y1 := line[i], line[i+1], line[i+2], line[i+3]
+			for k := 1; k < segments; k++ {
+				t := float64(k) / float64(segments)
+				distance = max(distance, distanceToLines(x0+t*(x1-x0), y0+t*(y1-y0), linesB))
+			}
+		}
+	}
+	return distance
+}
+
+// distanceToLines returns the distance from (x, y) to the nearest point of
+// lines.
+func distanceToLines(x, y float64, lines [][]float64) float64 {
+	nearest := nearestPair{distance: math.Inf(1)}
+	point := []float64{x, y}
+	for _, line := range lines {
+		nearest.lines(point, line)
+	}
+	return nearest.distance
+}
+
+// similarityLines returns the non-empty lines of g as xy coordinates
+// without repeated points.
+func similarityLines(g geom.T) ([][]float64, error) {
+	var lines [][]float64
+	switch g := g.(type) {
+	case *geom.LineString:
+		if line := xyCoords(g.FlatCoords(), g.Stride()); len(line) != 0 {
+			lines = append(lines, line)
+		}
+	case *geom.MultiLineString:
+		for i := range g.NumLineStrings() {
+			ls := g.LineString(i)
+			if line := xyCoords(ls.FlatCoords(), ls.Stride()); len(line) != 0 {
+				lines = append(lines, line)
+			}
+		}
+	default:
+		return nil, geom.ErrUnsupportedType{Value: g}
+	}
+	return lines, nil
+}
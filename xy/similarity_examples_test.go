@@ -0,0 +1,23 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleFrechetDistance() {
+	planned := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 100, 0})
+	recorded := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 50, 50, 100, 0})
+	hausdorff, err := xy.HausdorffDistance(planned, recorded, 0)
+	if err != nil {
+		panic(err)
+	}
+	frechet, err := xy.FrechetDistance(planned, recorded)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%.2f %.2f\n", hausdorff, frechet)
+	// Output: 50.00 70.71
+}
@@ -0,0 +1,132 @@
+package xy_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestHausdorffDistance(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		a, b            geom.T
+		densifyFraction float64
+		expected        float64
+	}{
+		{
+			name:     "parallel",
+			a:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			b:        geom.NewLineStringFlat(geom.XYZ, []float64{0, 1, 5, 10, 1, 5}),
+			expected: 1,
+		},
+		{
+			name:     "peak",
+			a:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 100, 0}),
+			b:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 50, 50, 100, 0}),
+			expected: 50,
+		},
+		{
+			name:     "discreteness",
+			a:        geom.NewLineStringFlat(geom.XY, []float64{130, 0, 0, 0, 0, 150}),
+			b:        geom.NewLineStringFlat(geom.XY, []float64{10, 10, 10, 150, 130, 10}),
+			expected: 14.142135623730951,
+		},
+		{
+			name:            "densified",
+			a:               geom.NewLineStringFlat(geom.XY, []float64{130, 0, 0, 0, 0, 150}),
+			b:               geom.NewLineStringFlat(geom.XY, []float64{10, 10, 10, 150, 130, 10}),
+			densifyFraction: 0.5,
+			expected:        70,
+		},
+		{
+			name:     "multilinestring",
+			a:        geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 0, 5, 10, 5}, []int{4, 8}),
+			b:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			expected: 5,
+		},
+		{
+			name:     "empty",
+			a:        geom.NewLineString(geom.XY),
+			b:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			expected: math.Inf(1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := xy.HausdorffDistance(tc.a, tc.b, tc.densifyFraction)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestHausdorffDistanceErrors(t *testing.T) {
+	line := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0})
+
+	var unsupportedTypeErr geom.ErrUnsupportedType
+	if _, err := xy.HausdorffDistance(line, geom.NewPointFlat(geom.XY, []float64{0, 0}), 0); !errors.As(err, &unsupportedTypeErr) {
+		t.Errorf("expected a geom.ErrUnsupportedType but got %v", err)
+	}
+
+	for _, densifyFraction := range []float64{-0.5, 1.5, math.NaN()} {
+		var invalidDensifyFractionErr xy.ErrInvalidDensifyFraction
+		if _, err := xy.HausdorffDistance(line, line, densifyFraction); !errors.As(err, &invalidDensifyFractionErr) {
+			t.Errorf("expected an xy.ErrInvalidDensifyFraction for %v but got %v", densifyFraction, err)
+		}
+	}
+}
+
+func TestFrechetDistance(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		a, b     geom.T
+		expected float64
+	}{
+		{
+			name:     "identical",
+			a:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10}),
+			b:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10}),
+			expected: 0,
+		},
+		{
+			name:     "peak",
+			a:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 100, 0}),
+			b:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 50, 50, 100, 0}),
+			expected: 70.71067811865476,
+		},
+		{
+			name:     "reversed",
+			a:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			b:        geom.NewLineStringFlat(geom.XY, []float64{10, 0, 0, 0}),
+			expected: 10,
+		},
+		{
+			name:     "multilinestring",
+			a:        geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 5, 0, 5, 0, 10, 0}, []int{4, 8}),
+			b:        geom.NewLineStringFlat(geom.XYM, []float64{0, 1, 0, 10, 1, 0}),
+			expected: 5.099019513592785,
+		},
+		{
+			name:     "empty",
+			a:        geom.NewMultiLineString(geom.XY),
+			b:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0}),
+			expected: math.Inf(1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := xy.FrechetDistance(tc.a, tc.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,577 @@
+package xy
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal"
+	"github.com/twpayne/go-geom/xy/internal/robustdeterminate"
+	"github.com/twpayne/go-geom/xy/location"
+)
+
+// A SimplifyAlgorithm is an algorithm for simplifying lines.
+type SimplifyAlgorithm int
+
+const (
+	// SimplifyDouglasPeucker keeps the vertices that are further than the
+	// tolerance from the line between the vertices kept on either side of
+	// them, using the Ramer-Douglas-Peucker algorithm.
+	SimplifyDouglasPeucker SimplifyAlgorithm = iota
+	// SimplifyVisvalingamWhyatt repeatedly removes the vertex that forms the
+	// triangle of least area with its neighbors, while that area is less
+	// than the square of the tolerance.
+	SimplifyVisvalingamWhyatt
+)
+
+// Simplify returns a copy of g, with the layout and SRID of g, in which each
+// line and ring is simplified independently using algorithm with tolerance.
+// The first and last points of each line and ring are always kept, and
+// points are unchanged. Rings that collapse to fewer than four points are
+// removed, as are polygons whose exterior rings collapse, so the result may
+// be empty. The result may be invalid even if g is valid, as rings may
+// intersect themselves or each other and holes may move outside their
+// shells. Use SimplifyPreserveTopology to prevent this.
+func Simplify(g geom.T, tolerance float64, algorithm SimplifyAlgorithm) (geom.T, error) {
+	return simplify(g, tolerance, algorithm, false)
+}
+
+// SimplifyPreserveTopology is like Simplify, except that a vertex is never
+// removed if that would make a line or ring intersect itself or any other
+// line or ring of g, or move a line or ring across a vertex of another.
+// Rings always keep at least four points, so they never collapse, and holes
+// remain inside their shells. The lines and rings of g are simplified in
+// order, each taking into account those already simplified, so the result
+// is valid if g is valid.
+func SimplifyPreserveTopology(g geom.T, tolerance float64, algorithm SimplifyAlgorithm) (geom.T, error) {
+	return simplify(g, tolerance, algorithm, true)
+}
+
+func simplify(g geom.T, tolerance float64, algorithm SimplifyAlgorithm, preserveTopology bool) (geom.T, error) {
+	s := &simplifier{}
+	if err := s.add(g); err != nil {
+		return nil, err
+	}
+	if preserveTopology {
+		s.index = newSimplifierIndex(s.lines)
+	}
+	tolerance2 := max(tolerance, 0) * max(tolerance, 0)
+	for _, l := range s.lines {
+		switch algorithm {
+		case SimplifyVisvalingamWhyatt:
+			s.visvalingamWhyatt(l, tolerance2)
+		default:
+			s.douglasPeucker(l, tolerance2)
+		}
+	}
+	next := 0
+	return s.build(g, &next), nil
+}
+
+// A simplifier simplifies the lines and rings of a geometry.
+type simplifier struct {
+	lines []*simplifierLine
+	index *simplifierIndex
+}
+
+// A simplifierLine is a line or ring being simplified. Its kept vertices
+// form a doubly linked list. Removed vertices are unlinked, with next and
+// prev set to -1.
+type simplifierLine struct {
+	flatCoords []float64
+	stride     int
+	isRing     bool
+	next, prev []int
+	numPoints  int
+}
+
+// add adds the lines and rings of g to s in order.
+func (s *simplifier) add(g geom.T) error {
+	switch g := g.(type) {
+	case *geom.Point, *geom.MultiPoint:
+	case *geom.LineString:
+		s.addLine(g.FlatCoords(), g.Stride(), false)
+	case *geom.LinearRing:
+		s.addLine(g.FlatCoords(), g.Stride(), true)
+	case *geom.MultiLineString:
+		for i := range g.NumLineStrings() {
+			s.addLine(g.LineString(i).FlatCoords(), g.Stride(), false)
+		}
+	case *geom.Polygon:
+		for i := range g.NumLinearRings() {
+			s.addLine(g.LinearRing(i).FlatCoords(), g.Stride(), true)
+		}
+	case *geom.MultiPolygon:
+		for i := range g.NumPolygons() {
+			if err := s.add(g.Polygon(i)); err != nil {
+				return err
+			}
+		}
+	case *geom.GeometryCollection:
+		for _, child := range g.Geoms() {
+			if err := s.add(child); err != nil {
+				return err
+			}
+		}
+	default:
+		return geom.ErrUnsupportedType{Value: g}
+	}
+	return nil
+}
+
+func (s *simplifier) addLine(flatCoords []float64, stride int, isRing bool) {
+	n := len(flatCoords) / stride
+	l := &simplifierLine{
+		flatCoords: flatCoords,
+		stride:     stride,
+		isRing:     isRing,
+		next:       make([]int, n),
+		prev:       make([]int, n),
+		numPoints:  n,
+	}
+	for i := range n {
+		l.next[i], l.prev[i] = i+1, i-1
+	}
+	if n != 0 {
+		l.next[n-1] = -1
+	}
+	s.lines = append(s.lines, l)
+}
+
+// build returns the simplified copy of g, taking its lines and rings from s
+// starting at *next.
+func (s *simplifier) build(g geom.T, next *int) geom.T {
+	nextLine := func() *simplifierLine {
+		l := s.lines[*next]
+		*next++
+		return l
+	}
+	switch g := g.(type) {
+	case *geom.Point:
+		return g.Clone()
+	case *geom.MultiPoint:
+		return g.Clone()
+	case *geom.LineString:
+		return geom.NewLineStringFlat(g.Layout(), nextLine().appendFlatCoords(nil)).SetSRID(g.SRID())
+	case *geom.LinearRing:
+		return geom.NewLinearRingFlat(g.Layout(), nextLine().appendFlatCoords(nil)).SetSRID(g.SRID())
+	case *geom.MultiLineString:
+		var flatCoords []float64
+		ends := make([]int, 0, g.NumLineStrings())
+		for range g.NumLineStrings() {
+			flatCoords = nextLine().appendFlatCoords(flatCoords)
+			ends = append(ends, len(flatCoords))
+		}
+		return geom.NewMultiLineStringFlat(g.Layout(), flatCoords, ends).SetSRID(g.SRID())
+	case *geom.Polygon:
+		flatCoords, ends := s.buildPolygon(g, next)
+		return geom.NewPolygonFlat(g.Layout(), flatCoords, ends).SetSRID(g.SRID())
+	case *geom.MultiPolygon:
+		var flatCoords []float64
+		var endss [][]int
+		for i := range g.NumPolygons() {
+			polygonFlatCoords, ends := s.buildPolygon(g.Polygon(i), next)
+			if len(ends) == 0 {
+				continue
+			}
+			for j := range ends {
+				ends[j] += len(flatCoords)
+			}
+			flatCoords = append(flatCoords, polygonFlatCoords...)
+			endss = append(endss, ends)
+		}
+		return geom.NewMultiPolygonFlat(g.Layout(), flatCoords, endss).SetSRID(g.SRID())
+	case *geom.GeometryCollection:
+		result := geom.NewGeometryCollection().SetSRID(g.SRID())
+		for _, child := range g.Geoms() {
+			result.MustPush(s.build(child, next))
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// buildPolygon returns the flat coordinates and ends of the simplified copy
+// of p, without any collapsed rings. If the exterior ring has collapsed then
+// the polygon is empty.
+func (s *simplifier) buildPolygon(p *geom.Polygon, next *int) ([]float64, []int) {
+	var flatCoords []float64
+	var ends []int
+	for i := range p.NumLinearRings() {
+		l := s.lines[*next]
+		*next++
+		if l.collapsed() {
+			if i == 0 {
+				*next += p.NumLinearRings() - 1
+				return nil, nil
+			}
+			continue
+		}
+		flatCoords = l.appendFlatCoords(flatCoords)
+		ends = append(ends, len(flatCoords))
+	}
+	return flatCoords, ends
+}
+
+// collapsed returns whether l is a ring that has been simplified to fewer
+// than four points.
+func (l *simplifierLine) collapsed() bool {
+	return l.isRing && l.numPoints < 4 && l.numPoints < len(l.next)
+}
+
+// appendFlatCoords appends the coordinates of the kept vertices of l to
+// flatCoords.
+func (l *simplifierLine) appendFlatCoords(flatCoords []float64) []float64 {
+	if l.numPoints == 0 {
+		return flatCoords
+	}
+	for i := 0; i != -1; i = l.next[i] {
+		flatCoords = append(flatCoords, l.flatCoords[i*l.stride:(i+1)*l.stride]...)
+	}
+	return flatCoords
+}
+
+func (l *simplifierLine) xy(i int) (float64, float64) {
+	return l.flatCoords[i*l.stride], l.flatCoords[i*l.stride+1]
+}
+
+func (l *simplifierLine) coord(i int) []float64 {
+	return l.flatCoords[i*l.stride : i*l.stride+2]
+}
+
+// douglasPeucker simplifies l with the Ramer-Douglas-Peucker algorithm.
+// Each section of l is replaced by a single segment if all of its vertices
+// are within the tolerance of the segment, and otherwise is split at the
+// vertex furthest from the segment.
+func (s *simplifier) douglasPeucker(l *simplifierLine, tolerance2 float64) {
+	n := len(l.next)
+	if n < 3 {
+		return
+	}
+	stack := [][2]int{{0, n - 1}}
+	for len(stack) > 0 {
+		i, j := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+		if j <= i+1 {
+			continue
+		}
+		farthest, maxDistance2 := i+1, -1.0
+		for k := i + 1; k < j; k++ {
+			if distance2 := distanceFromSegmentSquared(l.coord(i), l.coord(j), l.coord(k)); distance2 > maxDistance2 {
+				farthest, maxDistance2 = k, distance2
+			}
+		}
+		if maxDistance2 <= tolerance2 && s.canFlatten(l, i, j) {
+			s.flatten(l, i, j)
+			continue
+		}
+		stack = append(stack, [2]int{farthest, j}, [2]int{i, farthest})
+	}
+}
+
+// visvalingamWhyatt simplifies l with the Visvalingam-Whyatt algorithm,
+// repeatedly removing the vertex with the smallest effective area, the area
+// of the triangle that it forms with its neighbors, while that area is less
+// than threshold.
+func (s *simplifier) visvalingamWhyatt(l *simplifierLine, threshold float64) {
+	n := len(l.next)
+	if n < 3 {
+		return
+	}
+	effectiveArea := func(i int) float64 {
+		ax, ay := l.xy(l.prev[i])
+		bx, by := l.xy(i)
+		cx, cy := l.xy(l.next[i])
+		return math.Abs((bx-ax)*(cy-ay)-(by-ay)*(cx-ax)) / 2
+	}
+	// Entries in the queue are stale if the vertex's area has changed since
+	// they were pushed.
+	areas := make([]float64, n)
+	var queue simplifierQueue
+	for i := 1; i < n-1; i++ {
+		areas[i] = effectiveArea(i)
+		queue = append(queue, simplifierItem{vertex: i, area: areas[i]})
+	}
+	heap.Init(&queue)
+	for queue.Len() > 0 {
+		item := heap.Pop(&queue).(simplifierItem)
+		i := item.vertex
+		if l.next[i] == -1 || item.area != areas[i] {
+			continue
+		}
+		if item.area >= threshold {
+			break
+		}
+		prev, next := l.prev[i], l.next[i]
+		if !s.canFlatten(l, prev, next) {
+			continue
+		}
+		s.flatten(l, prev, next)
+		for _, j := range []int{prev, next} {
+			if l.prev[j] != -1 && l.next[j] != -1 {
+				areas[j] = effectiveArea(j)
+				heap.Push(&queue, simplifierItem{vertex: j, area: areas[j]})
+			}
+		}
+	}
+}
+
+// flatten removes the vertices of l between i and j.
+func (s *simplifier) flatten(l *simplifierLine, i, j int) {
+	for k := l.next[i]; k != j; {
+		next := l.next[k]
+		l.next[k], l.prev[k] = -1, -1
+		l.numPoints--
+		k = next
+	}
+	l.next[i], l.prev[j] = j, i
+	if s.index != nil {
+		s.index.insert(simplifierSegment{line: l, from: i, to: j})
+	}
+}
+
+// canFlatten returns whether the vertices of l between i and j can be
+// removed. When preserving topology, the new segment from i to j must not
+// intersect any other segment except at shared endpoints, and no other
+// vertex can be inside or on the boundary of the section being removed.
+func (s *simplifier) canFlatten(l *simplifierLine, i, j int) bool {
+	if s.index == nil {
+		return true
+	}
+	var section []float64
+	box := internal.Box{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	removed := -1
+	for k := i; k != l.next[j]; k = l.next[k] {
+		x, y := l.xy(k)
+		section = append(section, x, y)
+		box = box.Union(internal.Box{MinX: x, MinY: y, MaxX: x, MaxY: y})
+		removed++
+	}
+	removed--
+	if l.isRing && l.numPoints-removed < 4 {
+		return false
+	}
+	ax, ay := l.xy(i)
+	bx, by := l.xy(j)
+	if ax == bx && ay == by {
+		return false
+	}
+	section = append(section, ax, ay)
+
+	inSection := func(segment simplifierSegment) bool {
+		return segment.line == l && i <= segment.from && segment.to <= j
+	}
+	chordBox := internal.SegmentBox(ax, ay, bx, by)
+	return s.index.forEach(chordBox, func(segment simplifierSegment) bool {
+		if inSection(segment) {
+			return true
+		}
+		cx, cy := segment.line.xy(segment.from)
+		dx, dy := segment.line.xy(segment.to)
+		return !chordBox.Overlaps(internal.SegmentBox(cx, cy, dx, dy)) || !segmentsConflict(ax, ay, bx, by, cx, cy, dx, dy)
+	}) && s.index.forEach(box, func(segment simplifierSegment) bool {
+		if inSection(segment) {
+			return true
+		}
+		for _, k := range []int{segment.from, segment.to} {
+			if segment.line == l && i <= k && k <= j {
+				continue
+			}
+			x, y := segment.line.xy(k)
+			if x == ax && y == ay || x == bx && y == by || !box.Overlaps(internal.Box{MinX: x, MinY: y, MaxX: x, MaxY: y}) {
+				continue
+			}
+			if LocatePointInRing(geom.XY, geom.Coord{x, y}, section) != location.Exterior {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// segmentsConflict returns whether the segment from (ax, ay) to (bx, by)
+// and the segment from (cx, cy) to (dx, dy) intersect anywhere except at an
+// endpoint that they share.
+func segmentsConflict(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	orient := func(px, py, qx, qy, rx, ry float64) robustdeterminate.Sign {
+		// Only use the slower robust computation if rounding errors might
+		// affect the sign.
+		left, right := (qx-px)*(ry-py), (qy-py)*(rx-px)
+		switch det := left - right; {
+		case det > 1e-15*(math.Abs(left)+math.Abs(right)):
+			return robustdeterminate.Positive
+		case det < -1e-15*(math.Abs(left)+math.Abs(right)):
+			return robustdeterminate.Negative
+		default:
+			return robustdeterminate.SignOfDet2x2(qx-px, qy-py, rx-px, ry-py)
+		}
+	}
+	o1, o2 := orient(ax, ay, bx, by, cx, cy), orient(ax, ay, bx, by, dx, dy)
+	o3, o4 := orient(cx, cy, dx, dy, ax, ay), orient(cx, cy, dx, dy, bx, by)
+	isEndpoint := func(x, y, x0, y0, x1, y1 float64) bool {
+		return x == x0 && y == y0 || x == x1 && y == y1
+	}
+
+	if o1 == robustdeterminate.Zero && o2 == robustdeterminate.Zero {
+		// The segments are collinear, so compare their extents along the
+		// longer axis of the first segment.
+		project := func(x, y float64) float64 {
+			if math.Abs(bx-ax) >= math.Abs(by-ay) {
+				return x
+			}
+			return y
+		}
+		pa, pb, pc, pd := project(ax, ay), project(bx, by), project(cx, cy), project(dx, dy)
+		lo, hi := max(min(pa, pb), min(pc, pd)), min(max(pa, pb), max(pc, pd))
+		switch {
+		case lo > hi:
+			return false
+		case lo < hi:
+			return true
+		}
+		// The segments touch at a single point, which must be a shared
+		// endpoint.
+		return !(isEndpoint(ax, ay, cx, cy, dx, dy) && project(ax, ay) == lo ||
+			isEndpoint(bx, by, cx, cy, dx, dy) && project(bx, by) == lo)
+	}
+	if o1*o2 > 0 || o3*o4 > 0 {
+		return false
+	}
+	// The segments intersect, so any endpoint that is on the other segment
+	// must be one of its endpoints.
+	return o1 == robustdeterminate.Zero && !isEndpoint(cx, cy, ax, ay, bx, by) ||
+		o2 == robustdeterminate.Zero && !isEndpoint(dx, dy, ax, ay, bx, by) ||
+		o3 == robustdeterminate.Zero && !isEndpoint(ax, ay, cx, cy, dx, dy) ||
+		o4 == robustdeterminate.Zero && !isEndpoint(bx, by, cx, cy, dx, dy) ||
+		o1 != robustdeterminate.Zero && o2 != robustdeterminate.Zero && o3 != robustdeterminate.Zero && o4 != robustdeterminate.Zero
+}
+
+// A simplifierSegment is a segment of a simplifierLine from vertex from to
+// vertex to. It is current while they are adjacent.
+type simplifierSegment struct {
+	line     *simplifierLine
+	from, to int
+}
+
+// A simplifierIndex is a grid of square cells containing the segments that
+// overlap them. Segments are not removed from the index when they are
+// replaced, but are ignored once they are no longer current.
+type simplifierIndex struct {
+	minX, minY float64
+	cellSize   float64
+	cells      map[[2]int][]simplifierSegment
+}
+
+func newSimplifierIndex(lines []*simplifierLine) *simplifierIndex {
+	box := internal.Box{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	numSegments, length := 0, 0.0
+	for _, l := range lines {
+		for i := range l.numPoints {
+			x, y := l.xy(i)
+			box = box.Union(internal.Box{MinX: x, MinY: y, MaxX: x, MaxY: y})
+			if i > 0 {
+				x0, y0 := l.xy(i - 1)
+				length += math.Hypot(x-x0, y-y0)
+				numSegments++
+			}
+		}
+	}
+	index := &simplifierIndex{
+		minX:  box.MinX,
+		minY:  box.MinY,
+		cells: make(map[[2]int][]simplifierSegment),
+	}
+	// Make the cells large enough that most segments overlap only a few of
+	// them, but small enough that there are not many more segments than
+	// cells.
+	n := float64(max(numSegments, 1))
+	index.cellSize = max(length/n, max(box.MaxX-box.MinX, box.MaxY-box.MinY)/math.Sqrt(n))
+	if index.cellSize == 0 || math.IsInf(index.cellSize, 0) || math.IsNaN(index.cellSize) {
+		index.cellSize = 1
+	}
+	for _, l := range lines {
+		for i := 0; i+1 < l.numPoints; i++ {
+			index.insert(simplifierSegment{line: l, from: i, to: i + 1})
+		}
+	}
+	return index
+}
+
+// cellRange returns the range of cells that overlap box.
+func (index *simplifierIndex) cellRange(box internal.Box) (minI, minJ, maxI, maxJ int) {
+	cell := func(x, y float64) (int, int) {
+		return int(math.Floor((x - index.minX) / index.cellSize)), int(math.Floor((y - index.minY) / index.cellSize))
+	}
+	minI, minJ = cell(box.MinX, box.MinY)
+	maxI, maxJ = cell(box.MaxX, box.MaxY)
+	return minI, minJ, maxI, maxJ
+}
+
+func (index *simplifierIndex) insert(segment simplifierSegment) {
+	x0, y0 := segment.line.xy(segment.from)
+	x1, y1 := segment.line.xy(segment.to)
+	minI, minJ, maxI, maxJ := index.cellRange(internal.SegmentBox(x0, y0, x1, y1))
+	for i := minI; i <= maxI; i++ {
+		for j := minJ; j <= maxJ; j++ {
+			index.cells[[2]int{i, j}] = append(index.cells[[2]int{i, j}], segment)
+		}
+	}
+}
+
+// forEach calls f for each current segment in the cells that overlap box,
+// possibly more than once. If f returns false then the iteration stops and
+// forEach returns false.
+func (index *simplifierIndex) forEach(box internal.Box, f func(simplifierSegment) bool) bool {
+	minI, minJ, maxI, maxJ := index.cellRange(box)
+	for i := minI; i <= maxI; i++ {
+		for j := minJ; j <= maxJ; j++ {
+			key := [2]int{i, j}
+			segments := index.cells[key]
+			// Remove segments that are no longer current while iterating.
+			current := segments[:0]
+			for k, segment := range segments {
+				if segment.line.next[segment.from] != segment.to {
+					continue
+				}
+				current = append(current, segment)
+				if !f(segment) {
+					index.cells[key] = append(current, segments[k+1:]...)
+					return false
+				}
+			}
+			index.cells[key] = current
+		}
+	}
+	return true
+}
+
+// A simplifierItem is a vertex in a simplifierQueue.
+type simplifierItem struct {
+	vertex int
+	area   float64
+}
+
+// A simplifierQueue is a priority queue of vertices with the smallest
+// effective area first.
+type simplifierQueue []simplifierItem
+
+func (q simplifierQueue) Len() int { return len(q) }
+
+func (q simplifierQueue) Less(i, j int) bool {
+	if q[i].area != q[j].area {
+		return q[i].area < q[j].area
+	}
+	return q[i].vertex < q[j].vertex
+}
+
+func (q simplifierQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *simplifierQueue) Push(x any) { *q = append(*q, x.(simplifierItem)) }
+
+func (q *simplifierQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,298 @@
+package xy
+
+import (
+	"github.com/twpayne/go-geom"
+)
+
+// SimplifyCoverage simplifies a coverage, a set of polygons whose interiors
+// do not overlap, such as counties or postal areas, without opening gaps or
+// slivers between them. coverage must contain only *geom.Polygons and
+// *geom.MultiPolygons with the same layout, and adjacent polygons must share
+// the vertices of their common boundaries exactly.
+//
+// The boundaries of the polygons are split into edges at the vertices where
+// three or more polygons meet or where neighboring polygons diverge. Each
+// edge is simplified once with algorithm and tolerance, as by
+// SimplifyPreserveTopology, and the polygons are rebuilt from the simplified
+// edges, so shared edges remain shared and the result is still a coverage.
+// The ends of each edge are always kept, and rings never collapse.
+//
+// The result contains the simplified polygons in the same order and with
+// the same types and SRIDs as coverage. Their rings may start at different
+// vertices, and consecutive repeated points are removed.
+func SimplifyCoverage(coverage []geom.T, tolerance float64, algorithm SimplifyAlgorithm) ([]geom.T, error) {
+	c := &coverageSimplifier{
+		arcIDs: make(map[coverageArcKey]int),
+	}
+	for _, g := range coverage {
+		if err := c.addGeom(g); err != nil {
+			return nil, err
+		}
+	}
+	c.findJunctions()
+	for _, ring := range c.rings {
+		c.addArcs(ring)
+	}
+
+	s := &simplifier{}
+	for _, arc := range c.arcs {
+		n := len(arc) / c.stride
+		isRing := n > 1 && arc[0] == arc[(n-1)*c.stride] && arc[1] == arc[(n-1)*c.stride+1]
+		s.addLine(arc, c.stride, isRing)
+	}
+	s.index = newSimplifierIndex(s.lines)
+	tolerance2 := max(tolerance, 0) * max(tolerance, 0)
+	for _, l := range s.lines {
+		switch algorithm {
+		case SimplifyVisvalingamWhyatt:
+			s.visvalingamWhyatt(l, tolerance2)
+		default:
+			s.douglasPeucker(l, tolerance2)
+		}
+	}
+
+	result := make([]geom.T, 0, len(coverage))
+	next := 0
+	nextPolygon := func() ([]float64, []int) {
+		var flatCoords []float64
+		var ends []int
+		for range c.numRings[next] {
+			flatCoords = c.buildRing(s, c.rings[len(ends)+c.firstRing[next]], flatCoords)
+			ends = append(ends, len(flatCoords))
+		}
+		next++
+		return flatCoords, ends
+	}
+	for _, g := range coverage {
+		switch g := g.(type) {
+		case *geom.Polygon:
+			flatCoords, ends := nextPolygon()
+			result = append(result, geom.NewPolygonFlat(g.Layout(), flatCoords, ends).SetSRID(g.SRID()))
+		case *geom.MultiPolygon:
+			var flatCoords []float64
+			endss := make([][]int, 0, g.NumPolygons())
+			for range g.NumPolygons() {
+				polygonFlatCoords, ends := nextPolygon()
+				for i := range ends {
+					ends[i] += len(flatCoords)
+				}
+				flatCoords = append(flatCoords, polygonFlatCoords...)
+				endss = append(endss, ends)
+			}
+			result = append(result, geom.NewMultiPolygonFlat(g.Layout(), flatCoords, endss).SetSRID(g.SRID()))
+		}
+	}
+	return result, nil
+}
+
+// A coverageSimplifier splits the rings of a coverage into arcs that can be
+// simplified independently.
+type coverageSimplifier struct {
+	layout    geom.Layout
+	stride    int
+	rings     []*coverageRing
+	firstRing []int
+	numRings  []int
+	junctions map[[2]float64]bool
+	arcs      [][]float64
+	arcIDs    map[coverageArcKey]int
+}
+
+// A coverageRing is a ring of a coverage, without its closing point or any
+// consecutive repeated points, and the arcs that form it.
+type coverageRing struct {
+	flatCoords []float64
+	arcs       []coverageArcRef
+}
+
+// A coverageArcRef is a reference to an arc, which may be reversed.
+type coverageArcRef struct {
+	id       int
+	reversed bool
+}
+
+// A coverageArcKey identifies an arc by its first two vertices, its last
+// vertex, and its number of vertices. As arcs only meet at junctions, no two
+// distinct arcs have the same key.
+type coverageArcKey struct {
+	first, second, last [2]float64
+	n                   int
+}
+
+func (c *coverageSimplifier) addGeom(g geom.T) error {
+	switch g := g.(type) {
+	case *geom.Polygon:
+		if err := c.checkLayout(g.Layout()); err != nil {
+			return err
+		}
+		c.addPolygon(g)
+	case *geom.MultiPolygon:
+		if err := c.checkLayout(g.Layout()); err != nil {
+			return err
+		}
+		for i := range g.NumPolygons() {
+			c.addPolygon(g.Polygon(i))
+		}
+	default:
+		return geom.ErrUnsupportedType{Value: g}
+	}
+	return nil
+}
+
+func (c *coverageSimplifier) checkLayout(layout geom.Layout) error {
+	switch c.layout {
+	case geom.NoLayout:
+		c.layout, c.stride = layout, layout.Stride()
+	case layout:
+	default:
+		return geom.ErrLayoutMismatch{Got: layout, Want: c.layout}
+	}
+	return nil
+}
+
+func (c *coverageSimplifier) addPolygon(p *geom.Polygon) {
+	c.firstRing = append(c.firstRing, len(c.rings))
+	numRings := 0
+	for i := range p.NumLinearRings() {
+		flatCoords := p.LinearRing(i).FlatCoords()
+		var ring []float64
+		for j := 0; j < len(flatCoords); j += c.stride {
+			if n := len(ring); n == 0 || flatCoords[j] != ring[n-c.stride] || flatCoords[j+1] != ring[n-c.stride+1] {
+				ring = append(ring, flatCoords[j:j+c.stride]...)
+			}
+		}
+		if n := len(ring); n > c.stride && ring[0] == ring[n-c.stride] && ring[1] == ring[n-c.stride+1] {
+			ring = ring[:n-c.stride]
+		}
+		if len(ring) == 0 {
+			continue
+		}
+		c.rings = append(c.rings, &coverageRing{flatCoords: ring})
+		numRings++
+	}
+	c.numRings = append(c.numRings, numRings)
+}
+
+// findJunctions finds the vertices that are the ends of arcs. A vertex is a
+// junction if it has different neighbors in different rings, or in different
+// places in the same ring.
+func (c *coverageSimplifier) findJunctions() {
+	neighbors := make(map[[2]float64][2][2]float64)
+	c.junctions = make(map[[2]float64]bool)
+	for _, ring := range c.rings {
+		n := ring.numPoints(c.stride)
+		for i := range n {
+			vertex := ring.xy(i, c.stride)
+			a, b := ring.xy((i+n-1)%n, c.stride), ring.xy((i+1)%n, c.stride)
+			if lessXY(b, a) {
+				a, b = b, a
+			}
+			if existing, ok := neighbors[vertex]; !ok {
+				neighbors[vertex] = [2][2]float64{a, b}
+			} else if existing != [2][2]float64{a, b} {
+				c.junctions[vertex] = true
+			}
+		}
+	}
+}
+
+// addArcs splits ring into arcs at its junctions and adds them to c.
+func (c *coverageSimplifier) addArcs(ring *coverageRing) {
+	n := ring.numPoints(c.stride)
+	var cuts []int
+	for i := range n {
+		if c.junctions[ring.xy(i, c.stride)] {
+			cuts = append(cuts, i)
+		}
+	}
+	if len(cuts) == 0 {
+		// The ring is a single closed arc. Start it at its smallest vertex so
+		// that the same ring in different polygons gives the same arc.
+		start := 0
+		for i := 1; i < n; i++ {
+			if lessXY(ring.xy(i, c.stride), ring.xy(start, c.stride)) {
+				start = i
+			}
+		}
+		cuts = []int{start}
+	}
+	for k, start := range cuts {
+		end := cuts[0] + n
+		if k+1 < len(cuts) {
+			end = cuts[k+1]
+		}
+		arc := make([]float64, 0, (end-start+1)*c.stride)
+		for i := start; i <= end; i++ {
+			j := i % n
+			arc = append(arc, ring.flatCoords[j*c.stride:(j+1)*c.stride]...)
+		}
+		ring.arcs = append(ring.arcs, c.addArc(arc))
+	}
+}
+
+// addArc adds arc to c, if it has not already been added in either
+// direction, and returns a reference to it.
+func (c *coverageSimplifier) addArc(arc []float64) coverageArcRef {
+	n := len(arc) / c.stride
+	xy := func(i int) [2]float64 {
+		return [2]float64{arc[i*c.stride], arc[i*c.stride+1]}
+	}
+	forward := coverageArcKey{first: xy(0), second: xy(1), last: xy(n - 1), n: n}
+	backward := coverageArcKey{first: xy(n - 1), second: xy(n - 2), last: xy(0), n: n}
+	reversed := lessXY(backward.first, forward.first) ||
+		backward.first == forward.first && lessXY(backward.second, forward.second)
+	key := forward
+	if reversed {
+		key = backward
+	}
+	if id, ok := c.arcIDs[key]; ok {
+		return coverageArcRef{id: id, reversed: reversed}
+	}
+	if reversed {
+		arc = reverseFlatCoords(arc, c.stride)
+	}
+	id := len(c.arcs)
+	c.arcs = append(c.arcs, arc)
+	c.arcIDs[key] = id
+	return coverageArcRef{id: id, reversed: reversed}
+}
+
+// buildRing appends the closed ring formed by the simplified arcs of ring to
+// flatCoords.
+func (c *coverageSimplifier) buildRing(s *simplifier, ring *coverageRing, flatCoords []float64) []float64 {
+	start := len(flatCoords)
+	for _, ref := range ring.arcs {
+		arc := s.lines[ref.id].appendFlatCoords(nil)
+		if ref.reversed {
+			arc = reverseFlatCoords(arc, c.stride)
+		}
+		if len(flatCoords) > start {
+			arc = arc[c.stride:]
+		}
+		flatCoords = append(flatCoords, arc...)
+	}
+	return flatCoords
+}
+
+func (r *coverageRing) numPoints(stride int) int {
+	return len(r.flatCoords) / stride
+}
+
+func (r *coverageRing) xy(i, stride int) [2]float64 {
+	return [2]float64{r.flatCoords[i*stride], r.flatCoords[i*stride+1]}
+}
+
+// lessXY returns whether a is before b, ordering by x and then by y.
+func lessXY(a, b [2]float64) bool {
+	return a[0] < b[0] || a[0] == b[0] && a[1] < b[1]
+}
+
+// reverseFlatCoords returns a copy of flatCoords with the order of its
+// points reversed.
+func reverseFlatCoords(flatCoords []float64, stride int) []float64 {
+	reversed := make([]float64, 0, len(flatCoords))
+	for i := len(flatCoords) - stride; i >= 0; i -= stride {
+		reversed = append(reversed, flatCoords[i:i+stride]...)
+	}
+	return reversed
+}
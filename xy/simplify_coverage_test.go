@@ -0,0 +1,111 @@
+package xy_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestSimplifyCoverage(t *testing.T) {
+	// Two squares sharing a wiggly edge, and a third polygon sharing part of
+	// the bottom edge of the first and with a hole filled by a fourth.
+	coverage := []geom.T{
+		geom.NewPolygonFlat(geom.XY, []float64{
+			0, 0, 5, 0, 10, 0, 10.2, 2, 9.8, 4, 10.3, 6, 9.9, 8, 10, 10, 0, 10, 0, 0,
+		}, []int{20}).SetSRID(4326),
+		geom.NewMultiPolygonFlat(geom.XY, []float64{
+			10, 0, 20, 0, 20, 10, 10, 10, 9.9, 8, 10.3, 6, 9.8, 4, 10.2, 2, 10, 0,
+		}, [][]int{{18}}).SetSRID(4326),
+		geom.NewPolygonFlat(geom.XY, []float64{
+			0, 0, 0, -10, 5.1, -5, 10, -10, 10, 0, 5, 0, 0, 0,
+			2, -2, 6, -2, 6.1, -3.5, 6, -5, 2, -5, 2, -2,
+		}, []int{14, 26}),
+		geom.NewPolygonFlat(geom.XY, []float64{
+			2, -2, 2, -5, 6, -5, 6.1, -3.5, 6, -2, 2, -2,
+		}, []int{12}),
+	}
+	for _, tc := range []struct {
+		algorithm xy.SimplifyAlgorithm
+		tolerance float64
+	}{
+		{algorithm: xy.SimplifyDouglasPeucker, tolerance: 0.5},
+		{algorithm: xy.SimplifyVisvalingamWhyatt, tolerance: 1.5},
+	} {
+		got, err := xy.SimplifyCoverage(coverage, tc.tolerance, tc.algorithm)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got) != len(coverage) {
+			t.Fatalf("expected %d geometries, got %d", len(coverage), len(got))
+		}
+		for i, g := range got {
+			if reflect.TypeOf(g) != reflect.TypeOf(coverage[i]) || g.SRID() != coverage[i].SRID() {
+				t.Errorf("%d: expected %T with SRID %d, got %T with SRID %d", i, coverage[i], coverage[i].SRID(), g, g.SRID())
+			}
+			if err := xy.Validate(g); err != nil {
+				t.Errorf("%d: %v", i, err)
+			}
+		}
+
+		// The wiggly edge is simplified to the same straight line in both
+		// squares, so there are no gaps or overlaps.
+		expectedAreas := []float64{100, 100, 63, 12}
+		for i, g := range got {
+			if area := math.Abs(areaOf(g)); math.Abs(area-expectedAreas[i]) > 1e-9 {
+				t.Errorf("%d: expected area %v, got %v (%v)", i, expectedAreas[i], area, g.FlatCoords())
+			}
+		}
+		if !reflect.DeepEqual(got[0].FlatCoords(), []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}) {
+			t.Errorf("expected %v, got %v", []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, got[0].FlatCoords())
+		}
+		// The hole and the polygon that fills it are simplified together
+		// and do not collapse.
+		hole := got[2].(*geom.Polygon).LinearRing(1)
+		fill := got[3].(*geom.Polygon).LinearRing(0)
+		if hole.NumCoords() != fill.NumCoords() || hole.NumCoords() < 4 {
+			t.Errorf("expected the hole %v to match %v", hole.FlatCoords(), fill.FlatCoords())
+		}
+	}
+}
+
+func areaOf(g geom.T) float64 {
+	switch g := g.(type) {
+	case *geom.Polygon:
+		area := 0.0
+		for i := range g.NumLinearRings() {
+			ringArea := math.Abs(xy.SignedArea(geom.XY, g.LinearRing(i).FlatCoords()))
+			if i == 0 {
+				area += ringArea
+			} else {
+				area -= ringArea
+			}
+		}
+		return area
+	case *geom.MultiPolygon:
+		area := 0.0
+		for i := range g.NumPolygons() {
+			area += areaOf(g.Polygon(i))
+		}
+		return area
+	default:
+		return 0
+	}
+}
+
+func TestSimplifyCoverageErrors(t *testing.T) {
+	var errUnsupportedType geom.ErrUnsupportedType
+	if _, err := xy.SimplifyCoverage([]geom.T{geom.NewPointFlat(geom.XY, []float64{0, 0})}, 1, xy.SimplifyDouglasPeucker); !errors.As(err, &errUnsupportedType) {
+		t.Errorf("expected geom.ErrUnsupportedType, got %v", err)
+	}
+	var errLayoutMismatch geom.ErrLayoutMismatch
+	if _, err := xy.SimplifyCoverage([]geom.T{
+		geom.NewPolygon(geom.XY),
+		geom.NewPolygon(geom.XYZ),
+	}, 1, xy.SimplifyDouglasPeucker); !errors.As(err, &errLayoutMismatch) {
+		t.Errorf("expected geom.ErrLayoutMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleSimplify() {
+	ls := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 0.1, 2, -0.1, 3, 5, 4, 0})
+	for _, algorithm := range []xy.SimplifyAlgorithm{
+		xy.SimplifyDouglasPeucker,
+		xy.SimplifyVisvalingamWhyatt,
+	} {
+		simplified, err := xy.Simplify(ls, 0.5, algorithm)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(simplified.FlatCoords())
+	}
+	// Output:
+	// [0 0 2 -0.1 3 5 4 0]
+	// [0 0 2 -0.1 3 5 4 0]
+}
+
+func ExampleSimplifyPreserveTopology() {
+	// Removing the top vertex of the shell would move it across the hole.
+	polygon := geom.NewPolygonFlat(geom.XY, []float64{
+		0, 0, 20, 0, 20, 10, 10, 11, 0, 10, 0, 0,
+		6, 5, 6, 10.4, 14, 10.4, 14, 5, 6, 5,
+	}, []int{12, 22})
+
+	simplified, _ := xy.Simplify(polygon, 1.5, xy.SimplifyDouglasPeucker)
+	fmt.Println(xy.IsValid(simplified))
+
+	simplified, _ = xy.SimplifyPreserveTopology(polygon, 1.5, xy.SimplifyDouglasPeucker)
+	fmt.Println(xy.IsValid(simplified))
+	// Output:
+	// false
+	// true
+}
+
+func ExampleSimplifyCoverage() {
+	// Two squares that share a wiggly edge.
+	coverage := []geom.T{
+		geom.NewPolygonFlat(geom.XY, []float64{
+			0, 0, 10, 0, 10.2, 5, 10, 10, 0, 10, 0, 0,
+		}, []int{12}),
+		geom.NewPolygonFlat(geom.XY, []float64{
+			10, 0, 20, 0, 20, 10, 10, 10, 10.2, 5, 10, 0,
+		}, []int{12}),
+	}
+	simplified, err := xy.SimplifyCoverage(coverage, 0.5, xy.SimplifyDouglasPeucker)
+	if err != nil {
+		panic(err)
+	}
+	for _, g := range simplified {
+		fmt.Println(g.FlatCoords())
+	}
+	// Output:
+	// [10 0 10 10 0 10 0 0 10 0]
+	// [10 0 20 0 20 10 10 10 10 0]
+}
@@ -0,0 +1,182 @@
+package xy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestSimplifyTypes(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		g         geom.T
+		tolerance float64
+		algorithm xy.SimplifyAlgorithm
+		expected  geom.T
+	}{
+		{
+			name:      "point",
+			g:         geom.NewPointFlat(geom.XYZ, []float64{1, 2, 3}).SetSRID(4326),
+			tolerance: 1,
+			expected:  geom.NewPointFlat(geom.XYZ, []float64{1, 2, 3}).SetSRID(4326),
+		},
+		{
+			name:      "line_string_douglas_peucker",
+			g:         geom.NewLineStringFlat(geom.XYM, []float64{0, 0, 1, 1, 0.1, 2, 2, -0.1, 3, 3, 5, 4, 4, 0, 5}).SetSRID(4326),
+			tolerance: 0.5,
+			expected:  geom.NewLineStringFlat(geom.XYM, []float64{0, 0, 1, 2, -0.1, 3, 3, 5, 4, 4, 0, 5}).SetSRID(4326),
+		},
+		{
+			name:      "line_string_visvalingam_whyatt",
+			g:         geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 0.1, 2, -0.1, 3, 5, 4, 0}),
+			tolerance: 1,
+			algorithm: xy.SimplifyVisvalingamWhyatt,
+			expected:  geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2, -0.1, 3, 5, 4, 0}),
+		},
+		{
+			name:      "multi_line_string",
+			g:         geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 1, 0.1, 2, 0, 0, 1, 1, 1.1, 2, 1}, []int{6, 12}),
+			tolerance: 0.5,
+			expected:  geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 2, 0, 0, 1, 2, 1}, []int{4, 8}),
+		},
+		{
+			name: "polygon_with_collapsed_hole",
+			g: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 5, 0.1, 10, 0, 10, 10, 0, 10, 0, 0,
+				4, 4, 4, 4.2, 4.2, 4.2, 4, 4,
+			}, []int{12, 20}),
+			tolerance: 1,
+			expected:  geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, []int{10}),
+		},
+		{
+			name: "multi_polygon_with_collapsed_polygon",
+			g: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				20, 20, 20.1, 20, 20.1, 20.1, 20, 20,
+				0, 0, 5, 0.1, 10, 0, 10, 10, 0, 10, 0, 0,
+			}, [][]int{{8}, {20}}),
+			tolerance: 1,
+			expected:  geom.NewMultiPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}, [][]int{{10}}),
+		},
+		{
+			name: "geometry_collection",
+			g: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{1, 2}),
+				geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 0.1, 2, 0}),
+			).SetSRID(4326),
+			tolerance: 0.5,
+			expected: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{1, 2}),
+				geom.NewLineStringFlat(geom.XY, []float64{0, 0, 2, 0}),
+			).SetSRID(4326),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := xy.Simplify(tc.g, tc.tolerance, tc.algorithm)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSimplifyPreserveTopology(t *testing.T) {
+	// Removing the top vertex of the shell would move it across the hole.
+	polygon := geom.NewPolygonFlat(geom.XY, []float64{
+		0, 0, 20, 0, 20, 10, 10, 11, 0, 10, 0, 0,
+		6, 5, 6, 10.4, 14, 10.4, 14, 5, 6, 5,
+	}, []int{12, 22})
+	for _, tc := range []struct {
+		name      string
+		tolerance float64
+		algorithm xy.SimplifyAlgorithm
+	}{
+		{
+			name:      "douglas_peucker",
+			tolerance: 1.5,
+			algorithm: xy.SimplifyDouglasPeucker,
+		},
+		{
+			name:      "visvalingam_whyatt",
+			tolerance: 4,
+			algorithm: xy.SimplifyVisvalingamWhyatt,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			simplified, err := xy.Simplify(polygon, tc.tolerance, tc.algorithm)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if xy.IsValid(simplified) {
+				t.Errorf("expected %v to be invalid", simplified.FlatCoords())
+			}
+
+			got, err := xy.SimplifyPreserveTopology(polygon, tc.tolerance, tc.algorithm)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(polygon, got) {
+				t.Errorf("expected %v, got %v", polygon.FlatCoords(), got.FlatCoords())
+			}
+		})
+	}
+}
+
+func TestSimplifyPreserveTopologyRings(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		g        geom.T
+		expected geom.T
+	}{
+		{
+			name: "ring_does_not_collapse",
+			g: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 1, 0, 2, 0.1, 2, 1, 0, 0,
+			}, []int{10}),
+			expected: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 2, 0.1, 2, 1, 0, 0,
+			}, []int{8}),
+		},
+		{
+			name: "ring_does_not_self_intersect",
+			// Removing (5, -1) would make the first edge cross the notch
+			// at (5, -0.5).
+			g: geom.NewLinearRingFlat(geom.XY, []float64{
+				0, 0, 5, -1, 10, 0, 10, 10, 6, 10, 5, -0.5, 4, 10, 0, 10, 0, 0,
+			}),
+		},
+		{
+			name: "line_does_not_cross_other_line",
+			g: geom.NewMultiLineStringFlat(geom.XY, []float64{
+				0, 0, 5, 0.5, 10, 0,
+				5, 0.2, 5, -3,
+			}, []int{6, 10}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := xy.SimplifyPreserveTopology(tc.g, 1, xy.SimplifyDouglasPeucker)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			expected := tc.expected
+			if expected == nil {
+				expected = tc.g
+			}
+			if !reflect.DeepEqual(expected, got) {
+				t.Errorf("expected %v, got %v", expected.FlatCoords(), got.FlatCoords())
+			}
+		})
+	}
+}
+
+func TestSimplifyUnsupportedType(t *testing.T) {
+	var errUnsupportedType geom.ErrUnsupportedType
+	if _, err := xy.Simplify(nil, 1, xy.SimplifyDouglasPeucker); !errors.As(err, &errUnsupportedType) {
+		t.Errorf("expected geom.ErrUnsupportedType, got %v", err)
+	}
+}
@@ -0,0 +1,602 @@
+package xy
+
+// The ear clipping algorithm is adapted from
+// https://github.com/mapbox/earcut. Original license:
+//
+// ISC License
+//
+// Copyright (c) 2016, Mapbox
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND ISC DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL ISC BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE
+// OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+import (
+	"math"
+	"sort"
+
+	"github.com/twpayne/go-geom"
+)
+
+// A TriangulationMethod is a method of triangulating polygons.
+type TriangulationMethod int
+
+const (
+	// TriangulationEarClipping triangulates polygons by ear clipping, which
+	// is fast but can produce long, thin triangles.
+	TriangulationEarClipping TriangulationMethod = iota
+	// TriangulationConstrainedDelaunay triangulates polygons with the
+	// constrained Delaunay triangulation of their vertices, which maximizes
+	// the minimum angle of the triangles subject to the edges of the
+	// polygon.
+	TriangulationConstrainedDelaunay
+)
+
+// TriangulateIndices returns a triangulation of p as an index buffer. Each
+// consecutive triple of indices is a counterclockwise triangle, and each
+// index is the index of a vertex in p's flat coordinates, so vertex i is
+// p.FlatCoords()[i*p.Stride():(i+1)*p.Stride()]. No new vertices are added
+// and some vertices, such as repeated points and points on straight edges,
+// might not be used. Holes are allowed, but the rings of p should not cross.
+// It returns nil if p has no area.
+func TriangulateIndices(p *geom.Polygon, method TriangulationMethod) []int {
+	flatCoords, stride := p.FlatCoords(), p.Stride()
+	var triangles [][3]int
+	start := 0
+	var outer *earNode
+	var holes []*earNode
+	for i, end := range p.Ends() {
+		ring := newEarRing(flatCoords, stride, start, end, i == 0)
+		start = end
+		switch {
+		case ring == nil || ring.next == ring.prev:
+			if i == 0 {
+				return nil
+			}
+		case i == 0:
+			outer = ring
+		default:
+			holes = append(holes, ring.leftmost())
+		}
+	}
+	if outer == nil {
+		return nil
+	}
+	outer = eliminateHoles(outer, holes)
+	triangles = earClip(triangles, outer, 0)
+	if len(triangles) == 0 {
+		return nil
+	}
+
+	if method == TriangulationConstrainedDelaunay {
+		// The edges of the polygon are on the boundary of the
+		// triangulation, so they are never flipped.
+		t := newTriangulation(flatCoords, stride)
+		for _, triangle := range triangles {
+			t.addTriangle(triangle[0], triangle[1], triangle[2])
+		}
+		t.legalizeAll()
+		triangles = t.triangles
+	}
+
+	indices := make([]int, 0, 3*len(triangles))
+	for _, triangle := range triangles {
+		indices = append(indices, triangle[0], triangle[1], triangle[2])
+	}
+	return indices
+}
+
+// Triangulate returns a triangulation of p as a MultiPolygon of
+// counterclockwise triangles with the layout and SRID of p. See
+// TriangulateIndices for details.
+func Triangulate(p *geom.Polygon, method TriangulationMethod) *geom.MultiPolygon {
+	indices := TriangulateIndices(p, method)
+	flatCoords, stride := p.FlatCoords(), p.Stride()
+	triangleFlatCoords := make([]float64, 0, 4*stride*len(indices)/3)
+	endss := make([][]int, 0, len(indices)/3)
+	for i := 0; i < len(indices); i += 3 {
+		for _, index := range []int{indices[i], indices[i+1], indices[i+2], indices[i]} {
+			triangleFlatCoords = append(triangleFlatCoords, flatCoords[index*stride:(index+1)*stride]...)
+		}
+		endss = append(endss, []int{len(triangleFlatCoords)})
+	}
+	return geom.NewMultiPolygonFlat(p.Layout(), triangleFlatCoords, endss).SetSRID(p.SRID())
+}
+
+// An earNode is a vertex in a circular doubly linked list of the vertices of
+// a ring. The ear clipping algorithm is based on that of Mapbox's earcut
+// library, without its z-order indexing.
+type earNode struct {
+	i          int
+	x, y       float64
+	prev, next *earNode
+}
+
+// newEarRing returns the last node of a list of the vertices of the ring
+// flatCoords[start:end], counterclockwise if ccw is true and clockwise
+// otherwise, with the closing vertex removed.
+func newEarRing(flatCoords []float64, stride, start, end int, ccw bool) *earNode {
+	var last *earNode
+	insert := func(i int) {
+		node := &earNode{i: i / stride, x: flatCoords[i], y: flatCoords[i+1]}
+		if last == nil {
+			node.prev, node.next = node, node
+		} else {
+			node.next, node.prev = last.next, last
+			last.next.prev, last.next = node, node
+		}
+		last = node
+	}
+	area := 0.0
+	for i, j := start, end-stride; i < end; i, j = i+stride, i {
+		area += (flatCoords[j] - flatCoords[i]) * (flatCoords[i+1] + flatCoords[j+1])
+	}
+	if ccw == (area > 0) {
+		for i := start; i < end; i += stride {
+			insert(i)
+		}
+	} else {
+		for i := end - stride; i >= start; i -= stride {
+			insert(i)
+		}
+	}
+	if last != nil && last.equals(last.next) {
+		next := last.next
+		last.remove()
+		last = next
+	}
+	return last
+}
+
+// remove removes n from its list.
+func (n *earNode) remove() {
+	n.next.prev = n.prev
+	n.prev.next = n.next
+}
+
+// equals returns whether n and m have the same x and y ordinates.
+func (n *earNode) equals(m *earNode) bool {
+	return n.x == m.x && n.y == m.y
+}
+
+// leftmost returns the leftmost node of n's list, with the lowest y in the
+// case of a tie.
+func (n *earNode) leftmost() *earNode {
+	leftmost := n
+	for p := n.next; p != n; p = p.next {
+		if p.x < leftmost.x || p.x == leftmost.x && p.y < leftmost.y {
+			leftmost = p
+		}
+	}
+	return leftmost
+}
+
+// earArea returns twice the signed area of the triangle p, q, r, which is
+// negative if the triangle is counterclockwise.
+func earArea(p, q, r *earNode) float64 {
+	return (q.y-p.y)*(r.x-q.x) - (q.x-p.x)*(r.y-q.y)
+}
+
+// pointInEarTriangle returns whether (px, py) is inside or on the boundary of
+// the counterclockwise triangle (ax, ay), (bx, by), (cx, cy).
+func pointInEarTriangle(ax, ay, bx, by, cx, cy, px, py float64) bool {
+	return (cx-px)*(ay-py) >= (ax-px)*(cy-py) &&
+		(ax-px)*(by-py) >= (bx-px)*(ay-py) &&
+		(bx-px)*(cy-py) >= (cx-px)*(by-py)
+}
+
+// filterEarNodes removes repeated and collinear nodes from start's list,
+// stopping at end, and returns a remaining node.
+func filterEarNodes(start, end *earNode) *earNode {
+	if end == nil {
+		end = start
+	}
+	p := start
+	for {
+		again := false
+		if p.equals(p.next) || earArea(p.prev, p, p.next) == 0 {
+			p.remove()
+			p, end = p.prev, p.prev
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+		if !again && p == end {
+			break
+		}
+	}
+	return end
+}
+
+// eliminateHoles links each hole, given by its leftmost node, into outer's
+// list with a pair of bridge edges and returns a node of the resulting list.
+func eliminateHoles(outer *earNode, holes []*earNode) *earNode {
+	sort.Slice(holes, func(i, j int) bool {
+		return holes[i].x < holes[j].x || holes[i].x == holes[j].x && holes[i].y < holes[j].y
+	})
+	for _, hole := range holes {
+		bridge := findHoleBridge(hole, outer)
+		if bridge == nil {
+			continue
+		}
+		bridgeReverse := splitEarRing(bridge, hole)
+		filterEarNodes(bridgeReverse, bridgeReverse.next)
+		outer = filterEarNodes(bridge, bridge.next)
+	}
+	return outer
+}
+
+// findHoleBridge returns a node of outer's list that is visible from hole,
+// the leftmost node of a hole, using David Eberly's algorithm.
+func findHoleBridge(hole, outer *earNode) *earNode {
+	hx, hy := hole.x, hole.y
+	qx := math.Inf(-1)
+	var m *earNode
+
+	// Find the segment intersected by a ray from the hole to the left.
+	p := outer
+	for {
+		if hy <= p.y && hy >= p.next.y && p.next.y != p.y {
+			x := p.x + (hy-p.y)*(p.next.x-p.x)/(p.next.y-p.y)
+			if x <= hx && x > qx {
+				qx = x
+				m = p
+				if p.next.x < p.x {
+					m = p.next
+				}
+				if x == hx {
+					return m
+				}
+			}
+		}
+		if p = p.next; p == outer {
+			break
+		}
+	}
+	if m == nil {
+		return nil
+	}
+
+	// Look for points inside the triangle of the hole point, the
+	// intersection, and the segment's endpoint. If there are any, use the
+	// one with the smallest angle to the ray.
+	stop := m
+	mx, my := m.x, m.y
+	tanMin := math.Inf(1)
+	p = m
+	for {
+		ax, cx := qx, hx
+		if hy < my {
+			ax, cx = hx, qx
+		}
+		if hx >= p.x && p.x >= mx && hx != p.x && pointInEarTriangle(ax, hy, mx, my, cx, hy, p.x, p.y) {
+			tan := math.Abs(hy-p.y) / (hx - p.x)
+			if locallyInside(p, hole) && (tan < tanMin || tan == tanMin && (p.x > m.x || p.x == m.x && sectorContainsSector(m, p))) {
+				m = p
+				tanMin = tan
+			}
+		}
+		if p = p.next; p == stop {
+			break
+		}
+	}
+	return m
+}
+
+// sectorContainsSector returns whether the sector at m contains the sector
+// at p.
+func sectorContainsSector(m, p *earNode) bool {
+	return earArea(m.prev, m, p.prev) < 0 && earArea(p.next, m, m.next) < 0
+}
+
+// splitEarRing links a to b with a pair of edges, splitting their list into
+// two if they are in the same list, and returns the copy of b.
+func splitEarRing(a, b *earNode) *earNode {
+	a2 := &earNode{i: a.i, x: a.x, y: a.y}
+	b2 := &earNode{i: b.i, x: b.x, y: b.y}
+	an, bp := a.next, b.prev
+	a.next, b.prev = b, a
+	a2.next, an.prev = an, a2
+	b2.next, a2.prev = a2, b2
+	bp.next, b2.prev = b2, bp
+	return b2
+}
+
+// earClip appends the triangles of ear's list to triangles. pass is the
+// number of times that the list has been repaired.
+func earClip(triangles [][3]int, ear *earNode, pass int) [][3]int {
+	if ear == nil {
+		return triangles
+	}
+	stop := ear
+	for ear.prev != ear.next {
+		prev, next := ear.prev, ear.next
+		if isEar(ear) {
+			triangles = append(triangles, [3]int{prev.i, ear.i, next.i})
+			ear.remove()
+			ear, stop = next.next, next.next
+			continue
+		}
+		ear = next
+		if ear == stop {
+			// There are no ears, so the list is degenerate or
+			// self-intersecting. Try to repair it.
+			switch pass {
+			case 0:
+				triangles = earClip(triangles, filterEarNodes(ear, nil), 1)
+			case 1:
+				var cured *earNode
+				triangles, cured = cureLocalIntersections(triangles, filterEarNodes(ear, nil))
+				triangles = earClip(triangles, cured, 2)
+			default:
+				triangles = splitEarClip(triangles, ear)
+			}
+			break
+		}
+	}
+	return triangles
+}
+
+// isEar returns whether ear is the middle vertex of an ear, a convex vertex
+// whose triangle contains no other reflex vertices.
+func isEar(ear *earNode) bool {
+	a, b, c := ear.prev, ear, ear.next
+	if earArea(a, b, c) >= 0 {
+		return false
+	}
+	x0, x1 := min(a.x, b.x, c.x), max(a.x, b.x, c.x)
+	y0, y1 := min(a.y, b.y, c.y), max(a.y, b.y, c.y)
+	for p := c.next; p != a; p = p.next {
+		if x0 <= p.x && p.x <= x1 && y0 <= p.y && p.y <= y1 &&
+			!p.equals(a) && pointInEarTriangle(a.x, a.y, b.x, b.y, c.x, c.y, p.x, p.y) &&
+			earArea(p.prev, p, p.next) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// cureLocalIntersections clips the triangles formed by pairs of crossing
+// consecutive edges of start's list.
+func cureLocalIntersections(triangles [][3]int, start *earNode) ([][3]int, *earNode) {
+	p := start
+	for {
+		a, b := p.prev, p.next.next
+		if !a.equals(b) && earSegmentsIntersect(a, p, p.next, b) && locallyInside(a, b) && locallyInside(b, a) {
+			triangles = append(triangles, [3]int{a.i, p.i, b.i})
+			p.next.remove()
+			p.remove()
+			p, start = b, b
+		}
+		if p = p.next; p == start {
+			break
+		}
+	}
+	return triangles, filterEarNodes(p, nil)
+}
+
+// splitEarClip splits start's list in two with a valid diagonal and clips
+// each part.
+func splitEarClip(triangles [][3]int, start *earNode) [][3]int {
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && isValidDiagonal(a, b) {
+				c := splitEarRing(a, b)
+				a = filterEarNodes(a, a.next)
+				c = filterEarNodes(c, c.next)
+				triangles = earClip(triangles, a, 0)
+				return earClip(triangles, c, 0)
+			}
+		}
+		if a = a.next; a == start {
+			return triangles
+		}
+	}
+}
+
+// isValidDiagonal returns whether the diagonal from a to b is inside the
+// polygon and does not cross any of its edges.
+func isValidDiagonal(a, b *earNode) bool {
+	return a.next.i != b.i && a.prev.i != b.i && !intersectsEarRing(a, b) &&
+		(locallyInside(a, b) && locallyInside(b, a) && middleInside(a, b) &&
+			(earArea(a.prev, a, b.prev) != 0 || earArea(a, b.prev, b) != 0) ||
+			a.equals(b) && earArea(a.prev, a, a.next) > 0 && earArea(b.prev, b, b.next) > 0)
+}
+
+// earSegmentsIntersect returns whether the segments p1-q1 and p2-q2
+// intersect.
+func earSegmentsIntersect(p1, q1, p2, q2 *earNode) bool {
+	sign := func(x float64) int {
+		switch {
+		case x > 0:
+			return 1
+		case x < 0:
+			return -1
+		default:
+			return 0
+		}
+	}
+	onSegment := func(p, q, r *earNode) bool {
+		return q.x <= max(p.x, r.x) && q.x >= min(p.x, r.x) && q.y <= max(p.y, r.y) && q.y >= min(p.y, r.y)
+	}
+	o1 := sign(earArea(p1, q1, p2))
+	o2 := sign(earArea(p1, q1, q2))
+	o3 := sign(earArea(p2, q2, p1))
+	o4 := sign(earArea(p2, q2, q1))
+	return o1 != o2 && o3 != o4 ||
+		o1 == 0 && onSegment(p1, p2, q1) ||
+		o2 == 0 && onSegment(p1, q2, q1) ||
+		o3 == 0 && onSegment(p2, p1, q2) ||
+		o4 == 0 && onSegment(p2, q1, q2)
+}
+
+// intersectsEarRing returns whether the diagonal from a to b crosses an edge
+// of a's list.
+func intersectsEarRing(a, b *earNode) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i && earSegmentsIntersect(p, p.next, a, b) {
+			return true
+		}
+		if p = p.next; p == a {
+			return false
+		}
+	}
+}
+
+// locallyInside returns whether the diagonal from a to b starts inside the
+// polygon at a.
+func locallyInside(a, b *earNode) bool {
+	if earArea(a.prev, a, a.next) < 0 {
+		return earArea(a, b, a.next) >= 0 && earArea(a, a.prev, b) >= 0
+	}
+	return earArea(a, b, a.prev) < 0 || earArea(a, a.next, b) < 0
+}
+
+// middleInside returns whether the midpoint of the diagonal from a to b is
+// inside the polygon.
+func middleInside(a, b *earNode) bool {
+	inside := false
+	px, py := (a.x+b.x)/2, (a.y+b.y)/2
+	p := a
+	for {
+		if (p.y > py) != (p.next.y > py) && p.next.y != p.y && px < (p.next.x-p.x)*(py-p.y)/(p.next.y-p.y)+p.x {
+			inside = !inside
+		}
+		if p = p.next; p == a {
+			return inside
+		}
+	}
+}
+
+// A triangulation is a set of counterclockwise triangles of the vertices of
+// flat coordinates with the adjacency of their edges.
+type triangulation struct {
+	flatCoords []float64
+	stride     int
+	triangles  [][3]int
+	halfEdges  map[[2]int]int
+}
+
+// newTriangulation returns a new empty triangulation of the vertices of
+// flatCoords.
+func newTriangulation(flatCoords []float64, stride int) *triangulation {
+	return &triangulation{
+		flatCoords: flatCoords,
+		stride:     stride,
+		halfEdges:  make(map[[2]int]int),
+	}
+}
+
+// xy returns the x and y ordinates of vertex i.
+func (t *triangulation) xy(i int) (float64, float64) {
+	return t.flatCoords[i*t.stride], t.flatCoords[i*t.stride+1]
+}
+
+// addTriangle adds the counterclockwise triangle a, b, c.
+func (t *triangulation) addTriangle(a, b, c int) {
+	t.triangles = append(t.triangles, [3]int{})
+	t.setTriangle(len(t.triangles)-1, a, b, c)
+}
+
+// setTriangle sets triangle i to the counterclockwise triangle a, b, c.
+func (t *triangulation) setTriangle(i, a, b, c int) {
+	t.triangles[i] = [3]int{a, b, c}
+	t.halfEdges[[2]int{a, b}] = i
+	t.halfEdges[[2]int{b, c}] = i
+	t.halfEdges[[2]int{c, a}] = i
+}
+
+// opposite returns the vertex of the triangle to the left of the edge from a
+// to b that is not a or b, and whether there is such a triangle.
+func (t *triangulation) opposite(a, b int) (int, bool) {
+	i, ok := t.halfEdges[[2]int{a, b}]
+	if !ok {
+		return 0, false
+	}
+	for _, v := range t.triangles[i] {
+		if v != a && v != b {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// orient returns twice the signed area of the triangle a, b, c, which is
+// positive if the triangle is counterclockwise.
+func (t *triangulation) orient(a, b, c int) float64 {
+	ax, ay := t.xy(a)
+	bx, by := t.xy(b)
+	cx, cy := t.xy(c)
+	return (bx-ax)*(cy-ay) - (by-ay)*(cx-ax)
+}
+
+// inCircle returns whether vertex d is strictly inside the circumcircle of
+// the counterclockwise triangle a, b, c, allowing for rounding errors.
+func (t *triangulation) inCircle(a, b, c, d int) bool {
+	ax, ay := t.xy(a)
+	bx, by := t.xy(b)
+	cx, cy := t.xy(c)
+	dx, dy := t.xy(d)
+	adx, ady := ax-dx, ay-dy
+	bdx, bdy := bx-dx, by-dy
+	cdx, cdy := cx-dx, cy-dy
+	alift := adx*adx + ady*ady
+	blift := bdx*bdx + bdy*bdy
+	clift := cdx*cdx + cdy*cdy
+	det := alift*(bdx*cdy-cdx*bdy) + blift*(cdx*ady-adx*cdy) + clift*(adx*bdy-bdx*ady)
+	permanent := alift*(math.Abs(bdx*cdy)+math.Abs(cdx*bdy)) +
+		blift*(math.Abs(cdx*ady)+math.Abs(adx*cdy)) +
+		clift*(math.Abs(adx*bdy)+math.Abs(bdx*ady))
+	return det > 1e-12*permanent
+}
+
+// legalizeAll flips edges until every edge between two triangles is locally
+// Delaunay.
+func (t *triangulation) legalizeAll() {
+	edges := make([][2]int, 0, 3*len(t.triangles))
+	for _, triangle := range t.triangles {
+		edges = append(edges, [2]int{triangle[0], triangle[1]}, [2]int{triangle[1], triangle[2]}, [2]int{triangle[2], triangle[0]})
+	}
+	t.legalize(edges)
+}
+
+// legalize flips edges, starting with edges, until every edge between two
+// triangles is locally Delaunay.
+func (t *triangulation) legalize(edges [][2]int) {
+	for len(edges) > 0 {
+		a, b := edges[len(edges)-1][0], edges[len(edges)-1][1]
+		edges = edges[:len(edges)-1]
+		c, ok := t.opposite(a, b)
+		if !ok {
+			continue
+		}
+		d, ok := t.opposite(b, a)
+		if !ok || !t.inCircle(a, b, c, d) {
+			continue
+		}
+		// The quadrilateral a, d, b, c must be strictly convex for the
+		// flip to be valid.
+		if t.orient(a, d, c) <= 0 || t.orient(d, b, c) <= 0 {
+			continue
+		}
+		i, j := t.halfEdges[[2]int{a, b}], t.halfEdges[[2]int{b, a}]
+		delete(t.halfEdges, [2]int{a, b})
+		delete(t.halfEdges, [2]int{b, a})
+		t.setTriangle(i, c, a, d)
+		t.setTriangle(j, d, b, c)
+		edges = append(edges, [2]int{c, a}, [2]int{a, d}, [2]int{d, b}, [2]int{b, c})
+	}
+}
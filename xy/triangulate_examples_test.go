@@ -0,0 +1,21 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleTriangulateIndices() {
+	polygon := geom.NewPolygonFlat(geom.XY, []float64{
+		0, 0, 3, 0, 3, 3, 0, 3, 0, 0,
+		1, 1, 1, 2, 2, 2, 2, 1, 1, 1,
+	}, []int{10, 20})
+	indices := xy.TriangulateIndices(polygon, xy.TriangulationConstrainedDelaunay)
+	fmt.Println(len(indices)/3, "triangles")
+	fmt.Println(xy.Triangulate(polygon, xy.TriangulationConstrainedDelaunay).Area())
+	// Output:
+	// 8 triangles
+	// 8
+}
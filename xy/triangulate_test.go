@@ -0,0 +1,150 @@
+package xy_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+// triangleArea returns twice the signed area of the triangle with vertices
+// i, j, and k of flatCoords.
+func triangleArea(flatCoords []float64, stride, i, j, k int) float64 {
+	ax, ay := flatCoords[i*stride], flatCoords[i*stride+1]
+	bx, by := flatCoords[j*stride], flatCoords[j*stride+1]
+	cx, cy := flatCoords[k*stride], flatCoords[k*stride+1]
+	return (bx-ax)*(cy-ay) - (by-ay)*(cx-ax)
+}
+
+func TestTriangulateIndices(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		polygon           *geom.Polygon
+		expectedTriangles int
+		expectedArea      float64
+	}{
+		{
+			name:    "empty",
+			polygon: geom.NewPolygon(geom.XY),
+		},
+		{
+			name:    "degenerate",
+			polygon: geom.NewPolygonFlat(geom.XY, []float64{0, 0, 1, 1, 2, 2, 0, 0}, []int{8}),
+		},
+		{
+			name:              "triangle",
+			polygon:           geom.NewPolygonFlat(geom.XY, []float64{0, 0, 1, 0, 0, 1, 0, 0}, []int{8}),
+			expectedTriangles: 1,
+			expectedArea:      0.5,
+		},
+		{
+			name:              "square",
+			polygon:           geom.NewPolygonFlat(geom.XY, []float64{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}, []int{10}),
+			expectedTriangles: 2,
+			expectedArea:      4,
+		},
+		{
+			name:              "clockwise_unclosed",
+			polygon:           geom.NewPolygonFlat(geom.XY, []float64{0, 0, 0, 2, 2, 2, 2, 0}, []int{8}),
+			expectedTriangles: 2,
+			expectedArea:      4,
+		},
+		{
+			name:              "concave",
+			polygon:           geom.NewPolygonFlat(geom.XY, []float64{0, 0, 4, 0, 4, 4, 2, 1, 0, 4, 0, 0}, []int{12}),
+			expectedTriangles: 3,
+			expectedArea:      10,
+		},
+		{
+			name: "hole",
+			polygon: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 4, 0, 4, 4, 0, 4, 0, 0,
+				1, 1, 1, 3, 3, 3, 3, 1, 1, 1,
+			}, []int{10, 20}),
+			expectedTriangles: 8,
+			expectedArea:      12,
+		},
+		{
+			name: "two_holes",
+			polygon: geom.NewPolygonFlat(geom.XYZ, []float64{
+				0, 0, 0, 10, 0, 0, 10, 4, 0, 0, 4, 0, 0, 0, 0,
+				1, 1, 0, 3, 1, 0, 3, 3, 0, 1, 3, 0, 1, 1, 0,
+				6, 1.5, 0, 8, 1, 0, 7, 3, 0, 6, 1.5, 0,
+			}, []int{15, 30, 42}),
+			expectedTriangles: 13,
+			expectedArea:      40 - 4 - 1.75,
+		},
+		{
+			name: "repeated_points",
+			polygon: geom.NewPolygonFlat(geom.XY, []float64{
+				0, 0, 0, 0, 2, 0, 2, 0, 2, 2, 0, 2, 0, 0,
+			}, []int{14}),
+			expectedTriangles: 2,
+			expectedArea:      4,
+		},
+	} {
+		for _, method := range []xy.TriangulationMethod{xy.TriangulationEarClipping, xy.TriangulationConstrainedDelaunay} {
+			t.Run(tc.name, func(t *testing.T) {
+				indices := xy.TriangulateIndices(tc.polygon, method)
+				if len(indices) != 3*tc.expectedTriangles {
+					t.Fatalf("expected %d triangles, got %v", tc.expectedTriangles, indices)
+				}
+				area := 0.0
+				for i := 0; i < len(indices); i += 3 {
+					triangleArea := triangleArea(tc.polygon.FlatCoords(), tc.polygon.Stride(), indices[i], indices[i+1], indices[i+2])
+					if triangleArea <= 0 {
+						t.Errorf("triangle %v is not counterclockwise", indices[i:i+3])
+					}
+					area += triangleArea / 2
+				}
+				if math.Abs(area-tc.expectedArea) > 1e-9 {
+					t.Errorf("expected area %v, got %v", tc.expectedArea, area)
+				}
+			})
+		}
+	}
+}
+
+func TestTriangulateConstrainedDelaunay(t *testing.T) {
+	// Ear clipping a regular polygon produces a fan of long, thin
+	// triangles, but its Delaunay triangulation has no vertex of any
+	// triangle inside the circumcircle of any other triangle.
+	n := 16
+	flatCoords := make([]float64, 0, 2*(n+1))
+	for i := range n + 1 {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i%n) / float64(n))
+		flatCoords = append(flatCoords, 10*cos, 5*sin)
+	}
+	polygon := geom.NewPolygonFlat(geom.XY, flatCoords, []int{len(flatCoords)})
+	indices := xy.TriangulateIndices(polygon, xy.TriangulationConstrainedDelaunay)
+	if len(indices) != 3*(n-2) {
+		t.Fatalf("expected %d triangles, got %d", n-2, len(indices)/3)
+	}
+	for i := 0; i < len(indices); i += 3 {
+		ax, ay := flatCoords[2*indices[i]], flatCoords[2*indices[i]+1]
+		bx, by := flatCoords[2*indices[i+1]], flatCoords[2*indices[i+1]+1]
+		cx, cy := flatCoords[2*indices[i+2]], flatCoords[2*indices[i+2]+1]
+		for j := range n {
+			dx, dy := flatCoords[2*j], flatCoords[2*j+1]
+			adx, ady := ax-dx, ay-dy
+			bdx, bdy := bx-dx, by-dy
+			cdx, cdy := cx-dx, cy-dy
+			det := (adx*adx+ady*ady)*(bdx*cdy-cdx*bdy) + (bdx*bdx+bdy*bdy)*(cdx*ady-adx*cdy) + (cdx*cdx+cdy*cdy)*(adx*bdy-bdx*ady)
+			if det > 1e-9 {
+				t.Errorf("vertex %d is inside the circumcircle of %v", j, indices[i:i+3])
+			}
+		}
+	}
+}
+
+func TestTriangulate(t *testing.T) {
+	polygon := geom.NewPolygonFlat(geom.XYM, []float64{0, 0, 1, 2, 0, 2, 0, 2, 3, 0, 0, 1}, []int{12}).SetSRID(4326)
+	expected := geom.NewMultiPolygonFlat(geom.XYM, []float64{
+		0, 2, 3, 0, 0, 1, 2, 0, 2, 0, 2, 3,
+	}, [][]int{{12}}).SetSRID(4326)
+	if got := xy.Triangulate(polygon, xy.TriangulationEarClipping); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected %v, got %v", expected.FlatCoords(), got.FlatCoords())
+	}
+}
@@ -0,0 +1,185 @@
+package xy
+
+import (
+	"math"
+	"sort"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal"
+	"github.com/twpayne/go-geom/xy/internal/planargraph"
+)
+
+// unionLeafSize is the maximum number of polygons that are unioned in a
+// single planar graph.
+const unionLeafSize = 8
+
+// A unionPolygon is a polygon, as xy coordinate rings, and its bounds.
+type unionPolygon struct {
+	rings [][]float64
+	box   internal.Box
+}
+
+// UnaryUnion returns the union of the polygons in geoms, which may be
+// *geom.Polygons, *geom.MultiPolygons, or *geom.GeometryCollections of
+// them. Overlapping and adjacent polygons are merged.
+//
+// The polygons are recursively partitioned by their bounds into small groups
+// that are unioned together, and then the results of neighboring groups are
+// merged. When merging, only polygons whose bounds overlap the bounds of the
+// other group are unioned, so the cost grows with the number of polygons
+// that actually interact rather than with the total number of polygons.
+//
+// The result is a *geom.Polygon, a *geom.MultiPolygon if there is more than
+// one Polygon, or an empty *geom.Polygon if geoms contains no polygons. It is
+// computed from the x and y ordinates only, so it has layout geom.XY, and it
+// has the SRID of the first geometry. A geom.ErrUnsupportedType error is
+// returned if any geometry is not polygonal.
+func UnaryUnion(geoms ...geom.T) (geom.T, error) {
+	var polygons []unionPolygon
+	for _, g := range geoms {
+		var err error
+		if polygons, err = appendUnionPolygons(polygons, g); err != nil {
+			return nil, err
+		}
+	}
+	srid := 0
+	if len(geoms) > 0 {
+		srid = geoms[0].SRID()
+	}
+
+	return unionPolygonal(cascadedUnion(polygons), srid), nil
+}
+
+// unionPolygonal returns polygons, which must not overlap, as a polygonal
+// geometry ordered by their lowest point.
+func unionPolygonal(polygons []unionPolygon, srid int) geom.T {
+	sort.Slice(polygons, func(i, j int) bool {
+		ri, rj := polygons[i].rings[0], polygons[j].rings[0]
+		return ri[0] < rj[0] || (ri[0] == rj[0] && ri[1] < rj[1])
+	})
+	rings := make([][][]float64, len(polygons))
+	for i, p := range polygons {
+		rings[i] = p.rings
+	}
+	return polygonal(rings, srid)
+}
+
+// appendUnionPolygons appends the non-empty polygons of g to polygons.
+func appendUnionPolygons(polygons []unionPolygon, g geom.T) ([]unionPolygon, error) {
+	switch g := g.(type) {
+	case *geom.Polygon:
+		if g.NumLinearRings() == 0 {
+			return polygons, nil
+		}
+		rings := polygonRings(g)
+		return append(polygons, unionPolygon{rings: rings, box: ringBox(rings[0])}), nil
+	case *geom.MultiPolygon:
+		for i := range g.NumPolygons() {
+			polygons, _ = appendUnionPolygons(polygons, g.Polygon(i))
+		}
+		return polygons, nil
+	case *geom.GeometryCollection:
+		for _, child := range g.Geoms() {
+			var err error
+			if polygons, err = appendUnionPolygons(polygons, child); err != nil {
+				return nil, err
+			}
+		}
+		return polygons, nil
+	default:
+		return nil, geom.ErrUnsupportedType{Value: g}
+	}
+}
+
+// cascadedUnion returns the union of polygons. polygons is reordered.
+func cascadedUnion(polygons []unionPolygon) []unionPolygon {
+	if len(polygons) <= unionLeafSize {
+		return unionPolygons(polygons)
+	}
+
+	// Split the polygons in half along the longer axis of their bounds.
+	box := boundsOf(polygons)
+	axis := 0
+	if box.MaxY-box.MinY > box.MaxX-box.MinX {
+		axis = 1
+	}
+	center := func(p unionPolygon) float64 {
+		if axis == 0 {
+			return p.box.MinX + p.box.MaxX
+		}
+		return p.box.MinY + p.box.MaxY
+	}
+	sort.Slice(polygons, func(i, j int) bool {
+		return center(polygons[i]) < center(polygons[j])
+	})
+	half := len(polygons) / 2
+	left := cascadedUnion(polygons[:half])
+	right := cascadedUnion(polygons[half:])
+
+	// Only union the polygons that might interact with the other half.
+	leftBox, rightBox := boundsOf(left), boundsOf(right)
+	var candidates, result []unionPolygon
+	for _, p := range left {
+		if p.box.Overlaps(rightBox) {
+			candidates = append(candidates, p)
+		} else {
+			result = append(result, p)
+		}
+	}
+	numLeftCandidates := len(candidates)
+	for _, p := range right {
+		if p.box.Overlaps(leftBox) {
+			candidates = append(candidates, p)
+		} else {
+			result = append(result, p)
+		}
+	}
+	if numLeftCandidates == 0 || numLeftCandidates == len(candidates) {
+		return append(result, candidates...)
+	}
+	b := planargraph.NewBuilder(2)
+	for i, p := range candidates {
+		operand := 0
+		if i >= numLeftCandidates {
+			operand = 1
+		}
+		for _, ring := range p.rings {
+			b.AddRing(operand, ring)
+		}
+	}
+	return append(result, unionGraph(b, insideAny)...)
+}
+
+// unionPolygons returns the union of polygons in a single planar graph,
+// with each polygon as a separate operand.
+func unionPolygons(polygons []unionPolygon) []unionPolygon {
+	if len(polygons) == 0 {
+		return nil
+	}
+	b := planargraph.NewBuilder(len(polygons))
+	for i, p := range polygons {
+		for _, ring := range p.rings {
+			b.AddRing(i, ring)
+		}
+	}
+	return unionGraph(b, insideAny)
+}
+
+// unionGraph returns the polygons of the area of b for which inArea returns
+// true.
+func unionGraph(b *planargraph.Builder, inArea func(inside []bool) bool) []unionPolygon {
+	var polygons []unionPolygon
+	for _, p := range planargraph.Polygons(b.Build().AreaRings(inArea)) {
+		polygons = append(polygons, unionPolygon{rings: p, box: ringBox(p[0])})
+	}
+	return polygons
+}
+
+// boundsOf returns the bounds of polygons.
+func boundsOf(polygons []unionPolygon) internal.Box {
+	box := internal.Box{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	for _, p := range polygons {
+		box = box.Union(p.box)
+	}
+	return box
+}
@@ -0,0 +1,22 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleUnaryUnion() {
+	parcels := []geom.T{
+		geom.NewPolygonFlat(geom.XY, []float64{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}, []int{10}),
+		geom.NewPolygonFlat(geom.XY, []float64{1, 0, 2, 0, 2, 1, 1, 1, 1, 0}, []int{10}),
+		geom.NewPolygonFlat(geom.XY, []float64{0, 1, 2, 1, 2, 2, 0, 2, 0, 1}, []int{10}),
+	}
+	union, err := xy.UnaryUnion(parcels...)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(union.FlatCoords())
+	// Output: [0 0 1 0 2 0 2 1 2 2 0 2 0 1 0 0]
+}
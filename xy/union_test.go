@@ -0,0 +1,122 @@
+package xy_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func TestUnaryUnion(t *testing.T) {
+	square := func(x, y, size float64) *geom.Polygon {
+		return geom.NewPolygonFlat(geom.XY, []float64{x, y, x + size, y, x + size, y + size, x, y + size, x, y}, []int{10})
+	}
+	for _, tc := range []struct {
+		name     string
+		geoms    []geom.T
+		expected geom.T
+	}{
+		{
+			name:     "none",
+			expected: geom.NewPolygon(geom.XY),
+		},
+		{
+			name: "single_clockwise",
+			geoms: []geom.T{
+				geom.NewPolygonFlat(geom.XY, []float64{0, 0, 0, 1, 1, 1, 1, 0, 0, 0}, []int{10}).SetSRID(4326),
+			},
+			expected: square(0, 0, 1).SetSRID(4326),
+		},
+		{
+			name: "adjacent_and_disjoint",
+			geoms: []geom.T{
+				square(5, 5, 1),
+				geom.NewMultiPolygonFlat(geom.XY, []float64{
+					0, 0, 1, 0, 1, 1, 0, 1, 0, 0,
+					1, 0, 2, 0, 2, 1, 1, 1, 1, 0,
+				}, [][]int{{10}, {20}}),
+				geom.NewGeometryCollection().MustPush(square(0.5, 0.5, 1)),
+			},
+			expected: geom.NewMultiPolygonFlat(geom.XY, []float64{
+				0, 0, 1, 0, 2, 0, 2, 1, 1.5, 1, 1.5, 1.5, 0.5, 1.5, 0.5, 1, 0, 1, 0, 0,
+				5, 5, 6, 5, 6, 6, 5, 6, 5, 5,
+			}, [][]int{{20}, {30}}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := xy.UnaryUnion(tc.geoms...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnaryUnionNearlyCollinear(t *testing.T) {
+	// Pieces of the buffer of a curve, with vertices that lie within
+	// rounding error of the nearly collinear edges of other pieces.
+	mp := geom.NewMultiPolygonFlat(geom.XY, []float64{
+		0.5507222340845555, 6.169549870612377, 3.4492777659154443, 5.081019031684233, 4.449277765915444, 7.743833375727114, 1.5507222340845555, 8.832364214655257, 0.5507222340845555, 6.169549870612377,
+		1.5636907677092293, 8.8657203658043, 4.436309232290771, 7.710477224578071, 5.436309232290771, 10.197069321140887, 2.5636907677092293, 11.352312462367117, 1.5636907677092293, 8.8657203658043,
+		2.584548677494839, 11.401690447750381, 5.415451322505161, 10.147691335757623, 6.415451322505161, 12.405191056400703, 3.584548677494839, 13.659190168393462, 2.584548677494839, 11.401690447750381,
+		3.6180772919750535, 13.729987515977388, 6.3819227080249465, 12.334393708816776, 7.3819227080249465, 14.31480190629441, 4.6180772919750535, 15.710395713455021, 3.6180772919750535, 13.729987515977388,
+		14.14952873840749, 19.552665521872033, 11.85047126159251, 17.47881444797444, 12.85047126159251, 16.37022111369314, 15.14952873840749, 18.444072187590734, 14.14952873840749, 19.552665521872033,
+		17.39984355746969, 14.77217560270405, 14.600156442530311, 13.449951863374174, 15.600156442530311, 11.332543869808498, 18.39984355746969, 12.654767609138373, 17.39984355746969, 14.77217560270405,
+		2, 5.625284451148305, 0.5431283040042305, 6.148883296647103, 0.5507222340845555, 6.169549870612377, 2, 5.625284451148305,
+		5, 13.032190612397082, 3.584548677494839, 13.659190168393462, 3.6180772919750535, 13.729987515977388, 5, 13.032190612397082,
+		17, 11.993655739473436, 18.39984355746969, 12.654767609138373, 18.42655496893794, 12.594964154681252, 17, 11.993655739473436,
+	}, [][]int{{10}, {20}, {30}, {40}, {50}, {60}, {68}, {76}, {84}})
+	got, err := xy.UnaryUnion(mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := xy.Validate(got); err != nil {
+		t.Errorf("expected valid union, got %v", err)
+	}
+}
+
+func TestUnaryUnionGrid(t *testing.T) {
+	// A 20 by 20 grid of unit squares with the center 4 by 4 squares
+	// missing, plus some squares that overlap several others.
+	var geoms []geom.T
+	for i := range 20 {
+		for j := range 20 {
+			if 8 <= i && i < 12 && 8 <= j && j < 12 {
+				continue
+			}
+			x, y := float64(i), float64(j)
+			geoms = append(geoms, geom.NewPolygonFlat(geom.XY, []float64{x, y, x + 1, y, x + 1, y + 1, x, y + 1, x, y}, []int{10}))
+		}
+	}
+	for _, c := range []float64{2.5, 14.5} {
+		geoms = append(geoms, geom.NewPolygonFlat(geom.XY, []float64{c, c, c + 3, c, c + 3, c + 3, c, c + 3, c, c}, []int{10}))
+	}
+	got, err := xy.UnaryUnion(geoms...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := xy.Validate(got); err != nil {
+		t.Fatal(err)
+	}
+	polygon, ok := got.(*geom.Polygon)
+	if !ok {
+		t.Fatalf("expected a *geom.Polygon but got %T", got)
+	}
+	if n := polygon.NumLinearRings(); n != 2 {
+		t.Errorf("expected 2 rings but got %d", n)
+	}
+	if area := polygon.Area(); math.Abs(area-384) > 1e-9 {
+		t.Errorf("expected area 384 but got %f", area)
+	}
+}
+
+func TestUnaryUnionUnsupportedType(t *testing.T) {
+	if _, err := xy.UnaryUnion(geom.NewLineStringFlat(geom.XY, []float64{0, 0, 1, 1})); err == nil {
+		t.Error("expected an error")
+	}
+}
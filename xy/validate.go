@@ -0,0 +1,568 @@
+package xy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy/internal"
+	"github.com/twpayne/go-geom/xy/lineintersection"
+	"github.com/twpayne/go-geom/xy/lineintersector"
+	"github.com/twpayne/go-geom/xy/location"
+	"github.com/twpayne/go-geom/xy/validity"
+)
+
+// A ValidationError describes why a geometry is not valid or not simple.
+type ValidationError struct {
+	// Reason is the kind of problem.
+	Reason validity.Type
+	// Coord is the xy location of the problem, or nil if the problem has no
+	// single location.
+	Coord geom.Coord
+}
+
+func (e *ValidationError) Error() string {
+	if e.Coord == nil {
+		return "xy: " + e.Reason.String()
+	}
+	return fmt.Sprintf("xy: %s at %v", e.Reason, []float64(e.Coord))
+}
+
+// IsValid returns true if g is valid according to the OGC Simple Features
+// specification. Use Validate to find out why a geometry is not valid.
+func IsValid(g geom.T) bool {
+	return Validate(g) == nil
+}
+
+// Validate checks that g is valid according to the OGC Simple Features
+// specification. It returns nil if g is valid, a *ValidationError describing
+// the first problem found if g is not valid, or a geom.ErrUnsupportedType if
+// the type of g is not supported.
+//
+// The following conditions are checked:
+//   - all coordinates are finite;
+//   - LineStrings have at least two distinct points;
+//   - rings are closed, have at least three distinct points, and do not
+//     cross or touch themselves;
+//   - the rings of a Polygon do not cross, holes lie inside the shell and
+//     outside each other, and the interior is connected;
+//   - the Polygons of a MultiPolygon do not overlap and their shells are not
+//     nested.
+//
+// Empty geometries are valid.
+func Validate(g geom.T) error {
+	if err := checkSupported(g); err != nil {
+		return err
+	}
+	if g.Empty() {
+		return nil
+	}
+	var err *ValidationError
+	stride := g.Stride()
+	switch g := g.(type) {
+	case *geom.Point:
+		err = validateCoords(g.FlatCoords(), stride)
+	case *geom.MultiPoint:
+		err = validateCoords(g.FlatCoords(), stride)
+	case *geom.LineString:
+		err = validateLineString(g.FlatCoords(), stride)
+	case *geom.MultiLineString:
+		for i := 0; i < g.NumLineStrings() && err == nil; i++ {
+			err = validateLineString(g.LineString(i).FlatCoords(), stride)
+		}
+	case *geom.LinearRing:
+		err = validateRing(g.FlatCoords(), stride)
+	case *geom.Polygon:
+		err = validatePolygon(g)
+	case *geom.MultiPolygon:
+		err = validateMultiPolygon(g)
+	case *geom.GeometryCollection:
+		for _, child := range g.Geoms() {
+			if err := Validate(child); err != nil {
+				return err
+			}
+		}
+	default:
+		return geom.ErrUnsupportedType{Value: g}
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsSimple returns true if g is simple according to the OGC Simple Features
+// specification. Use ValidateSimple to find out why a geometry is not
+// simple.
+func IsSimple(g geom.T) bool {
+	return ValidateSimple(g) == nil
+}
+
+// ValidateSimple checks that g is simple according to the OGC Simple
+// Features specification, i.e. that it has no anomalous points such as
+// self-intersections. It returns nil if g is simple, a *ValidationError
+// describing the first anomalous point found if g is not simple, or a
+// geom.ErrUnsupportedType if the type of g is not supported.
+//
+// Points are always simple. MultiPoints are simple if they contain no
+// repeated points. LineStrings and LinearRings are simple if they do not
+// intersect themselves, except at their endpoints if they are closed.
+// MultiLineStrings are simple if their LineStrings are simple and only meet
+// at points that are endpoints of both. Polygons and MultiPolygons are simple
+// if their rings are simple.
+func ValidateSimple(g geom.T) error {
+	if err := checkSupported(g); err != nil {
+		return err
+	}
+	if g.Empty() {
+		return nil
+	}
+	stride := g.Stride()
+	switch g := g.(type) {
+	case *geom.Point:
+		return nil
+	case *geom.MultiPoint:
+		seen := make(map[[2]float64]struct{})
+		flatCoords := g.FlatCoords()
+		for i := 0; i < len(flatCoords); i += stride {
+			key := [2]float64{flatCoords[i], flatCoords[i+1]}
+			if _, ok := seen[key]; ok {
+				return &ValidationError{Reason: validity.RepeatedPoint, Coord: geom.Coord{key[0], key[1]}}
+			}
+			seen[key] = struct{}{}
+		}
+		return nil
+	case *geom.LineString:
+		return validateSimpleLines([][]float64{xyCoords(g.FlatCoords(), stride)}, false)
+	case *geom.LinearRing:
+		return validateSimpleLines([][]float64{xyCoords(g.FlatCoords(), stride)}, false)
+	case *geom.MultiLineString:
+		lines := make([][]float64, 0, g.NumLineStrings())
+		for i := range g.NumLineStrings() {
+			lines = append(lines, xyCoords(g.LineString(i).FlatCoords(), stride))
+		}
+		return validateSimpleLines(lines, true)
+	case *geom.Polygon:
+		for i := range g.NumLinearRings() {
+			if err := validateSimpleLines([][]float64{xyCoords(g.LinearRing(i).FlatCoords(), stride)}, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *geom.MultiPolygon:
+		for i := range g.NumPolygons() {
+			if err := ValidateSimple(g.Polygon(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *geom.GeometryCollection:
+		for _, child := range g.Geoms() {
+			if err := ValidateSimple(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return geom.ErrUnsupportedType{Value: g}
+	}
+}
+
+// validateSimpleLines checks that each of lines does not intersect itself,
+// except at shared vertices of adjacent segments. If interLine is true then
+// different lines must also not intersect, except at points that are
+// endpoints of both.
+func validateSimpleLines(lines [][]float64, interLine bool) error {
+	var err *ValidationError
+	forEachSegmentIntersection(lines, func(a, b segmentRef, result lineintersection.Result) bool {
+		if a.line == b.line {
+			if reason := selfIntersectionReason(lines[a.line], a.index, b.index, result); reason != validity.Valid {
+				err = &ValidationError{Reason: validity.SelfIntersection, Coord: intersectionCoord(result)}
+			}
+		} else if interLine {
+			if result.Type() != lineintersection.PointIntersection ||
+				!isLineEndpoint(lines[a.line], result.Intersection()[0]) ||
+				!isLineEndpoint(lines[b.line], result.Intersection()[0]) {
+				err = &ValidationError{Reason: validity.SelfIntersection, Coord: intersectionCoord(result)}
+			}
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// isLineEndpoint returns true if c is an endpoint of the open line.
+func isLineEndpoint(line []float64, c geom.Coord) bool {
+	n := len(line)
+	if internal.Equal(line, 0, line, n-2) {
+		return false
+	}
+	return internal.Equal(line, 0, c, 0) || internal.Equal(line, n-2, c, 0)
+}
+
+func validateCoords(flatCoords []float64, stride int) *ValidationError {
+	for i := 0; i < len(flatCoords); i += stride {
+		x, y := flatCoords[i], flatCoords[i+1]
+		if math.IsNaN(x) || math.IsInf(x, 0) || math.IsNaN(y) || math.IsInf(y, 0) {
+			return &ValidationError{Reason: validity.InvalidCoordinate, Coord: geom.Coord{x, y}}
+		}
+	}
+	return nil
+}
+
+func validateLineString(flatCoords []float64, stride int) *ValidationError {
+	if err := validateCoords(flatCoords, stride); err != nil {
+		return err
+	}
+	if len(flatCoords) == 0 {
+		return nil
+	}
+	if len(xyCoords(flatCoords, stride)) < 4 {
+		return &ValidationError{Reason: validity.TooFewPoints, Coord: geom.Coord{flatCoords[0], flatCoords[1]}}
+	}
+	return nil
+}
+
+func validateRing(flatCoords []float64, stride int) *ValidationError {
+	if err := validateCoords(flatCoords, stride); err != nil {
+		return err
+	}
+	if len(flatCoords) == 0 {
+		return &ValidationError{Reason: validity.TooFewPoints}
+	}
+	start := geom.Coord{flatCoords[0], flatCoords[1]}
+	if !internal.Equal(flatCoords, 0, flatCoords, len(flatCoords)-stride) {
+		return &ValidationError{Reason: validity.RingNotClosed, Coord: start}
+	}
+	ring := xyCoords(flatCoords, stride)
+	if len(ring) < 8 || len(flatCoords) < 4*stride {
+		return &ValidationError{Reason: validity.TooFewPoints, Coord: start}
+	}
+	var err *ValidationError
+	forEachSegmentIntersection([][]float64{ring}, func(a, b segmentRef, result lineintersection.Result) bool {
+		if reason := selfIntersectionReason(ring, a.index, b.index, result); reason != validity.Valid {
+			err = &ValidationError{Reason: reason, Coord: intersectionCoord(result)}
+		}
+		return err == nil
+	})
+	return err
+}
+
+// selfIntersectionReason classifies the intersection between the ith and jth
+// segments of line, where i < j. Adjacent segments may only meet at their
+// shared vertex.
+func selfIntersectionReason(line []float64, i, j int, result lineintersection.Result) validity.Type {
+	numSegments := len(line)/2 - 1
+	closed := internal.Equal(line, 0, line, len(line)-2)
+	adjacent := j == i+1 || (closed && i == 0 && j == numSegments-1 && numSegments > 2)
+	if adjacent && result.Type() == lineintersection.PointIntersection {
+		return validity.Valid
+	}
+	return validity.RingSelfIntersection
+}
+
+func validatePolygon(p *geom.Polygon) *ValidationError {
+	stride := p.Stride()
+	numRings := p.NumLinearRings()
+	if numRings == 0 {
+		return nil
+	}
+	rings := make([][]float64, numRings)
+	for i := range numRings {
+		if err := validateRing(p.LinearRing(i).FlatCoords(), stride); err != nil {
+			return err
+		}
+		rings[i] = xyCoords(p.LinearRing(i).FlatCoords(), stride)
+	}
+
+	if err := validateRingIntersections(rings); err != nil {
+		return err
+	}
+
+	shell := rings[0]
+	for _, hole := range rings[1:] {
+		c := pointNotOnRings(hole, shell)
+		if c != nil && LocatePointInRing(geom.XY, c, shell) == location.Exterior {
+			return &ValidationError{Reason: validity.HoleOutsideShell, Coord: c}
+		}
+	}
+
+	holes := rings[1:]
+	holeBoxes := make([]internal.Box, len(holes))
+	for i, hole := range holes {
+		holeBoxes[i] = ringBox(hole)
+	}
+	var err *ValidationError
+	internal.ForEachOverlappingPair(holeBoxes, func(i, j int) bool {
+		for _, pair := range [2][2][]float64{{holes[i], holes[j]}, {holes[j], holes[i]}} {
+			c := pointNotOnRings(pair[0], pair[1])
+			if c != nil && LocatePointInRing(geom.XY, c, pair[1]) == location.Interior {
+				err = &ValidationError{Reason: validity.NestedHoles, Coord: c}
+				return false
+			}
+		}
+		return true
+	})
+	return err
+}
+
+// validateRingIntersections checks that rings, which must each be valid,
+// only touch each other at single points and that those points do not
+// disconnect the interior.
+func validateRingIntersections(rings [][]float64) *ValidationError {
+	type ringPoint struct {
+		ring  int
+		point [2]float64
+	}
+	type ringPair struct{ a, b int }
+	pairPoints := make(map[ringPair][2]float64)
+	seen := make(map[ringPoint]struct{})
+	var touches []ringPoint
+	var err *ValidationError
+	forEachSegmentIntersection(rings, func(a, b segmentRef, result lineintersection.Result) bool {
+		if a.line == b.line {
+			return true
+		}
+		if result.Type() == lineintersection.CollinearIntersection || isProperIntersection(rings, a, b, result) {
+			err = &ValidationError{Reason: validity.SelfIntersection, Coord: intersectionCoord(result)}
+			return false
+		}
+		c := result.Intersection()[0]
+		point := [2]float64{c[0], c[1]}
+		pair := ringPair{a: min(a.line, b.line), b: max(a.line, b.line)}
+		if first, ok := pairPoints[pair]; !ok {
+			pairPoints[pair] = point
+		} else if first != point {
+			err = &ValidationError{Reason: validity.DisconnectedInterior, Coord: intersectionCoord(result)}
+			return false
+		}
+		for _, ring := range []int{a.line, b.line} {
+			touch := ringPoint{ring: ring, point: point}
+			if _, ok := seen[touch]; !ok {
+				seen[touch] = struct{}{}
+				touches = append(touches, touch)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	// Rings and the points where they touch form a graph. If this graph
+	// contains a cycle then the interior is disconnected.
+	parent := make([]int, len(rings))
+	for i := range parent {
+		parent[i] = i
+	}
+	pointIndex := make(map[[2]float64]int)
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, touch := range touches {
+		index, ok := pointIndex[touch.point]
+		if !ok {
+			index = len(parent)
+			pointIndex[touch.point] = index
+			parent = append(parent, index)
+		}
+		ra, rb := find(touch.ring), find(index)
+		if ra == rb {
+			return &ValidationError{Reason: validity.DisconnectedInterior, Coord: geom.Coord{touch.point[0], touch.point[1]}}
+		}
+		parent[ra] = rb
+	}
+	return nil
+}
+
+func validateMultiPolygon(mp *geom.MultiPolygon) *ValidationError {
+	numPolygons := mp.NumPolygons()
+	polygons := make([][][]float64, 0, numPolygons)
+	for i := range numPolygons {
+		p := mp.Polygon(i)
+		if err := validatePolygon(p); err != nil {
+			return err
+		}
+		if p.NumLinearRings() == 0 {
+			continue
+		}
+		rings := make([][]float64, p.NumLinearRings())
+		for j := range rings {
+			rings[j] = xyCoords(p.LinearRing(j).FlatCoords(), p.Stride())
+		}
+		polygons = append(polygons, rings)
+	}
+
+	var allRings [][]float64
+	var owner []int
+	for i, rings := range polygons {
+		allRings = append(allRings, rings...)
+		for range rings {
+			owner = append(owner, i)
+		}
+	}
+	var err *ValidationError
+	forEachSegmentIntersection(allRings, func(a, b segmentRef, result lineintersection.Result) bool {
+		if owner[a.line] == owner[b.line] {
+			return true
+		}
+		if result.Type() == lineintersection.CollinearIntersection || isProperIntersection(allRings, a, b, result) {
+			err = &ValidationError{Reason: validity.SelfIntersection, Coord: intersectionCoord(result)}
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	shellBoxes := make([]internal.Box, len(polygons))
+	for i, rings := range polygons {
+		shellBoxes[i] = ringBox(rings[0])
+	}
+	internal.ForEachOverlappingPair(shellBoxes, func(i, j int) bool {
+		for _, pair := range [2][2][][]float64{{polygons[i], polygons[j]}, {polygons[j], polygons[i]}} {
+			shell, polygon := pair[0][0], pair[1]
+			c := pointNotOnRings(shell, polygon...)
+			if c == nil {
+				err = &ValidationError{Reason: validity.NestedShells, Coord: geom.Coord{shell[0], shell[1]}}
+				return false
+			}
+			if locatePointInPolygonRings(c, polygon) == location.Interior {
+				err = &ValidationError{Reason: validity.NestedShells, Coord: c}
+				return false
+			}
+		}
+		return true
+	})
+	return err
+}
+
+// locatePointInPolygonRings returns the location of c relative to the
+// polygon defined by rings, whose first element is the shell.
+func locatePointInPolygonRings(c geom.Coord, rings [][]float64) location.Type {
+	loc := LocatePointInRing(geom.XY, c, rings[0])
+	if loc != location.Interior {
+		return loc
+	}
+	for _, hole := range rings[1:] {
+		switch LocatePointInRing(geom.XY, c, hole) {
+		case location.Interior:
+			return location.Exterior
+		case location.Boundary:
+			return location.Boundary
+		}
+	}
+	return location.Interior
+}
+
+// pointNotOnRings returns a vertex or segment midpoint of ring that does not
+// lie on the boundary of any of rings, or nil if there is no such point.
+func pointNotOnRings(ring []float64, rings ...[]float64) geom.Coord {
+	onRings := func(c geom.Coord) bool {
+		for _, other := range rings {
+			if LocatePointInRing(geom.XY, c, other) == location.Boundary {
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i < len(ring); i += 2 {
+		if c := (geom.Coord{ring[i], ring[i+1]}); !onRings(c) {
+			return c
+		}
+	}
+	for i := 2; i < len(ring); i += 2 {
+		if c := (geom.Coord{(ring[i-2] + ring[i]) / 2, (ring[i-1] + ring[i+1]) / 2}); !onRings(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+// isProperIntersection returns true if the point intersection result between
+// segments a and b is in the interior of both segments.
+func isProperIntersection(lines [][]float64, a, b segmentRef, result lineintersection.Result) bool {
+	if result.Type() != lineintersection.PointIntersection {
+		return false
+	}
+	c := result.Intersection()[0]
+	for _, ref := range [2]segmentRef{a, b} {
+		line := lines[ref.line]
+		if internal.Equal(line, 2*ref.index, c, 0) || internal.Equal(line, 2*ref.index+2, c, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func intersectionCoord(result lineintersection.Result) geom.Coord {
+	c := result.Intersection()[0]
+	return geom.Coord{c[0], c[1]}
+}
+
+func ringBox(ring []float64) internal.Box {
+	box := internal.Box{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	for i := 0; i < len(ring); i += 2 {
+		box.MinX = min(box.MinX, ring[i])
+		box.MinY = min(box.MinY, ring[i+1])
+		box.MaxX = max(box.MaxX, ring[i])
+		box.MaxY = max(box.MaxY, ring[i+1])
+	}
+	return box
+}
+
+// A segmentRef identifies the indexth segment of a line.
+type segmentRef struct {
+	line  int
+	index int
+}
+
+// forEachSegmentIntersection calls f for every pair of segments of lines,
+// which are xy coordinates without repeated points, that intersect. If f
+// returns false then the iteration stops.
+func forEachSegmentIntersection(lines [][]float64, f func(a, b segmentRef, result lineintersection.Result) bool) {
+	var refs []segmentRef
+	var boxes []internal.Box
+	for i, line := range lines {
+		for j := 0; j+3 < len(line); j += 2 {
+			refs = append(refs, segmentRef{line: i, index: j / 2})
+			boxes = append(boxes, internal.SegmentBox(line[j], line[j+1], line[j+2], line[j+3]))
+		}
+	}
+	strategy := lineintersector.RobustLineIntersector{}
+	internal.ForEachOverlappingPair(boxes, func(i, j int) bool {
+		a, b := refs[i], refs[j]
+		la, lb := lines[a.line], lines[b.line]
+		result := lineintersector.LineIntersectsLine(strategy,
+			geom.Coord(la[2*a.index:2*a.index+2]), geom.Coord(la[2*a.index+2:2*a.index+4]),
+			geom.Coord(lb[2*b.index:2*b.index+2]), geom.Coord(lb[2*b.index+2:2*b.index+4]))
+		if !result.HasIntersection() {
+			return true
+		}
+		if a.line > b.line || (a.line == b.line && a.index > b.index) {
+			a, b = b, a
+		}
+		return f(a, b, result)
+	})
+}
+
+// xyCoords returns the xy coordinates of flatCoords with consecutive repeated
+// points removed.
+func xyCoords(flatCoords []float64, stride int) []float64 {
+	xy := make([]float64, 0, 2*len(flatCoords)/max(stride, 1))
+	for i := 0; i < len(flatCoords); i += stride {
+		if n := len(xy); n >= 2 && xy[n-2] == flatCoords[i] && xy[n-1] == flatCoords[i+1] {
+			continue
+		}
+		xy = append(xy, flatCoords[i], flatCoords[i+1])
+	}
+	return xy
+}
@@ -0,0 +1,20 @@
+package xy_test
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+func ExampleValidate() {
+	bowTie := geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 10, 10, 0, 0, 10, 0, 0}, []int{10})
+	fmt.Println(xy.Validate(bowTie))
+	// Output: xy: RingSelfIntersection at [5 5]
+}
+
+func ExampleIsSimple() {
+	lineString := geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 10, 10, 0, 0, 10})
+	fmt.Println(xy.IsValid(lineString), xy.IsSimple(lineString))
+	// Output: true false
+}
@@ -0,0 +1,380 @@
+package xy_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+	"github.com/twpayne/go-geom/xy/validity"
+)
+
+func TestValidate(t *testing.T) {
+	square := []geom.Coord{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	for _, tc := range []struct {
+		name     string
+		g        geom.T
+		reason   validity.Type
+		location geom.Coord
+	}{
+		{
+			name:   "point",
+			g:      geom.NewPointFlat(geom.XY, []float64{1, 2}),
+			reason: validity.Valid,
+		},
+		{
+			name:   "empty_point",
+			g:      geom.NewPointEmpty(geom.XY),
+			reason: validity.Valid,
+		},
+		{
+			name:     "nan_point",
+			g:        geom.NewPointFlat(geom.XY, []float64{math.NaN(), 2}),
+			reason:   validity.InvalidCoordinate,
+			location: geom.Coord{math.NaN(), 2},
+		},
+		{
+			name:     "inf_multipoint",
+			g:        geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 1, math.Inf(1)}),
+			reason:   validity.InvalidCoordinate,
+			location: geom.Coord{1, math.Inf(1)},
+		},
+		{
+			name:   "self_intersecting_linestring",
+			g:      geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 10, 10, 0, 0, 10}),
+			reason: validity.Valid,
+		},
+		{
+			name:     "one_point_linestring",
+			g:        geom.NewLineStringFlat(geom.XYZ, []float64{1, 1, 0, 1, 1, 1}),
+			reason:   validity.TooFewPoints,
+			location: geom.Coord{1, 1},
+		},
+		{
+			name:     "multilinestring",
+			g:        geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 1, 1, 2, 2, 2, 2}, []int{4, 8}),
+			reason:   validity.TooFewPoints,
+			location: geom.Coord{2, 2},
+		},
+		{
+			name:   "linearring",
+			g:      geom.NewLinearRing(geom.XY).MustSetCoords(square),
+			reason: validity.Valid,
+		},
+		{
+			name:     "unclosed_linearring",
+			g:        geom.NewLinearRingFlat(geom.XY, []float64{0, 0, 1, 0, 1, 1, 0, 1}),
+			reason:   validity.RingNotClosed,
+			location: geom.Coord{0, 0},
+		},
+		{
+			name:   "polygon",
+			g:      geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{square}),
+			reason: validity.Valid,
+		},
+		{
+			name:   "empty_polygon",
+			g:      geom.NewPolygon(geom.XY),
+			reason: validity.Valid,
+		},
+		{
+			name: "polygon_with_repeated_points",
+			g: geom.NewPolygon(geom.XYM).MustSetCoords([][]geom.Coord{
+				{{0, 0, 1}, {10, 0, 2}, {10, 0, 3}, {10, 10, 4}, {0, 10, 5}, {0, 0, 1}},
+			}),
+			reason: validity.Valid,
+		},
+		{
+			name:     "unclosed_polygon",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 10}, []int{8}),
+			reason:   validity.RingNotClosed,
+			location: geom.Coord{0, 0},
+		},
+		{
+			name:     "too_few_points",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 0, 0}, []int{6}),
+			reason:   validity.TooFewPoints,
+			location: geom.Coord{0, 0},
+		},
+		{
+			name:     "too_few_distinct_points",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 0, 0, 0}, []int{8}),
+			reason:   validity.TooFewPoints,
+			location: geom.Coord{0, 0},
+		},
+		{
+			name:     "bow_tie",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 10, 10, 0, 0, 10, 0, 0}, []int{10}),
+			reason:   validity.RingSelfIntersection,
+			location: geom.Coord{5, 5},
+		},
+		{
+			name:     "self_touching_ring",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 5, 0, 0, 10, 0, 0}, []int{12}),
+			reason:   validity.RingSelfIntersection,
+			location: geom.Coord{5, 0},
+		},
+		{
+			name:     "spike",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 10, 20, 10, 10, 0, 10, 0, 0}, []int{14}),
+			reason:   validity.RingSelfIntersection,
+			location: geom.Coord{10, 10},
+		},
+		{
+			name: "polygon_with_hole",
+			g: geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{
+				square,
+				{{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}},
+			}),
+			reason: validity.Valid,
+		},
+		{
+			name: "hole_touching_shell",
+			g: geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{
+				square,
+				{{0, 5}, {5, 8}, {5, 2}, {0, 5}},
+			}),
+			reason: validity.Valid,
+		},
+		{
+			name: "hole_outside_shell",
+			g: geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{
+				square,
+				{{20, 20}, {20, 30}, {30, 30}, {20, 20}},
+			}),
+			reason:   validity.HoleOutsideShell,
+			location: geom.Coord{20, 20},
+		},
+		{
+			name: "hole_crossing_shell",
+			g: geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{
+				square,
+				{{5, 5}, {5, 15}, {8, 15}, {8, 5}, {5, 5}},
+			}),
+			reason:   validity.SelfIntersection,
+			location: geom.Coord{5, 10},
+		},
+		{
+			name: "nested_holes",
+			g: geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{
+				square,
+				{{1, 1}, {1, 9}, {9, 9}, {9, 1}, {1, 1}},
+				{{2, 2}, {2, 3}, {3, 3}, {2, 2}},
+			}),
+			reason:   validity.NestedHoles,
+			location: geom.Coord{2, 2},
+		},
+		{
+			name: "hole_touching_shell_twice",
+			g: geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{
+				square,
+				{{0, 5}, {5, 10}, {5, 5}, {0, 5}},
+			}),
+			reason:   validity.DisconnectedInterior,
+			location: geom.Coord{0, 5},
+		},
+		{
+			name: "holes_meeting_at_point",
+			g: geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{
+				square,
+				{{5, 5}, {2, 7}, {2, 3}, {5, 5}},
+				{{5, 5}, {8, 3}, {8, 7}, {5, 5}},
+				{{5, 5}, {6, 8}, {4, 8}, {5, 5}},
+			}),
+			reason: validity.Valid,
+		},
+		{
+			name: "holes_splitting_interior",
+			g: geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{
+				square,
+				{{0, 5}, {5, 6}, {5, 4}, {0, 5}},
+				{{5, 4}, {5, 6}, {10, 5}, {5, 4}},
+			}),
+			reason:   validity.DisconnectedInterior,
+			location: geom.Coord{5, 4},
+		},
+		{
+			name: "multipolygon",
+			g: geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+				{square},
+				{{{10, 10}, {20, 10}, {20, 20}, {10, 20}, {10, 10}}},
+			}),
+			reason: validity.Valid,
+		},
+		{
+			name: "multipolygon_shell_in_hole",
+			g: geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+				{square, {{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}},
+				{{{3, 3}, {7, 3}, {7, 7}, {3, 7}, {3, 3}}},
+			}),
+			reason: validity.Valid,
+		},
+		{
+			name: "nested_shells",
+			g: geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+				{square},
+				{{{3, 3}, {7, 3}, {7, 7}, {3, 7}, {3, 3}}},
+			}),
+			reason:   validity.NestedShells,
+			location: geom.Coord{3, 3},
+		},
+		{
+			name: "overlapping_shells",
+			g: geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+				{square},
+				{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}},
+			}),
+			reason:   validity.SelfIntersection,
+			location: geom.Coord{5, 10},
+		},
+		{
+			name: "shells_sharing_edge",
+			g: geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+				{square},
+				{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}},
+			}),
+			reason:   validity.SelfIntersection,
+			location: geom.Coord{10, 0},
+		},
+		{
+			name: "geometrycollection",
+			g: geom.NewGeometryCollection().MustPush(
+				geom.NewPointFlat(geom.XY, []float64{0, 0}),
+				geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 10, 10, 0, 0, 10, 0, 0}, []int{10}),
+			),
+			reason:   validity.RingSelfIntersection,
+			location: geom.Coord{5, 5},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := xy.Validate(tc.g)
+			if tc.reason == validity.Valid {
+				if err != nil {
+					t.Fatalf("expected valid but got %v", err)
+				}
+				if !xy.IsValid(tc.g) {
+					t.Error("expected IsValid to return true")
+				}
+				return
+			}
+			var validationErr *xy.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a *xy.ValidationError but got %v", err)
+			}
+			if validationErr.Reason != tc.reason {
+				t.Errorf("expected reason %v but got %v", tc.reason, validationErr.Reason)
+			}
+			if !validationErr.Coord.Equal(geom.XY, tc.location) {
+				t.Errorf("expected location %v but got %v", tc.location, validationErr.Coord)
+			}
+			if xy.IsValid(tc.g) {
+				t.Error("expected IsValid to return false")
+			}
+		})
+	}
+}
+
+func TestValidateSimple(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		g        geom.T
+		reason   validity.Type
+		location geom.Coord
+	}{
+		{
+			name:   "point",
+			g:      geom.NewPointFlat(geom.XY, []float64{1, 2}),
+			reason: validity.Valid,
+		},
+		{
+			name:     "repeated_multipoint",
+			g:        geom.NewMultiPointFlat(geom.XY, []float64{0, 0, 1, 1, 0, 0}),
+			reason:   validity.RepeatedPoint,
+			location: geom.Coord{0, 0},
+		},
+		{
+			name:   "linestring",
+			g:      geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10}),
+			reason: validity.Valid,
+		},
+		{
+			name:   "closed_linestring",
+			g:      geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 10, 0, 0}),
+			reason: validity.Valid,
+		},
+		{
+			name:     "crossing_linestring",
+			g:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 10, 10, 0, 0, 10}),
+			reason:   validity.SelfIntersection,
+			location: geom.Coord{5, 5},
+		},
+		{
+			name:     "backtracking_linestring",
+			g:        geom.NewLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 5, 0}),
+			reason:   validity.SelfIntersection,
+			location: geom.Coord{10, 0},
+		},
+		{
+			name:   "multilinestring_touching_at_endpoints",
+			g:      geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 10, 0, 10, 10}, []int{4, 8}),
+			reason: validity.Valid,
+		},
+		{
+			name:     "multilinestring_touching_interior",
+			g:        geom.NewMultiLineStringFlat(geom.XY, []float64{0, 0, 10, 0, 5, 0, 5, 10}, []int{4, 8}),
+			reason:   validity.SelfIntersection,
+			location: geom.Coord{5, 0},
+		},
+		{
+			name:     "polygon",
+			g:        geom.NewPolygonFlat(geom.XY, []float64{0, 0, 10, 10, 10, 0, 0, 10, 0, 0}, []int{10}),
+			reason:   validity.SelfIntersection,
+			location: geom.Coord{5, 5},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := xy.ValidateSimple(tc.g)
+			if tc.reason == validity.Valid {
+				if err != nil {
+					t.Fatalf("expected simple but got %v", err)
+				}
+				if !xy.IsSimple(tc.g) {
+					t.Error("expected IsSimple to return true")
+				}
+				return
+			}
+			var validationErr *xy.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a *xy.ValidationError but got %v", err)
+			}
+			if validationErr.Reason != tc.reason {
+				t.Errorf("expected reason %v but got %v", tc.reason, validationErr.Reason)
+			}
+			if !validationErr.Coord.Equal(geom.XY, tc.location) {
+				t.Errorf("expected location %v but got %v", tc.location, validationErr.Coord)
+			}
+		})
+	}
+}
+
+func TestValidateUnsupportedType(t *testing.T) {
+	var errUnsupportedType geom.ErrUnsupportedType
+	for _, g := range []geom.T{
+		nil,
+		geom.NewGeometryCollection().MustPush(nil),
+	} {
+		if err := xy.Validate(g); !errors.As(err, &errUnsupportedType) {
+			t.Errorf("expected geom.ErrUnsupportedType from Validate, got %v", err)
+		}
+		if err := xy.ValidateSimple(g); !errors.As(err, &errUnsupportedType) {
+			t.Errorf("expected geom.ErrUnsupportedType from ValidateSimple, got %v", err)
+		}
+		if xy.IsValid(g) {
+			t.Error("expected IsValid to return false")
+		}
+		if xy.IsSimple(g) {
+			t.Error("expected IsSimple to return false")
+		}
+	}
+}
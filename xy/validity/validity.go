@@ -0,0 +1,59 @@
+package validity
+
+import "fmt"
+
+// Type enumerates the reasons why a geometry can be invalid or non-simple.
+type Type int
+
+const (
+	// Valid indicates that no problem was found.
+	Valid Type = iota
+	// InvalidCoordinate indicates that a coordinate is NaN or infinite.
+	InvalidCoordinate
+	// TooFewPoints indicates that a component has too few distinct points,
+	// for example a LineString with fewer than two or a ring with fewer
+	// than three.
+	TooFewPoints
+	// RingNotClosed indicates that the first and last points of a ring differ.
+	RingNotClosed
+	// RingSelfIntersection indicates that a ring crosses or touches itself.
+	RingSelfIntersection
+	// SelfIntersection indicates that two components intersect in a way that
+	// is not permitted, for example two rings of a Polygon crossing.
+	SelfIntersection
+	// HoleOutsideShell indicates that a hole of a Polygon is not contained
+	// in its shell.
+	HoleOutsideShell
+	// NestedHoles indicates that a hole of a Polygon lies inside another hole.
+	NestedHoles
+	// DisconnectedInterior indicates that the rings of a Polygon touch in a
+	// way that splits its interior into more than one piece.
+	DisconnectedInterior
+	// NestedShells indicates that a Polygon of a MultiPolygon lies inside
+	// the interior of another.
+	NestedShells
+	// RepeatedPoint indicates that a MultiPoint contains the same point more
+	// than once.
+	RepeatedPoint
+)
+
+var labels = [...]string{
+	"Valid",
+	"InvalidCoordinate",
+	"TooFewPoints",
+	"RingNotClosed",
+	"RingSelfIntersection",
+	"SelfIntersection",
+	"HoleOutsideShell",
+	"NestedHoles",
+	"DisconnectedInterior",
+	"NestedShells",
+	"RepeatedPoint",
+}
+
+func (t Type) String() string {
+	if t < 0 || int(t) >= len(labels) {
+		return fmt.Sprintf("Unknown validity value: %d", int(t))
+	}
+	return labels[t]
+}